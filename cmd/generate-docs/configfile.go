@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFileName is looked up in the target directory, then the user's
+// home directory, when --config is not given.
+const configFileName = ".codebase-reviewer.yaml"
+
+// fileConfig is the schema of the config file. Pointer fields distinguish
+// "not set" from zero values so unset keys leave defaults alone.
+type fileConfig struct {
+	Verbose         *bool             `yaml:"verbose"`
+	Template        *string           `yaml:"template"`
+	AllowUnresolved *bool             `yaml:"allow_unresolved"`
+	MaxTokens       *int              `yaml:"max_tokens"`
+	Formats         []string          `yaml:"formats"`
+	Provider        *string           `yaml:"provider"`
+	OutputDir       *string           `yaml:"output_dir"`
+	MaxDepth        *int              `yaml:"max_depth"`
+	NoCache         *bool             `yaml:"no_cache"`
+	SkipDirs        []string          `yaml:"skip_dirs"`
+	LangMap         map[string]string `yaml:"lang_map"`
+}
+
+// applyConfigFile merges the config file into cfg. Values from the file
+// replace defaults but never flags given on the command line. A missing
+// default config file is not an error; a missing --config file is.
+func applyConfigFile(cfg *config, targetPath string) error {
+	path, err := locateConfigFile(cfg.configPath, targetPath)
+	if err != nil || path == "" {
+		return err
+	}
+
+	fc, err := loadFileConfig(path)
+	if err != nil {
+		return err
+	}
+	fc.mergeInto(cfg, explicitFlags(cfg.flags))
+	if cfg.maxDepth < 0 {
+		return fmt.Errorf("max_depth in %s must not be negative: %d", path, cfg.maxDepth)
+	}
+	return nil
+}
+
+// locateConfigFile returns the config file to load, or "" if there is none.
+func locateConfigFile(explicit, targetPath string) (string, error) {
+	if explicit != "" {
+		if _, err := os.Stat(explicit); err != nil {
+			return "", fmt.Errorf("config file %s: %w", explicit, err)
+		}
+		return explicit, nil
+	}
+
+	candidates := []string{filepath.Join(targetPath, configFileName)}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, configFileName))
+	}
+	for _, c := range candidates {
+		if _, err := os.Stat(c); err == nil {
+			return c, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("config file %s: %w", c, err)
+		}
+	}
+	return "", nil
+}
+
+// loadFileConfig reads and strictly parses the config file at path, so
+// misspelled keys are reported rather than silently ignored.
+func loadFileConfig(path string) (*fileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	fc := &fileConfig{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(fc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return fc, nil
+}
+
+// explicitFlags returns the names of flags set on the command line.
+func explicitFlags(fs *flag.FlagSet) map[string]bool {
+	set := make(map[string]bool)
+	if fs != nil {
+		fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	}
+	return set
+}
+
+// mergeInto copies values set in the file into cfg unless the
+// corresponding flag was given explicitly.
+func (fc *fileConfig) mergeInto(cfg *config, explicit map[string]bool) {
+	if fc.Verbose != nil && !explicit["v"] && !explicit["verbose"] {
+		cfg.verbose = *fc.Verbose
+	}
+	if fc.Template != nil && !explicit["template"] {
+		cfg.template = *fc.Template
+	}
+	if fc.AllowUnresolved != nil && !explicit["allow-unresolved"] {
+		cfg.allowUnresolved = *fc.AllowUnresolved
+	}
+	if fc.MaxTokens != nil && !explicit["max-tokens"] {
+		cfg.maxTokens = *fc.MaxTokens
+	}
+	if len(fc.Formats) > 0 && !explicit["formats"] {
+		cfg.formats = strings.Join(fc.Formats, ",")
+	}
+	if fc.Provider != nil && !explicit["provider"] {
+		cfg.provider = *fc.Provider
+	}
+	if fc.OutputDir != nil && !explicit["output-dir"] {
+		cfg.outputDir = *fc.OutputDir
+	}
+	if fc.MaxDepth != nil && !explicit["max-depth"] {
+		cfg.maxDepth = *fc.MaxDepth
+	}
+	if fc.NoCache != nil && !explicit["no-cache"] {
+		cfg.noCache = *fc.NoCache
+	}
+	cfg.skipDirs = fc.SkipDirs
+	cfg.langMap = fc.LangMap
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/prompt"
+)
+
+// writeConfig writes a config file named configFileName into dir.
+func writeConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, configFileName)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestApplyConfigFile_Precedence(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	target := t.TempDir()
+	writeConfig(t, target, `verbose: true
+max_tokens: 5000
+provider: claude
+skip_dirs: [third_party]
+lang_map:
+  .tpl: Go Template
+`)
+
+	tests := []struct {
+		name          string
+		args          []string
+		wantVerbose   bool
+		wantMaxTokens int
+		wantProvider  string
+	}{
+		{
+			name:          "file over default",
+			args:          []string{target},
+			wantVerbose:   true,
+			wantMaxTokens: 5000,
+			wantProvider:  "claude",
+		},
+		{
+			name:          "flag over file",
+			args:          []string{"--max-tokens", "42", "--provider", "openai", target},
+			wantVerbose:   true,
+			wantMaxTokens: 42,
+			wantProvider:  "openai",
+		},
+		{
+			name:          "explicit false flag over file",
+			args:          []string{"-v=false", target},
+			wantVerbose:   false,
+			wantMaxTokens: 5000,
+			wantProvider:  "claude",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := parseFlags(tt.args)
+			if err != nil {
+				t.Fatalf("parseFlags() error = %v", err)
+			}
+			if err := applyConfigFile(cfg, target); err != nil {
+				t.Fatalf("applyConfigFile() error = %v", err)
+			}
+			if cfg.verbose != tt.wantVerbose {
+				t.Errorf("verbose = %v, want %v", cfg.verbose, tt.wantVerbose)
+			}
+			if cfg.maxTokens != tt.wantMaxTokens {
+				t.Errorf("maxTokens = %d, want %d", cfg.maxTokens, tt.wantMaxTokens)
+			}
+			if cfg.provider != tt.wantProvider {
+				t.Errorf("provider = %q, want %q", cfg.provider, tt.wantProvider)
+			}
+			if !reflect.DeepEqual(cfg.skipDirs, []string{"third_party"}) {
+				t.Errorf("skipDirs = %v, want [third_party]", cfg.skipDirs)
+			}
+			if cfg.langMap[".tpl"] != "Go Template" {
+				t.Errorf("langMap = %v, want .tpl mapped", cfg.langMap)
+			}
+		})
+	}
+}
+
+func TestApplyConfigFile_MissingIsNoOp(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg, err := parseFlags([]string{"/some/path"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	before := *cfg
+
+	if err := applyConfigFile(cfg, t.TempDir()); err != nil {
+		t.Fatalf("applyConfigFile() error = %v", err)
+	}
+	if cfg.maxTokens != prompt.DefaultMaxTokens || cfg.verbose != before.verbose || cfg.provider != before.provider {
+		t.Errorf("config changed without a config file: %+v", cfg)
+	}
+}
+
+func TestApplyConfigFile_HomeFallback(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeConfig(t, home, "max_depth: 3\n")
+
+	cfg, _ := parseFlags([]string{"/some/path"})
+	if err := applyConfigFile(cfg, t.TempDir()); err != nil {
+		t.Fatalf("applyConfigFile() error = %v", err)
+	}
+	if cfg.maxDepth != 3 {
+		t.Errorf("maxDepth = %d, want 3 from home config", cfg.maxDepth)
+	}
+}
+
+func TestApplyConfigFile_Errors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		args    []string
+		wantErr string
+	}{
+		{name: "explicit path missing", args: []string{"--config", filepath.Join(dir, "nope.yaml"), dir}, wantErr: "nope.yaml"},
+		{name: "unknown key", content: "verbos: true\n", args: []string{dir}, wantErr: "verbos"},
+		{name: "negative max depth", content: "max_depth: -1\n", args: []string{dir}, wantErr: "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.content != "" {
+				writeConfig(t, dir, tt.content)
+			}
+			cfg, err := parseFlags(tt.args)
+			if err != nil {
+				t.Fatalf("parseFlags() error = %v", err)
+			}
+			err = applyConfigFile(cfg, dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("applyConfigFile() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
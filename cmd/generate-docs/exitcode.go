@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/learnings"
+)
+
+// Process exit codes, documented under EXIT CODES in --help so that CI can
+// gate on them.
+const (
+	exitOK       = 0
+	exitInternal = 1
+	exitObsolete = 2
+	exitUsage    = 3
+)
+
+// errUsage is wrapped by errors caused by how the tool was invoked rather
+// than by the codebase or the environment.
+var errUsage = errors.New("invalid usage")
+
+// exitCode maps err to the process exit code for its failure class.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, learnings.ErrObsolete):
+		return exitObsolete
+	case errors.Is(err, errUsage),
+		errors.Is(err, scanner.ErrPathNotFound),
+		errors.Is(err, scanner.ErrNoRepos):
+		return exitUsage
+	}
+	return exitInternal
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/learnings"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, exitOK},
+		{"obsolete", fmt.Errorf("%w: score 0.80", learnings.ErrObsolete), exitObsolete},
+		{"usage", fmt.Errorf("%w: no target path provided", errUsage), exitUsage},
+		{"path not found", fmt.Errorf("%w: /x", scanner.ErrPathNotFound), exitUsage},
+		{"no repos", fmt.Errorf("%w: all excluded", scanner.ErrNoRepos), exitUsage},
+		{"not readable", fmt.Errorf("walk: %w", scanner.ErrNotReadable), exitInternal},
+		{"cancelled", fmt.Errorf("scan: %w", context.Canceled), exitInternal},
+		{"other", errors.New("disk full"), exitInternal},
+		{"unknown format", reviewerOptionsErr(t, "--formats", "bogus"), exitUsage},
+		{"unknown provider", reviewerOptionsErr(t, "--provider", "bogus"), exitUsage},
+		{"unknown report", reviewerOptionsErr(t, "--report", "xml"), exitUsage},
+		{"unknown language", reviewerOptionsErr(t, "--only-lang", "klingon"), exitUsage},
+		{"relative output dir", reviewerOptionsErr(t, "--output-dir", "out"), exitUsage},
+		{"unknown --only name", selectErr(&config{only: "nosuch"}), exitUsage},
+		{"--select without a terminal", selectErr(&config{selectRepos: true}), exitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// reviewerOptionsErr returns the error reviewerOptions reports for args.
+func reviewerOptionsErr(t *testing.T, args ...string) error {
+	t.Helper()
+	cfg, err := parseFlags(args)
+	if err != nil {
+		t.Fatalf("parseFlags(%q) error = %v", args, err)
+	}
+	_, err = cfg.reviewerOptions("/x", logger.NewWithWriter(io.Discard, false))
+	return err
+}
+
+// selectErr returns the error selectRepositories reports for cfg without a
+// terminal.
+func selectErr(cfg *config) error {
+	_, err := selectRepositories(cfg, discovered, terminal{}, logger.NewWithWriter(io.Discard, false))
+	return err
+}
+
+func TestExitCode_ReviewAndUsagePaths(t *testing.T) {
+	if _, err := resolveTargetPath(nil); exitCode(err) != exitUsage {
+		t.Errorf("missing target: exitCode(%v) = %d, want %d", err, exitCode(err), exitUsage)
+	}
+
+	dir := t.TempDir()
+	l := learnings.NewLearnings()
+	l.CodebaseChanges.LanguageChanges.NewLanguages = []string{"Rust", "Zig"}
+	if err := l.Save(filepath.Join(dir, learningsFileName)); err != nil {
+		t.Fatal(err)
+	}
+
+	err := reviewObsolescence(dir, 0.1, logger.NewWithWriter(io.Discard, false))
+	if got := exitCode(err); got != exitObsolete {
+		t.Errorf("obsolete review: exitCode(%v) = %d, want %d", err, got, exitObsolete)
+	}
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/bordenet/codebase-reviewer/internal/prompt"
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/learnings"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+	"github.com/bordenet/codebase-reviewer/pkg/reviewer"
+)
+
+// config holds CLI configuration parsed from flags.
+type config struct {
+	verbose         bool
+	quiet           bool
+	scorch          bool
+	review          bool
+	help            bool
+	version         bool
+	template        string
+	allowUnresolved bool
+	maxTokens       int
+	formats         string
+	provider        string
+	outputDir       string
+	dryRun          bool
+	maxDepth        int
+	followSymlinks  bool
+	includeHidden   bool
+	noCache         bool
+	incremental     bool
+	configPath      string
+	report          string
+	reviewFormat    string
+	obsThreshold    float64
+	watch           bool
+	watchInterval   time.Duration
+	selectRepos     bool
+	only            string
+	excludeRepos    stringList
+	minFiles        int
+	includeEmpty    bool
+	since           string
+	perRepo         bool
+	summary         bool
+	logFile         string
+	logAppend       bool
+	trace           bool
+	memInterval     time.Duration
+	validateTmpl    string
+	listLangs       bool
+	initLearnings   string
+	reposFrom       string
+	largeFileSize   byteSize
+	walkWorkers     int
+	resume          bool
+	anonymize       bool
+	scanSecrets     bool
+	onlyLangs       string
+
+	// skipDirs and langMap come only from the config file.
+	skipDirs []string
+	langMap  map[string]string
+
+	// args holds the positional arguments remaining after flag parsing.
+	args []string
+	// flags is the flag set the configuration was parsed from.
+	flags *flag.FlagSet
+}
+
+// parseFlags parses command-line arguments (excluding the program name)
+// and returns configuration. Parse errors are reported to stderr by the
+// flag set before being returned.
+func parseFlags(args []string) (*config, error) {
+	cfg := &config{largeFileSize: byteSize(scanner.DefaultLargeFileThreshold)}
+	fs := flag.NewFlagSet(appName, flag.ContinueOnError)
+	fs.BoolVar(&cfg.verbose, "v", false, "Enable verbose logging")
+	fs.BoolVar(&cfg.verbose, "verbose", false, "Enable verbose logging")
+	fs.BoolVar(&cfg.quiet, "q", false, "Only log warnings and errors")
+	fs.BoolVar(&cfg.quiet, "quiet", false, "Only log warnings and errors")
+	fs.BoolVar(&cfg.trace, "trace", false, "Enable trace logging, including every file visited (implies -v)")
+	fs.BoolVar(&cfg.scorch, "scorch", false, "Force full rebuild of reference materials and Phase 2 tools")
+	fs.BoolVar(&cfg.review, "review", false, "Review existing Phase 2 tools for viability")
+	fs.StringVar(&cfg.template, "template", "", "Path to a custom Phase 1 prompt template (YAML)")
+	fs.BoolVar(&cfg.allowUnresolved, "allow-unresolved", false, "Keep unknown {{PLACEHOLDER}} tokens instead of failing")
+	fs.IntVar(&cfg.maxTokens, "max-tokens", prompt.DefaultMaxTokens, "Warn when the estimated prompt size exceeds this many tokens")
+	fs.StringVar(&cfg.formats, "formats", strings.Join(prompt.DefaultFormats, ","), "Comma-separated prompt output formats (md, yaml, json)")
+	fs.StringVar(&cfg.provider, "provider", prompt.ProviderGeneric, "Prompt formatting for an LLM provider (generic, claude, openai)")
+	fs.StringVar(&cfg.outputDir, "output-dir", "", "Base directory for outputs (default "+reviewer.DefaultOutputBase+")")
+	fs.BoolVar(&cfg.dryRun, "dry-run", false, "Log planned outputs without writing or removing any files")
+	fs.IntVar(&cfg.maxDepth, "max-depth", 0, "Maximum directory depth to search for repositories (0 = unlimited)")
+	fs.BoolVar(&cfg.followSymlinks, "follow-symlinks", false, "Descend into symlinked directories during discovery and analysis")
+	fs.BoolVar(&cfg.includeHidden, "include-hidden", false, "Analyze hidden directories such as .github (never .git)")
+	fs.IntVar(&cfg.walkWorkers, "walk-workers", runtime.NumCPU(), "Goroutines walking each repository during analysis (1 = sequential)")
+	fs.Var(&cfg.largeFileSize, "large-file-threshold", "Warn about files larger than this size, such as 5MB or 500KB")
+	fs.StringVar(&cfg.onlyLangs, "only-lang", "", "Comma-separated languages to count individually (e.g. go,rust); other languages are tallied as \"other\"")
+	fs.BoolVar(&cfg.scanSecrets, "scan-secrets", false, "Count potential secrets such as AWS keys and private keys in the prompt and learnings.yaml")
+	fs.BoolVar(&cfg.noCache, "no-cache", false, "Ignore and do not update the cached analysis")
+	fs.BoolVar(&cfg.incremental, "incremental", false, "Re-analyze only files git reports as changed, merged into the cached analysis")
+	fs.BoolVar(&cfg.resume, "resume", false, "Skip the repositories an interrupted run already analyzed, as recorded in its checkpoint")
+	fs.StringVar(&cfg.report, "report", "", "Also write a machine-readable scan report (json)")
+	fs.StringVar(&cfg.reviewFormat, "format", "", "With --review, also write findings in this format (sarif)")
+	fs.Float64Var(&cfg.obsThreshold, "obsolescence-threshold", learnings.DefaultObsolescenceThreshold, "With --review, exit non-zero when the obsolescence score (0..1) reaches this")
+	fs.BoolVar(&cfg.watch, "watch", false, "Keep running and regenerate when files in the target change")
+	fs.DurationVar(&cfg.watchInterval, "watch-interval", defaultWatchInterval, "Quiet period after a change before --watch regenerates")
+	fs.StringVar(&cfg.reposFrom, "repos-from", "", "Analyze the repositories listed in this file (one per line or a JSON array) instead of searching")
+	fs.BoolVar(&cfg.selectRepos, "select", false, "Interactively choose which discovered repositories to include")
+	fs.StringVar(&cfg.only, "only", "", "Comma-separated names of the discovered repositories to include")
+	fs.Var(&cfg.excludeRepos, "exclude-repo", "Skip repositories whose name or relative path matches this glob (repeatable)")
+	fs.StringVar(&cfg.since, "since", "", "Skip repositories whose last commit predates this date (2024-01-01) or duration ago (30d)")
+	fs.BoolVar(&cfg.includeEmpty, "include-empty", false, "Keep repositories with nothing but .git in the prompt")
+	fs.IntVar(&cfg.minFiles, "min-files", 0, "Leave repositories with fewer files than this out of the prompt (0 = keep all)")
+	fs.BoolVar(&cfg.anonymize, "anonymize", false, "Replace paths, repository names, and remotes in the outputs with tokens, writing the mapping to "+prompt.AnonymizationMapFileName)
+	fs.BoolVar(&cfg.perRepo, "per-repo", false, "Write a separate prompt for each repository plus an index.md")
+	fs.BoolVar(&cfg.summary, "summary", false, "Print a table of the analyzed repositories to stdout")
+	fs.DurationVar(&cfg.memInterval, "memory-interval", defaultMemoryInterval, "How often to sample heap use for the run's memory_peak_mb metric")
+	fs.StringVar(&cfg.logFile, "log-file", "", "Also write log output to this file (truncated unless --log-append)")
+	fs.BoolVar(&cfg.logAppend, "log-append", false, "Append to --log-file instead of truncating it")
+	fs.StringVar(&cfg.validateTmpl, "validate-template", "", "Check a prompt template for required sections and exit")
+	fs.BoolVar(&cfg.listLangs, "list-languages", false, "Print the effective extension-to-language mapping and exit")
+	fs.StringVar(&cfg.initLearnings, "init-learnings", "", "Write a commented learnings.yaml scaffold into this directory and exit")
+	fs.StringVar(&cfg.configPath, "config", "", "Path to a config file (default: "+configFileName+" in the target or home directory)")
+	fs.BoolVar(&cfg.version, "version", false, "Print version and build information")
+	fs.BoolVar(&cfg.help, "h", false, "Show help message")
+	fs.BoolVar(&cfg.help, "help", false, "Show help message")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if err := validateFlags(cfg); err != nil {
+		fmt.Fprintln(fs.Output(), err)
+		return nil, err
+	}
+	if cfg.trace {
+		cfg.verbose = true
+	}
+	cfg.args = fs.Args()
+	cfg.flags = fs
+	return cfg, nil
+}
+
+// validateFlags checks flag values and combinations that the flag package
+// cannot express.
+func validateFlags(cfg *config) error {
+	if cfg.maxDepth < 0 {
+		return fmt.Errorf("--max-depth must not be negative: %d", cfg.maxDepth)
+	}
+	if cfg.walkWorkers < 1 {
+		return fmt.Errorf("--walk-workers must be at least 1: %d", cfg.walkWorkers)
+	}
+	if cfg.minFiles < 0 {
+		return fmt.Errorf("--min-files must not be negative: %d", cfg.minFiles)
+	}
+	if cfg.obsThreshold < 0 || cfg.obsThreshold > 1 {
+		return fmt.Errorf("--obsolescence-threshold must be between 0 and 1: %g", cfg.obsThreshold)
+	}
+	if cfg.watchInterval <= 0 {
+		return fmt.Errorf("--watch-interval must be positive: %s", cfg.watchInterval)
+	}
+	if cfg.since != "" {
+		if _, err := parseSince(cfg.since, time.Now()); err != nil {
+			return err
+		}
+	}
+	if cfg.memInterval <= 0 {
+		return fmt.Errorf("--memory-interval must be positive: %s", cfg.memInterval)
+	}
+	if err := validateGlobs(cfg.excludeRepos); err != nil {
+		return err
+	}
+	if cfg.quiet && (cfg.verbose || cfg.trace) {
+		return fmt.Errorf("--quiet cannot be used with --verbose or --trace")
+	}
+	if cfg.logAppend && cfg.logFile == "" {
+		return fmt.Errorf("--log-append requires --log-file")
+	}
+	if cfg.incremental && cfg.noCache {
+		return fmt.Errorf("--incremental requires the analysis cache and cannot be used with --no-cache")
+	}
+	if cfg.resume && cfg.scorch {
+		return fmt.Errorf("--resume cannot be used with --scorch, which discards the checkpoint")
+	}
+	if cfg.perRepo && cfg.anonymize {
+		return fmt.Errorf("--anonymize cannot be used with --per-repo, whose index names every repository")
+	}
+	if cfg.perRepo && cfg.review {
+		return fmt.Errorf("--per-repo cannot be used with --review")
+	}
+	if cfg.reposFrom != "" && cfg.maxDepth > 0 {
+		return fmt.Errorf("--max-depth has no effect with --repos-from, which skips the repository search")
+	}
+	if cfg.selectRepos && cfg.only != "" {
+		return fmt.Errorf("--select and --only cannot be used together")
+	}
+	if cfg.selectRepos && cfg.watch {
+		return fmt.Errorf("--select cannot be used with --watch; use --only name1,name2")
+	}
+	if cfg.reviewFormat != "" {
+		if cfg.reviewFormat != reviewFormatSARIF {
+			return fmt.Errorf("unknown --format %q (supported: %s)", cfg.reviewFormat, reviewFormatSARIF)
+		}
+		if !cfg.review {
+			return fmt.Errorf("--format %s requires --review", cfg.reviewFormat)
+		}
+	}
+	return nil
+}
+
+// scanOptions returns the scanner options selected by the flags, reporting
+// scan progress to log.
+func (c *config) scanOptions(log *logger.Logger) scanner.ScanOptions {
+	return scanner.ScanOptions{
+		MaxDepth:           c.maxDepth,
+		OnProgress:         newProgressLogger(log, progressLogInterval, time.Now),
+		SkipDirs:           c.skipDirs,
+		LangMap:            c.langMap,
+		FollowSymlinks:     c.followSymlinks,
+		IncludeHidden:      c.includeHidden,
+		LargeFileThreshold: int64(c.largeFileSize),
+		WalkWorkers:        c.walkWorkers,
+		ScanSecrets:        c.scanSecrets,
+	}
+}
+
+// reviewerOptions returns the library options selected by the flags for
+// reviewing absPath, logging to log. Invalid flag values are reported as
+// usage errors.
+func (c *config) reviewerOptions(absPath string, log *logger.Logger) (reviewer.Options, error) {
+	formats, err := prompt.ParseFormats(c.formats)
+	if err != nil {
+		return reviewer.Options{}, fmt.Errorf("%w: %w", errUsage, err)
+	}
+	provider, err := prompt.ParseProvider(c.provider)
+	if err != nil {
+		return reviewer.Options{}, fmt.Errorf("%w: %w", errUsage, err)
+	}
+	report, err := prompt.ParseReport(c.report)
+	if err != nil {
+		return reviewer.Options{}, fmt.Errorf("%w: %w", errUsage, err)
+	}
+	// The output base may also come from the config file, so it is checked
+	// here rather than in validateFlags.
+	if c.outputDir != "" && !filepath.IsAbs(c.outputDir) {
+		return reviewer.Options{}, fmt.Errorf("%w: --output-dir must be an absolute path: %s", errUsage, c.outputDir)
+	}
+
+	opts := reviewer.Options{
+		PromptOptions: prompt.Options{
+			Verbose:         c.verbose,
+			Scorch:          c.scorch,
+			TemplatePath:    c.template,
+			AllowUnresolved: c.allowUnresolved,
+			MaxTokens:       c.maxTokens,
+			Formats:         formats,
+			Provider:        provider,
+			DryRun:          c.dryRun,
+			Scan:            c.scanOptions(log),
+			NoCache:         c.noCache,
+			Incremental:     c.incremental,
+			Report:          report,
+			MinFiles:        c.minFiles,
+			IncludeEmpty:    c.includeEmpty,
+			Anonymize:       c.anonymize,
+			Resume:          c.resume,
+		},
+		Target:     absPath,
+		OutputBase: c.outputDir,
+		Log:        log,
+		Filter: func(repos []scanner.Repository) ([]scanner.Repository, error) {
+			return filterRepositories(c, repos, log)
+		},
+	}
+	if c.onlyLangs != "" {
+		opts.Scan.OnlyLanguages, err = resolveLanguages(c.onlyLangs, opts.Scan.LanguageMap())
+		if err != nil {
+			return reviewer.Options{}, err
+		}
+	}
+	if c.summary {
+		opts.Summary = os.Stdout
+	}
+	return opts, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// listLanguages writes the effective extension-to-language mapping, the
+// built-in entries plus lang_map from the config file that applies to the
+// target (the current directory when none is given), one sorted entry per
+// line.
+func listLanguages(cfg *config, w io.Writer) error {
+	target := "."
+	if len(cfg.args) > 0 {
+		target = cfg.args[0]
+	}
+	if err := applyConfigFile(cfg, target); err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	langs := cfg.scanOptions(nil).LanguageMap()
+	exts := make([]string, 0, len(langs))
+	for ext := range langs {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, ext := range exts {
+		fmt.Fprintf(tw, "%s\t%s\n", ext, langs[ext])
+	}
+	return tw.Flush()
+}
+
+// resolveLanguages maps the comma-separated --only-lang list to the
+// language names used in langs, matched in any case, dropping duplicates.
+// A name no extension maps to is a usage error.
+func resolveLanguages(list string, langs map[string]string) ([]string, error) {
+	known := make(map[string]string, len(langs))
+	for _, lang := range langs {
+		known[strings.ToLower(lang)] = lang
+	}
+
+	var resolved []string
+	seen := make(map[string]bool)
+	for _, name := range splitList(list) {
+		lang, ok := known[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown language %q in --only-lang (see --list-languages)", errUsage, name)
+		}
+		if !seen[lang] {
+			seen[lang] = true
+			resolved = append(resolved, lang)
+		}
+	}
+	return resolved, nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestListLanguages(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	target := t.TempDir()
+	writeConfig(t, target, "lang_map:\n  tpl: Go Template\n  .js: ECMAScript\n")
+
+	cfg, err := parseFlags([]string{"--list-languages", target})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	var out bytes.Buffer
+	if err := listLanguages(cfg, &out); err != nil {
+		t.Fatalf("listLanguages() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"default", `(?m)^\.go +Go$`},
+		{"added override", `(?m)^\.tpl +Go Template$`},
+		{"replaced default", `(?m)^\.js +ECMAScript$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !regexp.MustCompile(tt.line).MatchString(out.String()) {
+				t.Errorf("output does not match %s:\n%s", tt.line, out.String())
+			}
+		})
+	}
+	if regexp.MustCompile(`(?m)^\.js +JavaScript$`).MatchString(out.String()) {
+		t.Error("overridden default .js JavaScript still listed")
+	}
+}
+
+func TestListLanguages_Sorted(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg, err := parseFlags([]string{"--list-languages", t.TempDir()})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	var out bytes.Buffer
+	if err := listLanguages(cfg, &out); err != nil {
+		t.Fatalf("listLanguages() error = %v", err)
+	}
+
+	var prev string
+	for _, line := range bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n")) {
+		ext := string(bytes.Fields(line)[0])
+		if ext < prev {
+			t.Errorf("%q listed after %q", ext, prev)
+		}
+		prev = ext
+	}
+}
+
+func TestListLanguages_BadConfig(t *testing.T) {
+	cfg, err := parseFlags([]string{"--list-languages", "--config", "/nonexistent/config.yaml"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	err = listLanguages(cfg, &bytes.Buffer{})
+	if !errors.Is(err, errUsage) {
+		t.Errorf("listLanguages() error = %v, want errUsage", err)
+	}
+}
+
+func TestResolveLanguages(t *testing.T) {
+	langs := map[string]string{".go": "Go", ".rs": "Rust", ".c": "C", ".h": "C", ".cpp": "C++"}
+	tests := []struct {
+		name    string
+		list    string
+		want    []string
+		wantErr bool
+	}{
+		{"case-insensitive", "go,RUST", []string{"Go", "Rust"}, false},
+		{"trims and dedupes", " c , C,c++ ", []string{"C", "C++"}, false},
+		{"unknown", "go,cobol", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveLanguages(tt.list, langs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveLanguages(%q) error = %v, wantErr %v", tt.list, err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, errUsage) {
+				t.Errorf("resolveLanguages(%q) error = %v, want a usage error", tt.list, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveLanguages(%q) = %v, want %v", tt.list, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReviewerOptions_OnlyLang(t *testing.T) {
+	cfg, err := parseFlags([]string{"--only-lang", "go,rust", "/x"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	opts, err := cfg.reviewerOptions("/x", logger.New(false))
+	if err != nil {
+		t.Fatalf("reviewerOptions() error = %v", err)
+	}
+	if want := []string{"Go", "Rust"}; !reflect.DeepEqual(opts.Scan.OnlyLanguages, want) {
+		t.Errorf("OnlyLanguages = %v, want %v", opts.Scan.OnlyLanguages, want)
+	}
+
+	cfg, err = parseFlags([]string{"--only-lang", "klingon", "/x"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	if _, err := cfg.reviewerOptions("/x", logger.New(false)); !errors.Is(err, errUsage) {
+		t.Errorf("reviewerOptions() with an unknown language error = %v, want a usage error", err)
+	}
+}
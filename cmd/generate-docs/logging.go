@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// openLogger returns the logger selected by the flags: stdout alone, or
+// stdout teed into --log-file, which is truncated unless --log-append is
+// set. --trace lowers the level to trace and --quiet raises it to warn. The returned close function
+// releases the log file, if any.
+func openLogger(cfg *config, stdout io.Writer) (*logger.Logger, func() error, error) {
+	if cfg.logFile == "" {
+		return withLevel(logger.New(cfg.verbose), cfg), func() error { return nil }, nil
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if cfg.logAppend {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(cfg.logFile, flags, 0644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	return withLevel(logger.NewTee(cfg.verbose, stdout, f), cfg), f.Close, nil
+}
+
+// withLevel applies the --trace or --quiet level to log.
+func withLevel(log *logger.Logger, cfg *config) *logger.Logger {
+	switch {
+	case cfg.trace:
+		log.SetLevel(logger.LevelTrace)
+	case cfg.quiet:
+		log.SetLevel(logger.LevelWarn)
+	}
+	return log
+}
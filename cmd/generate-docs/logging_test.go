@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenLogger_LogFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		append    bool
+		wantStale bool
+	}{
+		{"truncates by default", false, false},
+		{"appends with --log-append", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "run.log")
+			if err := os.WriteFile(path, []byte("previous run\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var stdout bytes.Buffer
+			log, closeLog, err := openLogger(&config{logFile: path, logAppend: tt.append}, &stdout)
+			if err != nil {
+				t.Fatalf("openLogger() error = %v", err)
+			}
+			log.Info("scan started")
+			if err := closeLog(); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(stdout.String(), "scan started") || !strings.Contains(string(data), "scan started") {
+				t.Errorf("line should reach stdout and the file; stdout %q, file %q", stdout.String(), data)
+			}
+			if got := strings.Contains(string(data), "previous run"); got != tt.wantStale {
+				t.Errorf("file kept earlier contents = %v, want %v", got, tt.wantStale)
+			}
+		})
+	}
+}
+
+func TestParseFlags_LogAppendRequiresLogFile(t *testing.T) {
+	if _, err := parseFlags([]string{"--log-append", "/x"}); err == nil || !strings.Contains(err.Error(), "requires --log-file") {
+		t.Errorf("parseFlags() error = %v, want --log-append to require --log-file", err)
+	}
+}
+
+func TestOpenLogger_Trace(t *testing.T) {
+	cfg, err := parseFlags([]string{"--trace", "--log-file", filepath.Join(t.TempDir(), "run.log"), "/x"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	if !cfg.verbose {
+		t.Error("--trace should imply verbose")
+	}
+
+	var stdout bytes.Buffer
+	log, closeLog, err := openLogger(cfg, &stdout)
+	if err != nil {
+		t.Fatalf("openLogger() error = %v", err)
+	}
+	defer closeLog()
+
+	log.Trace("visiting main.go")
+	if !strings.Contains(stdout.String(), "[TRACE] visiting main.go") {
+		t.Errorf("trace line should be emitted with --trace, got %q", stdout.String())
+	}
+}
+
+func TestParseFlags_QuietConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"verbose", []string{"--quiet", "--verbose", "/x"}},
+		{"short verbose", []string{"-q", "-v", "/x"}},
+		{"trace", []string{"--quiet", "--trace", "/x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseFlags(tt.args); err == nil || !strings.Contains(err.Error(), "--quiet cannot be used") {
+				t.Errorf("parseFlags(%v) error = %v, want --quiet conflict", tt.args, err)
+			}
+		})
+	}
+}
+
+func TestOpenLogger_Quiet(t *testing.T) {
+	cfg, err := parseFlags([]string{"--quiet", "--log-file", filepath.Join(t.TempDir(), "run.log"), "/x"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+
+	var stdout bytes.Buffer
+	log, closeLog, err := openLogger(cfg, &stdout)
+	if err != nil {
+		t.Fatalf("openLogger() error = %v", err)
+	}
+	defer closeLog()
+
+	log.Info("scan started")
+	log.Warn("cache is stale")
+	if strings.Contains(stdout.String(), "scan started") {
+		t.Errorf("INFO line should be suppressed with --quiet, got %q", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "[WARN] cache is stale") {
+		t.Errorf("WARN line should still be emitted with --quiet, got %q", stdout.String())
+	}
+
+	var summary bytes.Buffer
+	printCompletionMessage(&summary, "/out/phase1-llm-prompt.md", 1234, "/out", log)
+	if !strings.Contains(summary.String(), "Phase 1 complete! Prompt: /out/phase1-llm-prompt.md (~1234 tokens)") {
+		t.Errorf("completion summary should be printed regardless of --quiet, got %q", summary.String())
+	}
+	if strings.Contains(stdout.String(), "Next steps") {
+		t.Errorf("next steps are INFO and should be suppressed, got %q", stdout.String())
+	}
+}
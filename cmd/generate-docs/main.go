@@ -1,14 +1,24 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/bordenet/codebase-reviewer/internal/prompt"
 	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/learnings"
 	"github.com/bordenet/codebase-reviewer/pkg/logger"
+	"github.com/bordenet/codebase-reviewer/pkg/reviewer"
 )
 
 const (
@@ -16,55 +26,128 @@ const (
 	appName = "generate-docs"
 )
 
-// config holds CLI configuration parsed from flags.
-type config struct {
-	verbose bool
-	scorch  bool
-	review  bool
-	help    bool
-}
-
-// parseFlags parses command-line flags and returns configuration.
-func parseFlags() *config {
-	cfg := &config{}
-	flag.BoolVar(&cfg.verbose, "v", false, "Enable verbose logging")
-	flag.BoolVar(&cfg.verbose, "verbose", false, "Enable verbose logging")
-	flag.BoolVar(&cfg.scorch, "scorch", false, "Force full rebuild of reference materials and Phase 2 tools")
-	flag.BoolVar(&cfg.review, "review", false, "Review existing Phase 2 tools for viability")
-	flag.BoolVar(&cfg.help, "h", false, "Show help message")
-	flag.BoolVar(&cfg.help, "help", false, "Show help message")
-	flag.Parse()
-	return cfg
-}
+// Build metadata, injected at link time:
+//
+//	go build -ldflags "-X main.commit=$(git rev-parse --short HEAD) -X main.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	commit    = "unknown"
+	buildDate = "unknown"
+)
 
 func main() {
-	cfg := parseFlags()
+	cfg, err := parseFlags(os.Args[1:])
+	if err != nil {
+		// The flag set has already reported the problem and usage.
+		os.Exit(exitUsage)
+	}
 
-	if cfg.help {
-		printHelp()
-		os.Exit(0)
+	if handleInfoFlags(cfg, os.Stdout) {
+		os.Exit(exitOK)
+	}
+
+	if cfg.validateTmpl != "" {
+		os.Exit(validateTemplateFile(cfg.validateTmpl, os.Stdout))
 	}
 
-	log := logger.New(cfg.verbose)
+	if cfg.initLearnings != "" {
+		if err := initLearnings(cfg.initLearnings, os.Stdout); err != nil {
+			exitOnError(err, logger.New(cfg.verbose))
+		}
+		os.Exit(exitOK)
+	}
+
+	if cfg.listLangs {
+		if err := listLanguages(cfg, os.Stdout); err != nil {
+			exitOnError(err, logger.New(cfg.verbose))
+		}
+		os.Exit(exitOK)
+	}
 
-	absPath, err := resolveTargetPath()
+	absPath, err := resolveTargetPath(cfg.args)
 	if err != nil {
-		log.Error("%v", err)
-		printUsage()
-		os.Exit(1)
+		printUsage(cfg.flags)
+		exitOnError(err, logger.New(cfg.verbose))
+	}
+
+	// The config file may change verbosity, so load it before the logger.
+	if err := applyConfigFile(cfg, absPath); err != nil {
+		exitOnError(fmt.Errorf("%w: %w", errUsage, err), logger.New(cfg.verbose))
 	}
 
-	if err := run(cfg, absPath, log); err != nil {
+	log, closeLog, err := openLogger(cfg, os.Stdout)
+	if err != nil {
+		exitOnError(err, logger.New(cfg.verbose))
+	}
+	defer closeLog()
+
+	// Ctrl-C cancels the context; the scan stops at the next file and
+	// nothing further is written.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, cfg, absPath, log); err != nil {
+		stop()
+		exitOnError(err, log)
+	}
+
+	if cfg.watch {
+		if err := watchAndRegenerate(ctx, cfg, absPath, log); err != nil {
+			stop()
+			exitOnError(err, log)
+		}
+	}
+}
+
+// exitOnError logs err and exits with the code exitCode assigns to it,
+// reporting cancellation distinctly.
+func exitOnError(err error, log *logger.Logger) {
+	if errors.Is(err, context.Canceled) {
+		log.Error("Interrupted; stopped before writing output: %v", err)
+	} else {
 		log.Error("%v", err)
-		os.Exit(1)
 	}
+	if hint := errorHint(err); hint != "" {
+		log.Error("%s", hint)
+	}
+	log.Flush()
+	os.Exit(exitCode(err))
+}
+
+// errorHint suggests a fix for the failure classes reported by the
+// scanner's sentinel errors, or returns "" for any other error.
+func errorHint(err error) string {
+	switch {
+	case errors.Is(err, scanner.ErrPathNotFound):
+		return "Check the target path and try again."
+	case errors.Is(err, scanner.ErrNotReadable):
+		return "Check that the target is readable by the current user."
+	case errors.Is(err, scanner.ErrNotRepo):
+		return "Check that every path passed with --repos-from is a git repository."
+	case errors.Is(err, scanner.ErrNoRepos):
+		return "Loosen --exclude-repo, --since, or --min-files, pass --include-empty, or choose another target."
+	}
+	return ""
+}
+
+// handleInfoFlags services flags that print information instead of running
+// an analysis. It reports whether the program should exit successfully.
+func handleInfoFlags(cfg *config, w io.Writer) bool {
+	switch {
+	case cfg.help:
+		printHelp()
+		return true
+	case cfg.version:
+		printVersion(w)
+		return true
+	}
+	return false
 }
 
-// resolveTargetPath validates and resolves the target path from CLI args.
-func resolveTargetPath() (string, error) {
-	args := flag.Args()
+// resolveTargetPath validates and resolves the target path from the
+// positional CLI args.
+func resolveTargetPath(args []string) (string, error) {
 	if len(args) == 0 {
-		return "", fmt.Errorf("no target path provided")
+		return "", fmt.Errorf("%w: no target path provided", errUsage)
 	}
 
 	absPath, err := filepath.Abs(args[0])
@@ -73,74 +156,107 @@ func resolveTargetPath() (string, error) {
 	}
 
 	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("path does not exist: %s", absPath)
+		return "", fmt.Errorf("%w: %s", scanner.ErrPathNotFound, absPath)
 	}
 
 	return absPath, nil
 }
 
 // run executes the main application logic.
-func run(cfg *config, absPath string, log *logger.Logger) error {
+func run(ctx context.Context, cfg *config, absPath string, log *logger.Logger) error {
+	counter := countLogs(log, time.Now())
+	defer log.OnLog(nil)
+	sampler := startMemorySampler(ctx, cfg.memInterval)
+	defer sampler.stop()
+
 	log.Info("Codebase Reviewer - Phase 1")
 	log.Info("Version: %s", version)
 	log.Info("Target: %s", absPath)
 	log.Info("Scorch mode: %v", cfg.scorch)
 	log.Info("Review mode: %v", cfg.review)
+	if cfg.dryRun {
+		log.Info("Dry run: no files will be written or removed")
+	}
 	log.Info("")
 
 	if err := validateNotSelfScan(absPath); err != nil {
 		return fmt.Errorf("security check failed: %w", err)
 	}
 
-	repos, err := discoverRepositories(absPath, log)
+	opts, err := cfg.reviewerOptions(absPath, log)
 	if err != nil {
 		return err
 	}
-
-	outputDir, err := determineOutputDir(absPath, cfg.scorch, log)
+	if cfg.reposFrom != "" {
+		opts.Repositories, err = reposFromFile(cfg.reposFrom, absPath, log)
+		if err != nil {
+			return err
+		}
+	}
+	res, err := reviewer.Prepare(ctx, opts)
 	if err != nil {
 		return err
 	}
+	outputDir := res.OutputDir
 	log.Info("Output directory: %s", outputDir)
 
-	if !cfg.scorch && !cfg.review && toolsExist(outputDir) {
+	if !cfg.scorch && !cfg.review && reviewer.ToolsExist(outputDir) {
 		log.Info("Phase 2 tools already exist. Use --scorch to rebuild or --review to validate.")
 		log.Info("To regenerate reference materials, run the Phase 2 tools directly.")
 		return nil
 	}
 
 	if cfg.review {
-		return runReviewMode(outputDir, repos, log)
+		err = runReviewMode(cfg, outputDir, res.Repositories, log)
+	} else {
+		err = generatePrompt(ctx, cfg, res, opts, log)
 	}
 
-	return generatePrompt(cfg, absPath, repos, outputDir, log)
-}
-
-// discoverRepositories scans for git repositories in the target path.
-func discoverRepositories(absPath string, log *logger.Logger) ([]scanner.Repository, error) {
-	log.Info("Scanning for git repositories...")
-	repos, err := scanner.FindGitRepos(absPath, log)
-	if err != nil {
-		return nil, fmt.Errorf("failed to scan for repositories: %w", err)
+	// A cancelled run writes nothing further, metrics included.
+	if ctx.Err() == nil {
+		metrics := counter.metrics(time.Now())
+		metrics.MemoryPeakMB = sampler.stop()
+		metrics.FilesProcessed = filesProcessed(res.Analyses)
+		meta := runMetadata(res, cfg.anonymize, time.Now())
+		if metricsErr := recordRunMetrics(outputDir, meta, metrics, cfg.dryRun, log); metricsErr != nil {
+			log.Warn("Failed to record run metrics: %v", metricsErr)
+		}
 	}
+	return err
+}
 
+// filterRepositories applies --exclude-repo, --since, and --select to the
+// repositories found, in that order.
+func filterRepositories(cfg *config, repos []scanner.Repository, log *logger.Logger) ([]scanner.Repository, error) {
+	repos = excludeRepositories(repos, cfg.excludeRepos, log)
 	if len(repos) == 0 {
-		log.Warn("No git repositories found in %s", absPath)
-		log.Info("Treating entire directory as single codebase")
-		return []scanner.Repository{{Path: absPath, Name: filepath.Base(absPath)}}, nil
+		return nil, fmt.Errorf("%w: every discovered repository was excluded by --exclude-repo", scanner.ErrNoRepos)
 	}
-
-	log.Info("Found %d git repositories", len(repos))
-	for _, repo := range repos {
-		log.Info("  - %s", repo.Name)
+	if cfg.since != "" {
+		cutoff, err := parseSince(cfg.since, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		repos = dropReposBefore(repos, cutoff, log)
+		if len(repos) == 0 {
+			return nil, fmt.Errorf("%w: no repository has commits since %s", scanner.ErrNoRepos, cutoff.Format("2006-01-02"))
+		}
 	}
-	return repos, nil
+	return selectRepositories(cfg, repos, stdioTerminal(), log)
 }
 
 // runReviewMode checks if existing Phase 2 tools are still viable.
-func runReviewMode(outputDir string, repos []scanner.Repository, log *logger.Logger) error {
+func runReviewMode(cfg *config, outputDir string, repos []scanner.Repository, log *logger.Logger) error {
 	log.Info("Reviewing existing Phase 2 tools...")
-	if err := reviewPhase2Tools(outputDir, repos, log); err != nil {
+	if cfg.reviewFormat == reviewFormatSARIF {
+		if err := writeReviewSARIF(outputDir, cfg.dryRun, log); err != nil {
+			return err
+		}
+	}
+	if err := recordDependencyChanges(outputDir, repos, cfg.dryRun, log); err != nil {
+		log.Warn("Failed to detect dependency changes: %v", err)
+	}
+	if err := reviewObsolescence(outputDir, cfg.obsThreshold, log); err != nil {
 		log.Info("Run with --scorch to rebuild tools")
 		return fmt.Errorf("review failed: %w", err)
 	}
@@ -148,22 +264,40 @@ func runReviewMode(outputDir string, repos []scanner.Repository, log *logger.Log
 	return nil
 }
 
-// generatePrompt creates the LLM prompt and prints next steps.
-func generatePrompt(cfg *config, absPath string, repos []scanner.Repository, outputDir string, log *logger.Logger) error {
+// generatePrompt creates the LLM prompt, filling in res, and prints next
+// steps.
+func generatePrompt(ctx context.Context, cfg *config, res *reviewer.Result, opts reviewer.Options, log *logger.Logger) error {
 	log.Info("Generating LLM prompt for codebase analysis...")
-	promptPath, err := prompt.Generate(absPath, repos, outputDir, cfg.verbose, cfg.scorch, log)
-	if err != nil {
+	if cfg.perRepo {
+		out, err := generatePerRepo(ctx, res.Repositories, res.OutputDir, opts.PromptOptions, log)
+		if err != nil {
+			return fmt.Errorf("failed to generate prompt: %w", err)
+		}
+		res.Repositories, res.Analyses, res.PromptPath, res.Tokens = out.Repositories, out.Analyses, out.PromptPath, out.Tokens
+	} else if err := reviewer.Generate(ctx, res, opts); err != nil {
 		return fmt.Errorf("failed to generate prompt: %w", err)
 	}
+	// generatePerRepo records each repository's secrets beside its prompt.
+	if cfg.scanSecrets && !cfg.perRepo {
+		if err := recordSecretSignals(res.OutputDir, res.Analyses, cfg.dryRun, log); err != nil {
+			log.Warn("Failed to record potential secrets: %v", err)
+		}
+	}
+
+	if cfg.dryRun {
+		fmt.Fprintln(os.Stdout, "✓ Dry run complete; nothing was written")
+		return nil
+	}
 
-	printCompletionMessage(promptPath, outputDir, log)
+	printCompletionMessage(os.Stdout, res.PromptPath, res.Tokens, res.OutputDir, log)
 	return nil
 }
 
-// printCompletionMessage displays success message and next steps.
-func printCompletionMessage(promptPath, outputDir string, log *logger.Logger) {
-	log.Info("")
-	log.Info("✓ Phase 1 complete!")
+// printCompletionMessage writes the success summary, with the prompt's
+// estimated token count, to w, bypassing the logger so that it survives
+// --quiet, then logs the next steps.
+func printCompletionMessage(w io.Writer, promptPath string, tokens int, outputDir string, log *logger.Logger) {
+	fmt.Fprintf(w, "✓ Phase 1 complete! Prompt: %s (~%d tokens)\n", promptPath, tokens)
 	log.Info("")
 	log.Info("Next steps:")
 	log.Info("1. Open the generated prompt in your AI assistant:")
@@ -177,14 +311,23 @@ func printCompletionMessage(promptPath, outputDir string, log *logger.Logger) {
 	log.Info("3. After AI completes, you can regenerate docs anytime by running:")
 	log.Info("   %s/phase2-tools/bin/update-docs", outputDir)
 	log.Info("")
-	log.Info("SECURITY REMINDER: All outputs are in /tmp or .gitignore'd locations")
+	log.Info("SECURITY REMINDER: All outputs are in %s or .gitignore'd locations", os.TempDir())
 	log.Info("                   DO NOT commit proprietary analysis results to git")
 }
 
-func printUsage() {
+func printUsage(fs *flag.FlagSet) {
 	fmt.Fprintf(os.Stderr, "Usage: %s [OPTIONS] <target-path>\n", appName)
 	fmt.Fprintf(os.Stderr, "\nOptions:\n")
-	flag.PrintDefaults()
+	fs.SetOutput(os.Stderr)
+	fs.PrintDefaults()
+}
+
+// printVersion writes the version, build metadata, and Go runtime version.
+func printVersion(w io.Writer) {
+	fmt.Fprintf(w, "%s %s\n", appName, version)
+	fmt.Fprintf(w, "Commit: %s\n", commit)
+	fmt.Fprintf(w, "Built: %s\n", buildDate)
+	fmt.Fprintf(w, "Go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
 }
 
 func printHelp() {
@@ -197,9 +340,67 @@ func printHelp() {
 	fmt.Printf("  %s [OPTIONS] <target-path>\n\n", appName)
 	fmt.Printf("OPTIONS:\n")
 	fmt.Printf("  -v, --verbose    Enable verbose logging\n")
+	fmt.Printf("  -q, --quiet      Only log warnings and errors (cannot be used with -v)\n")
 	fmt.Printf("  -h, --help       Show this help message\n")
+	fmt.Printf("  --version        Print version and build information\n")
 	fmt.Printf("  --scorch         Force full rebuild of Phase 2 tools and reference materials\n")
-	fmt.Printf("  --review         Review existing Phase 2 tools to verify they're still viable\n\n")
+	fmt.Printf("  --review         Review existing Phase 2 tools to verify they're still viable\n")
+	fmt.Printf("  --template PATH  Use a custom Phase 1 prompt template instead of the default\n")
+	fmt.Printf("  --allow-unresolved\n")
+	fmt.Printf("                   Keep unknown {{PLACEHOLDER}} tokens instead of failing\n")
+	fmt.Printf("  --max-tokens N   Warn when the prompt exceeds ~N tokens (default %d)\n", prompt.DefaultMaxTokens)
+	fmt.Printf("  --formats LIST   Prompt output formats: md, yaml, json (default md,yaml)\n")
+	fmt.Printf("  --provider NAME  Prompt layout for an LLM: generic, claude, openai (default generic)\n")
+	fmt.Printf("  --output-dir DIR Absolute base directory for outputs (default %s)\n", reviewer.DefaultOutputBase)
+	fmt.Printf("  --dry-run        Scan and analyze, log planned outputs, but write nothing\n")
+	fmt.Printf("  --max-depth N    Only search N directory levels below the target for repos\n")
+	fmt.Printf("  --follow-symlinks\n")
+	fmt.Printf("                   Also scan directories reached through symlinks\n")
+	fmt.Printf("  --include-hidden Also analyze hidden directories such as .github (never .git)\n")
+	fmt.Printf("  --walk-workers N Walk each repository with N goroutines; 1 walks sequentially (default: CPUs)\n")
+	fmt.Printf("  --large-file-threshold SIZE\n")
+	fmt.Printf("                   Warn about files larger than SIZE, e.g. 500KB or 1GB (default %s)\n", formatByteSize(scanner.DefaultLargeFileThreshold))
+	fmt.Printf("  --only-lang LIST Count only these languages (e.g. go,rust) individually; the rest as \"other\"\n")
+	fmt.Printf("  --scan-secrets   Count potential secrets (AWS keys, private keys, API_KEY=...) without revealing them\n")
+	fmt.Printf("  --obsolescence-threshold N\n")
+	fmt.Printf("                   With --review, fail when the score (0..1) reaches N (default %.1f)\n", learnings.DefaultObsolescenceThreshold)
+	fmt.Printf("  --resume         Skip repositories already analyzed by an interrupted run\n")
+	fmt.Printf("  --no-cache       Re-analyze even if the codebase is unchanged since the last run\n")
+	fmt.Printf("  --incremental    Re-analyze only files changed since the last commit (full scan without a cache)\n")
+	fmt.Printf("  --report json    Also write scan-report.json for CI and other tooling\n")
+	fmt.Printf("  --format sarif   With --review, write findings from learnings.yaml to review.sarif\n")
+	fmt.Printf("  --watch          Regenerate whenever files in the target change (Ctrl+C to stop)\n")
+	fmt.Printf("  --watch-interval D\n")
+	fmt.Printf("                   Quiet period before regenerating in --watch mode (default %s)\n", defaultWatchInterval)
+	fmt.Printf("  --repos-from FILE\n")
+	fmt.Printf("                   Analyze only the repos listed in FILE, one per line or as a JSON array\n")
+	fmt.Printf("  --select         Choose interactively which discovered repos to include\n")
+	fmt.Printf("  --only LIST      Include only the named repos (comma-separated), e.g. for scripts\n")
+	fmt.Printf("  --exclude-repo GLOB\n")
+	fmt.Printf("                   Skip repos whose name or path matches GLOB (repeatable)\n")
+	fmt.Printf("  --anonymize      Replace paths and repo names with tokens for sharing; see %s\n", prompt.AnonymizationMapFileName)
+	fmt.Printf("  --per-repo       Write a prompt per repository plus an index.md linking them\n")
+	fmt.Printf("  --summary        Print a table of repositories, languages, files, and test ratios\n")
+	fmt.Printf("  --since WHEN     Skip repos with no commits since a date (2024-01-01) or duration (30d)\n")
+	fmt.Printf("  --include-empty  Keep repos with nothing but .git (skipped with a warning by default)\n")
+	fmt.Printf("  --min-files N    Leave repos with fewer than N files out of the prompt\n")
+	fmt.Printf("  --memory-interval D\n")
+	fmt.Printf("                   How often to sample heap use for learnings.yaml metrics (default %s)\n", defaultMemoryInterval)
+	fmt.Printf("  --trace          Log every file and directory visited (more than -v)\n")
+	fmt.Printf("  --log-file PATH  Also write the log to PATH (truncated unless --log-append)\n")
+	fmt.Printf("  --log-append     Append to --log-file instead of truncating it\n")
+	fmt.Printf("  --validate-template PATH\n")
+	fmt.Printf("                   Check a prompt template for required sections, then exit\n")
+	fmt.Printf("  --init-learnings DIR\n")
+	fmt.Printf("                   Write a commented %s scaffold for Phase 2 tools into DIR, then exit\n", learningsFileName)
+	fmt.Printf("  --list-languages [PATH]\n")
+	fmt.Printf("                   Print file extensions and their languages, including lang_map, then exit\n")
+	fmt.Printf("  --config PATH    Read settings from PATH (default %s in the target or home dir)\n\n", configFileName)
+	fmt.Printf("EXIT CODES:\n")
+	fmt.Printf("  %d  Success\n", exitOK)
+	fmt.Printf("  %d  Internal error (unreadable target, I/O failure, interrupted)\n", exitInternal)
+	fmt.Printf("  %d  Codebase changed: --review found the Phase 2 tools obsolete\n", exitObsolete)
+	fmt.Printf("  %d  Invalid usage: bad flags, missing target, or no repository left to analyze\n\n", exitUsage)
 	fmt.Printf("EXAMPLES:\n")
 	fmt.Printf("  # Analyze a codebase with verbose output\n")
 	fmt.Printf("  %s -v /Users/matt/projects/my-app\n\n", appName)
@@ -210,7 +411,7 @@ func printHelp() {
 	fmt.Printf("  # Analyze current directory\n")
 	fmt.Printf("  %s .\n\n", appName)
 	fmt.Printf("SECURITY:\n")
-	fmt.Printf("  All outputs are written to /tmp/codebase-reviewer/ or .gitignore'd locations.\n")
+	fmt.Printf("  All outputs are written to %s or .gitignore'd locations.\n", reviewer.DefaultOutputBase)
 	fmt.Printf("  Phase 2 tools and reference materials are considered proprietary and must\n")
 	fmt.Printf("  NOT be committed to public repositories.\n\n")
 	fmt.Printf("OUTPUT:\n")
@@ -219,6 +420,8 @@ func printHelp() {
 	fmt.Printf("  offline without requiring AI assistance.\n\n")
 }
 
+// validateNotSelfScan refuses a target inside the directory holding this
+// executable.
 func validateNotSelfScan(targetPath string) error {
 	// Get the path of this executable
 	exePath, err := os.Executable()
@@ -226,46 +429,25 @@ func validateNotSelfScan(targetPath string) error {
 		return fmt.Errorf("cannot determine executable path: %w", err)
 	}
 
-	exeDir := filepath.Dir(exePath)
-
-	// Check if target is within the tool's directory
-	relPath, err := filepath.Rel(exeDir, targetPath)
-	if err == nil && !filepath.IsAbs(relPath) && len(relPath) > 0 && relPath[0] != '.' {
+	if isWithinDir(filepath.Dir(exePath), targetPath) {
 		return fmt.Errorf("cannot scan the codebase-reviewer tool's own directory")
 	}
 
 	return nil
 }
 
-// determineOutputDir creates and returns the output directory path.
-func determineOutputDir(targetPath string, scorch bool, log *logger.Logger) (string, error) {
-	codebaseName := filepath.Base(targetPath)
-	outputDir := filepath.Join("/tmp", "codebase-reviewer", codebaseName)
-
-	if scorch {
-		if _, err := os.Stat(outputDir); err == nil {
-			log.Info("Scorch mode: removing existing output directory")
-			if err := os.RemoveAll(outputDir); err != nil {
-				log.Warn("Failed to remove existing output: %v", err)
-			}
-		}
+// isWithinDir reports whether target is dir or lies below it. Paths are
+// compared as the platform does: on Windows, case-insensitively, with
+// either separator, and never across drive letters.
+func isWithinDir(dir, target string) bool {
+	rel, err := filepath.Rel(dir, target)
+	if err != nil {
+		// Rel fails when no relative path exists, such as between drives.
+		return false
 	}
-
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create output directory: %w", err)
+	if rel == "." {
+		return true
 	}
-
-	return outputDir, nil
-}
-
-func toolsExist(outputDir string) bool {
-	toolsDir := filepath.Join(outputDir, "phase2-tools")
-	_, err := os.Stat(toolsDir)
-	return err == nil
-}
-
-func reviewPhase2Tools(outputDir string, repos []scanner.Repository, log *logger.Logger) error {
-	// This will be implemented to validate existing tools
-	// For now, return not implemented
-	return fmt.Errorf("review mode not yet implemented")
+	parent := ".." + string(filepath.Separator)
+	return rel != ".." && !strings.HasPrefix(rel, parent) && !filepath.IsAbs(rel)
 }
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+)
+
+func TestHandleInfoFlags_Version(t *testing.T) {
+	origCommit, origDate := commit, buildDate
+	commit, buildDate = "abc1234", "2024-05-01T00:00:00Z"
+	defer func() { commit, buildDate = origCommit, origDate }()
+
+	cfg, err := parseFlags([]string{"--version"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if !handleInfoFlags(cfg, &buf) {
+		t.Fatal("handleInfoFlags() = false, want true for --version")
+	}
+
+	output := buf.String()
+	for _, want := range []string{version, "abc1234", "2024-05-01T00:00:00Z", runtime.Version()} {
+		if !strings.Contains(output, want) {
+			t.Errorf("version output missing %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestHandleInfoFlags_NoInfoFlag(t *testing.T) {
+	cfg, err := parseFlags([]string{"-v", "/some/path"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if handleInfoFlags(cfg, &buf) {
+		t.Error("handleInfoFlags() = true, want false without info flags")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+	if len(cfg.args) != 1 || cfg.args[0] != "/some/path" {
+		t.Errorf("args = %v, want [/some/path]", cfg.args)
+	}
+}
+
+func TestParseFlags_Incremental(t *testing.T) {
+	cfg, err := parseFlags([]string{"--incremental", "/x"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	if !cfg.incremental {
+		t.Error("incremental = false, want true")
+	}
+	if _, err := parseFlags([]string{"--incremental", "--no-cache", "/x"}); err == nil {
+		t.Error("parseFlags(--incremental --no-cache) error = nil, want conflict")
+	}
+}
+
+func TestParseFlags_Resume(t *testing.T) {
+	cfg, err := parseFlags([]string{"--resume", "/x"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	if !cfg.resume {
+		t.Error("resume = false, want true")
+	}
+	if _, err := parseFlags([]string{"--resume", "--scorch", "/x"}); err == nil {
+		t.Error("parseFlags(--resume --scorch) error = nil, want conflict")
+	}
+}
+
+func TestParseFlags_IncludeHidden(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"/x"}, false},
+		{[]string{"--include-hidden", "/x"}, true},
+	}
+	for _, tt := range tests {
+		cfg, err := parseFlags(tt.args)
+		if err != nil {
+			t.Fatalf("parseFlags(%v) error = %v", tt.args, err)
+		}
+		if got := cfg.scanOptions(nil).IncludeHidden; got != tt.want {
+			t.Errorf("parseFlags(%v) IncludeHidden = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestParseFlags_WalkWorkers(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    int
+		wantErr bool
+	}{
+		{[]string{"/x"}, runtime.NumCPU(), false},
+		{[]string{"--walk-workers", "1", "/x"}, 1, false},
+		{[]string{"--walk-workers", "16", "/x"}, 16, false},
+		{[]string{"--walk-workers", "0", "/x"}, 0, true},
+	}
+	for _, tt := range tests {
+		cfg, err := parseFlags(tt.args)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseFlags(%v) error = nil, want error", tt.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("parseFlags(%v) error = %v", tt.args, err)
+		}
+		if got := cfg.scanOptions(nil).WalkWorkers; got != tt.want {
+			t.Errorf("parseFlags(%v) WalkWorkers = %d, want %d", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestResolveTargetPath_Missing(t *testing.T) {
+	_, err := resolveTargetPath([]string{filepath.Join(t.TempDir(), "nope")})
+	if !errors.Is(err, scanner.ErrPathNotFound) {
+		t.Errorf("resolveTargetPath() error = %v, want scanner.ErrPathNotFound", err)
+	}
+}
+
+func TestErrorHint(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"path not found", fmt.Errorf("%w: /x", scanner.ErrPathNotFound), "Check the target path"},
+		{"not readable", fmt.Errorf("walk: %w", scanner.ErrNotReadable), "readable"},
+		{"no repos", fmt.Errorf("%w: excluded", scanner.ErrNoRepos), "--exclude-repo"},
+		{"other", errors.New("boom"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errorHint(tt.err)
+			if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+				t.Errorf("errorHint(%v) = %q, want it to contain %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsWithinDir(t *testing.T) {
+	type tc struct {
+		name   string
+		dir    string
+		target string
+		want   bool
+	}
+	tests := []tc{
+		{"same directory", "/opt/tool", "/opt/tool", true},
+		{"subdirectory", "/opt/tool", "/opt/tool/src", true},
+		{"hidden subdirectory", "/opt/tool", "/opt/tool/.config", true},
+		{"dot-prefixed name", "/opt/tool", "/opt/tool/..data", true},
+		{"parent", "/opt/tool", "/opt", false},
+		{"sibling", "/opt/tool", "/opt/tools", false},
+		{"elsewhere", "/opt/tool", "/home/me/app", false},
+	}
+	if runtime.GOOS == "windows" {
+		tests = append(tests,
+			tc{"windows subdirectory", `C:\tools\reviewer`, `C:\tools\reviewer\src`, true},
+			tc{"windows forward slashes", `C:\tools\reviewer`, `C:/tools/reviewer/src`, true},
+			tc{"windows case-insensitive", `C:\Tools\Reviewer`, `c:\tools\reviewer\src`, true},
+			tc{"windows sibling", `C:\tools\reviewer`, `C:\tools\other`, false},
+			tc{"windows other drive", `C:\tools\reviewer`, `D:\tools\reviewer\src`, false},
+		)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, target := filepath.FromSlash(tt.dir), filepath.FromSlash(tt.target)
+			if got := isWithinDir(dir, target); got != tt.want {
+				t.Errorf("isWithinDir(%q, %q) = %v, want %v", dir, target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFlags_AnonymizeConflicts(t *testing.T) {
+	cfg, err := parseFlags([]string{"--anonymize", "/x"})
+	if err != nil || !cfg.anonymize {
+		t.Fatalf("parseFlags() = %+v, %v", cfg, err)
+	}
+	if _, err := parseFlags([]string{"--anonymize", "--per-repo", "/x"}); err == nil || !strings.Contains(err.Error(), "--per-repo") {
+		t.Errorf("parseFlags() error = %v, want --per-repo conflict", err)
+	}
+}
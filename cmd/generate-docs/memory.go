@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// defaultMemoryInterval is how often the heap is sampled for the run's
+// MemoryPeakMB unless --memory-interval says otherwise.
+const defaultMemoryInterval = 250 * time.Millisecond
+
+const bytesPerMB = 1024 * 1024
+
+// memorySampler records the peak heap allocation seen during a run by
+// reading runtime.MemStats periodically in a goroutine.
+type memorySampler struct {
+	mu       sync.Mutex
+	peak     uint64
+	stopOnce sync.Once
+	quit     chan struct{}
+	done     chan struct{}
+}
+
+// startMemorySampler samples the heap immediately and then every interval
+// until ctx is cancelled or stop is called. A non-positive interval uses
+// defaultMemoryInterval.
+func startMemorySampler(ctx context.Context, interval time.Duration) *memorySampler {
+	if interval <= 0 {
+		interval = defaultMemoryInterval
+	}
+	s := &memorySampler{quit: make(chan struct{}), done: make(chan struct{})}
+	s.sample()
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.quit:
+				return
+			case <-ticker.C:
+				s.sample()
+			}
+		}
+	}()
+	return s
+}
+
+// sample reads the current heap allocation and raises the peak if needed.
+func (s *memorySampler) sample() {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m.HeapAlloc > s.peak {
+		s.peak = m.HeapAlloc
+	}
+}
+
+// stop ends sampling, takes a final sample, and returns the peak heap in
+// MB. It waits for the sampling goroutine to exit and is safe to call more
+// than once.
+func (s *memorySampler) stop() float64 {
+	s.stopOnce.Do(func() {
+		close(s.quit)
+		<-s.done
+		s.sample()
+	})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return float64(s.peak) / bytesPerMB
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemorySampler_RecordsPeak(t *testing.T) {
+	s := startMemorySampler(context.Background(), time.Millisecond)
+
+	chunk := make([]byte, 64*bytesPerMB)
+	for i := range chunk {
+		chunk[i] = byte(i)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	peak := s.stop()
+	runtime.KeepAlive(chunk)
+	if peak <= 0 {
+		t.Fatalf("peak = %.1f MB, want it positive", peak)
+	}
+	if peak < 64 {
+		t.Errorf("peak = %.1f MB, want at least the 64 MB chunk", peak)
+	}
+	if again := s.stop(); again != peak {
+		t.Errorf("second stop() = %.1f, want the same peak %.1f", again, peak)
+	}
+}
+
+func TestMemorySampler_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := startMemorySampler(ctx, time.Millisecond)
+	cancel()
+
+	select {
+	case <-s.done:
+	case <-time.After(time.Second):
+		t.Fatal("sampler goroutine did not exit after the context was cancelled")
+	}
+	if peak := s.stop(); peak <= 0 {
+		t.Errorf("peak = %.1f MB after cancel, want the samples taken so far", peak)
+	}
+}
+
+func TestParseFlags_MemoryInterval(t *testing.T) {
+	cfg, err := parseFlags([]string{"/x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.memInterval != defaultMemoryInterval {
+		t.Errorf("memInterval = %s, want default %s", cfg.memInterval, defaultMemoryInterval)
+	}
+	if _, err := parseFlags([]string{"--memory-interval", "0s", "/x"}); err == nil || !strings.Contains(err.Error(), "must be positive") {
+		t.Errorf("parseFlags() error = %v, want a non-positive interval rejected", err)
+	}
+}
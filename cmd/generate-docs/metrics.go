@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/learnings"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+	"github.com/bordenet/codebase-reviewer/pkg/reviewer"
+)
+
+// runCounter tallies the warnings and errors a logger emits during a run.
+type runCounter struct {
+	mu       sync.Mutex
+	start    time.Time
+	warnings int
+	errors   int
+}
+
+// countLogs starts counting the warnings and errors written to log,
+// replacing any earlier OnLog hook.
+func countLogs(log *logger.Logger, now time.Time) *runCounter {
+	c := &runCounter{start: now}
+	log.OnLog(c.observe)
+	return c
+}
+
+func (c *runCounter) observe(level logger.Level, _ string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch level {
+	case logger.LevelWarn:
+		c.warnings++
+	case logger.LevelError:
+		c.errors++
+	}
+}
+
+// metrics returns the counts so far and the time elapsed until now.
+func (c *runCounter) metrics(now time.Time) learnings.ExecutionMetrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return learnings.ExecutionMetrics{
+		DurationSeconds:   now.Sub(c.start).Seconds(),
+		ErrorsEncountered: c.errors,
+		WarningsGenerated: c.warnings,
+	}
+}
+
+// filesProcessed returns the number of files analyzed across analyses.
+func filesProcessed(analyses []*scanner.RepositoryAnalysis) int {
+	total := 0
+	for _, a := range analyses {
+		total += a.TotalFiles
+	}
+	return total
+}
+
+// runMetadata describes the run that produced res at now. With anonymize
+// the codebase name and path are left out, as they are from the prompt.
+func runMetadata(res *reviewer.Result, anonymize bool, now time.Time) learnings.Metadata {
+	meta := learnings.Metadata{
+		ToolName:            appName,
+		ToolVersion:         version,
+		RunDate:             now.UTC(),
+		CodebaseFingerprint: res.Fingerprint,
+	}
+	if !anonymize {
+		meta.CodebaseName = filepath.Base(res.Target)
+		meta.CodebasePath = res.Target
+	}
+	return meta
+}
+
+// recordRunMetrics adds m to the execution metrics in outputDir's
+// learnings.yaml, creating the file if needed, and records meta as the
+// latest run. The file's generation is kept, starting at 1.
+func recordRunMetrics(outputDir string, meta learnings.Metadata, m learnings.ExecutionMetrics, dryRun bool, log *logger.Logger) error {
+	path := filepath.Join(outputDir, learningsFileName)
+	if dryRun {
+		log.Debug("Dry run: would record %d warning(s) and %d error(s) in %s", m.WarningsGenerated, m.ErrorsEncountered, path)
+		return nil
+	}
+
+	l, err := learnings.Load(path)
+	if err != nil {
+		return fmt.Errorf("failed to load learnings: %w", err)
+	}
+	meta.Generation = l.Metadata.Generation
+	if meta.Generation == 0 {
+		meta.Generation = 1
+	}
+	l.WithMetadata(meta).AddMetrics(m)
+	if err := l.Save(path); err != nil {
+		return err
+	}
+	log.Debug("Recorded %d warning(s) and %d error(s) in %s", m.WarningsGenerated, m.ErrorsEncountered, path)
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/learnings"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+	"github.com/bordenet/codebase-reviewer/pkg/reviewer"
+)
+
+func TestCountLogs(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.NewWithWriter(&buf, false)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	counter := countLogs(log, start)
+	log.Debug("suppressed")
+	log.Info("scanning")
+	log.Warn("slow")
+	log.Warn("slower")
+	log.Error("failed")
+
+	got := counter.metrics(start.Add(3 * time.Second))
+	want := learnings.ExecutionMetrics{DurationSeconds: 3, WarningsGenerated: 2, ErrorsEncountered: 1}
+	if got != want {
+		t.Errorf("metrics() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRecordRunMetrics(t *testing.T) {
+	outputDir := t.TempDir()
+	log := logger.New(false)
+	run := learnings.ExecutionMetrics{FilesProcessed: 3, WarningsGenerated: 2, ErrorsEncountered: 1}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	meta := runMetadata(&reviewer.Result{Target: "/src/app", Fingerprint: "abc123"}, false, now)
+
+	for i := 0; i < 2; i++ {
+		if err := recordRunMetrics(outputDir, meta, run, false, log); err != nil {
+			t.Fatalf("recordRunMetrics() error = %v", err)
+		}
+	}
+	if err := recordRunMetrics(outputDir, meta, run, true, log); err != nil {
+		t.Fatalf("recordRunMetrics() dry run error = %v", err)
+	}
+
+	l, err := learnings.Load(filepath.Join(outputDir, learningsFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.ExecutionMetrics.WarningsGenerated != 4 || l.ExecutionMetrics.ErrorsEncountered != 2 || l.ExecutionMetrics.FilesProcessed != 6 {
+		t.Errorf("recorded metrics = %+v, want 6 files, 4 warnings, and 2 errors from two real runs", l.ExecutionMetrics)
+	}
+	want := learnings.Metadata{
+		ToolName:            appName,
+		ToolVersion:         version,
+		Generation:          1,
+		RunDate:             now,
+		CodebaseName:        "app",
+		CodebasePath:        "/src/app",
+		CodebaseFingerprint: "abc123",
+	}
+	if !l.Metadata.RunDate.Equal(want.RunDate) {
+		t.Errorf("recorded run date = %s, want %s", l.Metadata.RunDate, want.RunDate)
+	}
+	l.Metadata.RunDate = want.RunDate
+	if l.Metadata != want {
+		t.Errorf("recorded metadata = %+v, want %+v", l.Metadata, want)
+	}
+}
+
+func TestRunMetadata_Anonymized(t *testing.T) {
+	meta := runMetadata(&reviewer.Result{Target: "/src/app"}, true, time.Now())
+	if meta.CodebaseName != "" || meta.CodebasePath != "" {
+		t.Errorf("runMetadata() with anonymize = %+v, want no codebase name or path", meta)
+	}
+}
+
+func TestFilesProcessed(t *testing.T) {
+	analyses := []*scanner.RepositoryAnalysis{{TotalFiles: 3}, {TotalFiles: 4}}
+	if got := filesProcessed(analyses); got != 7 {
+		t.Errorf("filesProcessed() = %d, want 7", got)
+	}
+}
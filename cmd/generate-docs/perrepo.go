@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bordenet/codebase-reviewer/internal/prompt"
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// indexFileName is the --per-repo index linking each repository's prompt.
+const indexFileName = "index.md"
+
+// repoOutputNames returns the subdirectory each repository's outputs are
+// written to in --per-repo mode: its name, or, when several repositories
+// share that name, its relative path with separators replaced by "_".
+func repoOutputNames(repos []scanner.Repository) []string {
+	counts := make(map[string]int)
+	for _, repo := range repos {
+		counts[repo.Name]++
+	}
+
+	names := make([]string, len(repos))
+	for i, repo := range repos {
+		names[i] = repo.Name
+		rel := filepath.ToSlash(repo.RelativePath)
+		if counts[repo.Name] > 1 && rel != "" && rel != "." {
+			names[i] = strings.ReplaceAll(rel, "/", "_")
+		}
+	}
+	return names
+}
+
+// generatePerRepo writes a separate prompt for each repository under
+// <outputDir>/<name>/ and an index.md in outputDir linking them. The
+// result's PromptPath is the index, and its Tokens, Repositories, and
+// Analyses cover all the prompts. With --scan-secrets each repository's
+// counts are recorded in the learnings.yaml beside its prompt.
+// Repositories that Generate drops as empty or below --min-files are left
+// out of the index.
+func generatePerRepo(ctx context.Context, repos []scanner.Repository, outputDir string, opts prompt.Options, log *logger.Logger) (*prompt.Result, error) {
+	names := repoOutputNames(repos)
+
+	var index strings.Builder
+	all := &prompt.Result{}
+	index.WriteString("# Codebase Review Prompts\n\n")
+	for i, repo := range repos {
+		repoDir := filepath.Join(outputDir, names[i])
+		if !opts.DryRun {
+			if err := os.MkdirAll(repoDir, 0755); err != nil {
+				return nil, fmt.Errorf("failed to create output directory for %s: %w", repo.Name, err)
+			}
+		}
+
+		log.Info("Generating prompt for %s...", repo.Name)
+		res, err := prompt.GenerateResult(ctx, repo.Path, []scanner.Repository{repo}, repoDir, opts, log)
+		if errors.Is(err, scanner.ErrNoRepos) {
+			// Empty or below --min-files; Generate has logged why.
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate prompt for %s: %w", repo.Name, err)
+		}
+		if opts.Scan.ScanSecrets {
+			if err := recordSecretSignals(repoDir, res.Analyses, opts.DryRun, log); err != nil {
+				log.Warn("Failed to record potential secrets for %s: %v", repo.Name, err)
+			}
+		}
+
+		link, err := filepath.Rel(outputDir, res.PromptPath)
+		if err != nil {
+			link = res.PromptPath
+		}
+		fmt.Fprintf(&index, "- [%s](%s)", repo.Name, filepath.ToSlash(link))
+		if rel := filepath.ToSlash(repo.RelativePath); rel != "" && rel != "." {
+			fmt.Fprintf(&index, " (`%s`)", rel)
+		}
+		index.WriteString("\n")
+		all.Repositories = append(all.Repositories, res.Repositories...)
+		all.Analyses = append(all.Analyses, res.Analyses...)
+		all.Tokens += res.Tokens
+	}
+
+	if len(all.Repositories) == 0 {
+		return nil, fmt.Errorf("%w: no repository produced a prompt", scanner.ErrNoRepos)
+	}
+
+	all.PromptPath = filepath.Join(outputDir, indexFileName)
+	if opts.DryRun {
+		log.Info("Dry run: would write %s", all.PromptPath)
+		return all, nil
+	}
+	if err := os.WriteFile(all.PromptPath, []byte(index.String()), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write prompt index: %w", err)
+	}
+	log.Info("Prompt index written: %s", all.PromptPath)
+	return all, nil
+}
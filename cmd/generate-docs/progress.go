@@ -0,0 +1,24 @@
+package main
+
+import (
+	"time"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// progressLogInterval is the minimum time between progress log lines.
+const progressLogInterval = 2 * time.Second
+
+// newProgressLogger returns a scanner progress callback that logs the
+// running file count at INFO level at most once per interval.
+func newProgressLogger(log *logger.Logger, interval time.Duration, now func() time.Time) func(filesSeen int) {
+	var last time.Time
+	return func(filesSeen int) {
+		t := now()
+		if !last.IsZero() && t.Sub(last) < interval {
+			return
+		}
+		last = t
+		log.Info("  ...%d files scanned", filesSeen)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestNewProgressLogger_Throttles(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.NewWithWriter(&buf, false)
+
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	report := newProgressLogger(log, 2*time.Second, func() time.Time { return clock })
+
+	steps := []time.Duration{0, time.Second, time.Second, time.Second, 3 * time.Second}
+	for i, step := range steps {
+		clock = clock.Add(step)
+		report((i + 1) * 1000)
+	}
+
+	got := strings.Count(buf.String(), "files scanned")
+	if got != 3 {
+		t.Errorf("logged %d progress lines, want 3:\n%s", got, buf.String())
+	}
+	for _, want := range []string{"1000 files", "3000 files", "5000 files"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// readRepoList parses the repository paths listed in data, either as a
+// JSON array of strings or as one path per line. Blank lines and lines
+// starting with # are ignored in the line format.
+func readRepoList(data []byte) ([]string, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var paths []string
+		if err := json.Unmarshal(trimmed, &paths); err != nil {
+			return nil, fmt.Errorf("invalid JSON repository list: %w", err)
+		}
+		return paths, nil
+	}
+
+	var paths []string
+	lines := bufio.NewScanner(bytes.NewReader(data))
+	for lines.Scan() {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	return paths, lines.Err()
+}
+
+// reposFromFile reads the repositories named in the --repos-from file at
+// listPath instead of discovering them under absPath. Relative entries are
+// resolved against the directory holding the list. Every entry must be a
+// git repository outside this tool's own directory; listing the same
+// directory twice keeps the first.
+func reposFromFile(listPath, absPath string, log *logger.Logger) ([]scanner.Repository, error) {
+	data, err := os.ReadFile(listPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read --repos-from: %w", errUsage, err)
+	}
+	paths, err := readRepoList(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", errUsage, listPath, err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w: %s lists no repositories", scanner.ErrNoRepos, listPath)
+	}
+
+	listDir, err := filepath.Abs(filepath.Dir(listPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", listPath, err)
+	}
+	seen := make(map[string]bool, len(paths))
+	var repos []scanner.Repository
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(listDir, path)
+		}
+		path = filepath.Clean(path)
+		if seen[path] {
+			log.Debug("Skipping repository listed twice: %s", path)
+			continue
+		}
+		seen[path] = true
+
+		if err := validateNotSelfScan(path); err != nil {
+			return nil, fmt.Errorf("security check failed: %w", err)
+		}
+		repo, err := scanner.OpenRepository(path, absPath, log)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", errUsage, listPath, err)
+		}
+		repos = append(repos, repo)
+	}
+
+	log.Info("Using %d repositories from %s", len(repos), listPath)
+	for _, repo := range repos {
+		log.Info("  - %s", repo.Name)
+	}
+	return repos, nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// makeRepos creates a directory with a .git subdirectory under root for
+// each name.
+func makeRepos(t *testing.T, root string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if err := os.MkdirAll(filepath.Join(root, name, ".git"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadRepoList(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []string
+		wantErr bool
+	}{
+		{"newline", "api\n\n# comment\n  /src/web  \r\n", []string{"api", "/src/web"}, false},
+		{"json", ` ["api", "/src/web"]`, []string{"api", "/src/web"}, false},
+		{"empty", "\n\n", nil, false},
+		{"invalid json", `["api",`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readRepoList([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readRepoList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readRepoList() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReposFromFile(t *testing.T) {
+	root := t.TempDir()
+	makeRepos(t, root, "api", "web")
+	if err := os.MkdirAll(filepath.Join(root, "plain"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		list      string
+		wantNames []string
+		wantErr   error
+	}{
+		{"newline", "api\n" + filepath.Join(root, "web") + "\napi\n", []string{"api", "web"}, nil},
+		{"json", `["web", "api"]`, []string{"web", "api"}, nil},
+		{"not a repo", "api\nplain\n", nil, scanner.ErrNotRepo},
+		{"missing path", "missing\n", nil, scanner.ErrPathNotFound},
+		{"no entries", "# nothing yet\n", nil, scanner.ErrNoRepos},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listPath := filepath.Join(root, "repos.txt")
+			if err := os.WriteFile(listPath, []byte(tt.list), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			repos, err := reposFromFile(listPath, root, logger.New(false))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("reposFromFile() error = %v, want %v", err, tt.wantErr)
+				}
+				if exitCode(err) != exitUsage {
+					t.Errorf("exitCode() = %d, want %d", exitCode(err), exitUsage)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("reposFromFile() error = %v", err)
+			}
+			var names []string
+			for _, repo := range repos {
+				names = append(names, repo.Name)
+				if repo.RelativePath != repo.Name {
+					t.Errorf("RelativePath = %q, want %q", repo.RelativePath, repo.Name)
+				}
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("reposFromFile() names = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestReposFromFile_MissingList(t *testing.T) {
+	_, err := reposFromFile(filepath.Join(t.TempDir(), "absent.txt"), t.TempDir(), logger.New(false))
+	if !errors.Is(err, errUsage) {
+		t.Errorf("reposFromFile() error = %v, want errUsage", err)
+	}
+}
+
+func TestParseFlags_ReposFrom(t *testing.T) {
+	cfg, err := parseFlags([]string{"--repos-from", "repos.txt", "/x"})
+	if err != nil || cfg.reposFrom != "repos.txt" {
+		t.Fatalf("parseFlags() = %+v, %v", cfg, err)
+	}
+	if _, err := parseFlags([]string{"--repos-from", "repos.txt", "--max-depth", "2", "/x"}); err == nil {
+		t.Error("parseFlags() error = nil, want --max-depth conflict")
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/learnings"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// reviewFormatSARIF selects SARIF output for --review.
+const reviewFormatSARIF = "sarif"
+
+const (
+	learningsFileName   = "learnings.yaml"
+	reviewSARIFFileName = "review.sarif"
+)
+
+// maxReviewReasons caps how many obsolescence reasons --review prints.
+const maxReviewReasons = 3
+
+// writeReviewSARIF converts the learnings in outputDir to SARIF and writes
+// review.sarif next to them. Missing learnings yield an empty SARIF run.
+func writeReviewSARIF(outputDir string, dryRun bool, log *logger.Logger) error {
+	l, err := learnings.Load(filepath.Join(outputDir, learningsFileName))
+	if err != nil {
+		return fmt.Errorf("failed to load learnings: %w", err)
+	}
+
+	data, err := learnings.ToSARIF(l)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(outputDir, reviewSARIFFileName)
+	if dryRun {
+		log.Info("Dry run: would write %s (%d bytes)", path, len(data))
+		return nil
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write SARIF report: %w", err)
+	}
+	log.Info("SARIF report written: %s", path)
+	return nil
+}
+
+// recordDependencyChanges compares the dependencies now declared by repos
+// with those in the scan report left by an earlier --report json run, logs
+// the differences, and stores them in learnings.yaml. Without an earlier
+// report there is nothing to compare and nothing is written.
+func recordDependencyChanges(outputDir string, repos []scanner.Repository, dryRun bool, log *logger.Logger) error {
+	previous, err := scanner.LoadScanReport(filepath.Join(outputDir, scanner.ScanReportFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			log.Info("No previous %s; run with --report json to track dependency changes", scanner.ScanReportFileName)
+			return nil
+		}
+		return err
+	}
+
+	current := make(map[string]string)
+	for _, repo := range repos {
+		for name, version := range scanner.DependencyVersions(repo) {
+			current[name] = version
+		}
+	}
+
+	changes := learnings.DetectDependencyChanges(previous.Dependencies, current)
+	for _, dep := range changes.NewDependencies {
+		log.Info("New dependency: %s", dep)
+	}
+	for _, dep := range changes.RemovedDependencies {
+		log.Info("Removed dependency: %s", dep)
+	}
+	for _, dep := range changes.MajorUpgrades {
+		log.Warn("Major upgrade: %s", dep)
+	}
+
+	path := filepath.Join(outputDir, learningsFileName)
+	if dryRun {
+		log.Info("Dry run: would record dependency changes in %s", path)
+		return nil
+	}
+	l, err := learnings.Load(path)
+	if err != nil {
+		return fmt.Errorf("failed to load learnings: %w", err)
+	}
+	l.CodebaseChanges.DependencyChanges = changes
+	return l.Save(path)
+}
+
+// reviewObsolescence scores the codebase changes recorded in outputDir's
+// learnings, logs the score and its leading reasons, and returns an error
+// wrapping learnings.ErrObsolete when the score meets or exceeds threshold.
+func reviewObsolescence(outputDir string, threshold float64, log *logger.Logger) error {
+	l, err := learnings.Load(filepath.Join(outputDir, learningsFileName))
+	if err != nil {
+		return fmt.Errorf("failed to load learnings: %w", err)
+	}
+
+	indicators := learnings.ComputeObsolescence(l)
+	log.Info("Obsolescence score: %.2f (threshold %.2f, confidence %s)", indicators.ObsolescenceScore, threshold, indicators.Confidence)
+	reasons := indicators.Reasons
+	if len(reasons) > maxReviewReasons {
+		reasons = reasons[:maxReviewReasons]
+	}
+	for _, reason := range reasons {
+		log.Info("  - %s", reason)
+	}
+
+	if indicators.ObsolescenceScore >= threshold {
+		return fmt.Errorf("%w: obsolescence score %.2f meets threshold %.2f", learnings.ErrObsolete, indicators.ObsolescenceScore, threshold)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/learnings"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestWriteReviewSARIF(t *testing.T) {
+	dir := t.TempDir()
+	l := learnings.NewLearnings()
+	l.WhatFailed = append(l.WhatFailed, learnings.Failed{Category: "parsing", Description: "timed out"})
+	if err := l.Save(filepath.Join(dir, learningsFileName)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeReviewSARIF(dir, false, logger.New(false)); err != nil {
+		t.Fatalf("writeReviewSARIF() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, reviewSARIFFileName))
+	if err != nil {
+		t.Fatalf("review.sarif not written: %v", err)
+	}
+	var doc struct {
+		Runs []struct {
+			Results []struct {
+				Level string `json:"level"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Runs) != 1 || len(doc.Runs[0].Results) != 1 || doc.Runs[0].Results[0].Level != "error" {
+		t.Errorf("unexpected SARIF document: %s", data)
+	}
+}
+
+func TestWriteReviewSARIF_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeReviewSARIF(dir, true, logger.New(false)); err != nil {
+		t.Fatalf("writeReviewSARIF() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, reviewSARIFFileName)); !os.IsNotExist(err) {
+		t.Errorf("dry run wrote review.sarif (stat err = %v)", err)
+	}
+}
+
+func TestParseFlags_ReviewFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "sarif with review", args: []string{"--review", "--format", "sarif", "/x"}},
+		{name: "sarif without review", args: []string{"--format", "sarif", "/x"}, wantErr: "requires --review"},
+		{name: "unknown format", args: []string{"--review", "--format", "xml", "/x"}, wantErr: "unknown --format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseFlags(tt.args)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("parseFlags() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseFlags() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecordDependencyChanges(t *testing.T) {
+	outputDir := t.TempDir()
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "package.json"), []byte(`{"dependencies": {"react": "^18.2.0", "axios": "^1.6.0"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repos := []scanner.Repository{{Path: repoDir, Name: "web"}}
+
+	// Without an earlier report there is nothing to compare.
+	if err := recordDependencyChanges(outputDir, repos, false, logger.New(false)); err != nil {
+		t.Fatalf("recordDependencyChanges() without report error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, learningsFileName)); !os.IsNotExist(err) {
+		t.Errorf("learnings should not be written without a previous report, stat err = %v", err)
+	}
+
+	previous := &scanner.ScanReport{Dependencies: map[string]string{"react": "^17.0.2", "moment": "^2.29.0"}}
+	data, err := scanner.MarshalScanReport(previous)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, scanner.ScanReportFileName), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := recordDependencyChanges(outputDir, repos, false, logger.New(false)); err != nil {
+		t.Fatalf("recordDependencyChanges() error = %v", err)
+	}
+	l, err := learnings.Load(filepath.Join(outputDir, learningsFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := learnings.DependencyChanges{
+		NewDependencies:     []string{"axios@^1.6.0"},
+		RemovedDependencies: []string{"moment@^2.29.0"},
+		MajorUpgrades:       []string{"react ^17.0.2 -> ^18.2.0"},
+	}
+	if !reflect.DeepEqual(l.CodebaseChanges.DependencyChanges, want) {
+		t.Errorf("recorded changes = %+v, want %+v", l.CodebaseChanges.DependencyChanges, want)
+	}
+}
+
+func TestReviewObsolescence(t *testing.T) {
+	// Two language changes saturate that category (weight 0.25) and five
+	// dependency changes saturate theirs (0.15): a score of 0.40.
+	dir := t.TempDir()
+	l := learnings.NewLearnings()
+	l.CodebaseChanges.LanguageChanges.NewLanguages = []string{"Rust", "Zig"}
+	l.CodebaseChanges.DependencyChanges.NewDependencies = []string{"a", "b", "c", "d", "e"}
+	if err := l.Save(filepath.Join(dir, learningsFileName)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		threshold float64
+		wantErr   bool
+	}{
+		{"score just below threshold", 0.41, false},
+		{"score meets threshold", 0.40, true},
+		{"score just above threshold", 0.39, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			log := logger.NewWithWriter(&out, false)
+			err := reviewObsolescence(dir, tt.threshold, log)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("reviewObsolescence() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), "Obsolescence score: 0.40") {
+				t.Errorf("output missing score:\n%s", out.String())
+			}
+			if !strings.Contains(out.String(), "2 language change(s) detected") {
+				t.Errorf("output missing reasons:\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestParseFlags_ObsolescenceThreshold(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    float64
+		wantErr bool
+	}{
+		{"default", []string{"/x"}, learnings.DefaultObsolescenceThreshold, false},
+		{"custom", []string{"--obsolescence-threshold", "0.8", "/x"}, 0.8, false},
+		{"bounds inclusive", []string{"--obsolescence-threshold", "1", "/x"}, 1, false},
+		{"above range", []string{"--obsolescence-threshold", "1.5", "/x"}, 0, true},
+		{"negative", []string{"--obsolescence-threshold", "-0.1", "/x"}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := parseFlags(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.obsThreshold != tt.want {
+				t.Errorf("obsThreshold = %v, want %v", cfg.obsThreshold, tt.want)
+			}
+		})
+	}
+}
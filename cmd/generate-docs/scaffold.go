@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/bordenet/codebase-reviewer/pkg/learnings"
+)
+
+// initLearnings writes a commented learnings.yaml scaffold into dir for
+// Phase 2 tool authors to start from, and reports the path written to w.
+func initLearnings(dir string, w io.Writer) error {
+	path := filepath.Join(dir, learningsFileName)
+	if err := learnings.WriteTemplate(path); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Wrote %s\n", path)
+	return nil
+}
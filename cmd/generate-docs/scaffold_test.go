@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/learnings"
+)
+
+func TestInitLearnings(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tools")
+	var out bytes.Buffer
+	if err := initLearnings(dir, &out); err != nil {
+		t.Fatalf("initLearnings() error = %v", err)
+	}
+
+	path := filepath.Join(dir, learningsFileName)
+	if !strings.Contains(out.String(), path) {
+		t.Errorf("output %q does not name %s", out.String(), path)
+	}
+	l, err := learnings.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(l.Improvements) == 0 || l.Metadata.ToolName == "" {
+		t.Errorf("Load() = %+v, want the populated example", l)
+	}
+
+	if err := initLearnings(dir, &bytes.Buffer{}); err == nil {
+		t.Error("second initLearnings() error = nil, want refusal to overwrite")
+	}
+}
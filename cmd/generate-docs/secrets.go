@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/learnings"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// recordSecretSignals stores the --scan-secrets counts of analyses, by
+// repository and by kind, in outputDir's learnings.yaml, replacing those
+// of the previous scan. The findings themselves are not recorded.
+func recordSecretSignals(outputDir string, analyses []*scanner.RepositoryAnalysis, dryRun bool, log *logger.Logger) error {
+	byRepository := make(map[string]int)
+	var all []scanner.SecretFinding
+	for _, a := range analyses {
+		byRepository[a.Repository.Name] += len(a.Secrets)
+		all = append(all, a.Secrets...)
+	}
+	if len(all) > 0 {
+		log.Warn("Found %d potential secret(s); rotate any that are real and remove them from history", len(all))
+	}
+
+	path := filepath.Join(outputDir, learningsFileName)
+	if dryRun {
+		log.Debug("Dry run: would record %d potential secret(s) in %s", len(all), path)
+		return nil
+	}
+	l, err := learnings.Load(path)
+	if err != nil {
+		return fmt.Errorf("failed to load learnings: %w", err)
+	}
+	l.RecordSecrets(byRepository, scanner.CountSecretsByKind(all))
+	if err := l.Save(path); err != nil {
+		return err
+	}
+	log.Debug("Recorded %d potential secret(s) in %s", len(all), path)
+	return nil
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+	"golang.org/x/term"
+)
+
+// terminal is where the interactive --select prompt is shown and answered.
+type terminal struct {
+	in          io.Reader
+	out         io.Writer
+	interactive bool
+}
+
+// stdioTerminal returns the process terminal, interactive when stdin is a
+// terminal rather than a pipe, file, or device such as /dev/null.
+func stdioTerminal() terminal {
+	return fileTerminal(os.Stdin, os.Stdout)
+}
+
+// fileTerminal returns a terminal reading from in and writing to out,
+// interactive only when in is a terminal.
+func fileTerminal(in *os.File, out io.Writer) terminal {
+	return terminal{in: in, out: out, interactive: term.IsTerminal(int(in.Fd()))}
+}
+
+// selectRepositories narrows the discovered repositories to those chosen
+// with --only or, on a terminal, interactively with --select. Without
+// either flag all repositories are kept.
+func selectRepositories(cfg *config, repos []scanner.Repository, term terminal, log *logger.Logger) ([]scanner.Repository, error) {
+	switch {
+	case cfg.only != "":
+		selected, err := filterByName(repos, splitList(cfg.only))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errUsage, err)
+		}
+		log.Info("Selected %d of %d repositories with --only", len(selected), len(repos))
+		return selected, nil
+	case cfg.selectRepos:
+		if !term.interactive {
+			return nil, fmt.Errorf("%w: --select needs an interactive terminal; use --only name1,name2 instead", errUsage)
+		}
+		selected, err := promptSelection(repos, term)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("Selected %d of %d repositories", len(selected), len(repos))
+		return selected, nil
+	}
+	return repos, nil
+}
+
+// filterByName keeps the repositories whose Name or RelativePath is listed
+// in names, in discovery order. Every name must match some repository.
+func filterByName(repos []scanner.Repository, names []string) ([]scanner.Repository, error) {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	matched := make(map[string]bool)
+	var selected []scanner.Repository
+	for _, repo := range repos {
+		hit := false
+		for _, key := range []string{repo.Name, repo.RelativePath} {
+			if wanted[key] {
+				matched[key] = true
+				hit = true
+			}
+		}
+		if hit {
+			selected = append(selected, repo)
+		}
+	}
+
+	for _, name := range names {
+		if !matched[name] {
+			return nil, fmt.Errorf("--only: no discovered repository named %q", name)
+		}
+	}
+	return selected, nil
+}
+
+// splitList splits a comma-separated flag value, dropping blank entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// promptSelection lists repos on term and reads index ranges until a valid
+// choice is entered. An empty answer selects everything.
+func promptSelection(repos []scanner.Repository, term terminal) ([]scanner.Repository, error) {
+	fmt.Fprintf(term.out, "Discovered %d repositories:\n", len(repos))
+	for i, repo := range repos {
+		fmt.Fprintf(term.out, "  %3d) %s (%s)\n", i+1, repo.Name, repo.RelativePath)
+	}
+
+	reader := bufio.NewReader(term.in)
+	for {
+		fmt.Fprintf(term.out, "Select repositories (e.g. 1-3,5; empty for all): ")
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return nil, fmt.Errorf("no repository selection entered: %w", err)
+		}
+
+		indexes, parseErr := parseIndexRanges(line, len(repos))
+		if parseErr != nil {
+			fmt.Fprintf(term.out, "%v\n", parseErr)
+			continue
+		}
+		if indexes == nil {
+			return repos, nil
+		}
+		selected := make([]scanner.Repository, 0, len(indexes))
+		for _, i := range indexes {
+			selected = append(selected, repos[i])
+		}
+		return selected, nil
+	}
+}
+
+// parseIndexRanges parses 1-based indexes and ranges such as "1-3, 5" into
+// sorted, de-duplicated 0-based indexes below n. It returns nil for a blank
+// answer.
+func parseIndexRanges(answer string, n int) ([]int, error) {
+	parts := splitList(answer)
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	chosen := make([]bool, n)
+	for _, part := range parts {
+		lo, hi, isRange := strings.Cut(part, "-")
+		start, err := parseIndex(lo, n)
+		if err != nil {
+			return nil, err
+		}
+		end := start
+		if isRange {
+			if end, err = parseIndex(hi, n); err != nil {
+				return nil, err
+			}
+			if end < start {
+				return nil, fmt.Errorf("invalid range %q: start is after end", part)
+			}
+		}
+		for i := start; i <= end; i++ {
+			chosen[i] = true
+		}
+	}
+
+	var indexes []int
+	for i, ok := range chosen {
+		if ok {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes, nil
+}
+
+// parseIndex converts a 1-based index string to a 0-based index below n.
+func parseIndex(s string, n int) (int, error) {
+	i, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || i < 1 || i > n {
+		return 0, fmt.Errorf("invalid selection %q: enter numbers from 1 to %d", strings.TrimSpace(s), n)
+	}
+	return i - 1, nil
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// excludeRepositories drops repositories matched by any of the
+// --exclude-repo glob patterns, logging each one dropped.
+func excludeRepositories(repos []scanner.Repository, patterns []string, log *logger.Logger) []scanner.Repository {
+	if len(patterns) == 0 {
+		return repos
+	}
+	var kept []scanner.Repository
+	for _, repo := range repos {
+		if pattern, ok := matchRepo(repo, patterns); ok {
+			log.Info("Excluding repository %s (matches --exclude-repo %q)", repo.RelativePath, pattern)
+			continue
+		}
+		kept = append(kept, repo)
+	}
+	return kept
+}
+
+// matchRepo returns the first pattern matching the repository's Name, its
+// RelativePath, or a parent directory of that path, using filepath.Match
+// semantics on slash-separated paths.
+func matchRepo(repo scanner.Repository, patterns []string) (string, bool) {
+	candidates := []string{repo.Name}
+	for rel := filepath.ToSlash(repo.RelativePath); rel != "." && rel != "/" && rel != ""; rel = path.Dir(rel) {
+		candidates = append(candidates, rel)
+	}
+	for _, pattern := range patterns {
+		for _, candidate := range candidates {
+			if ok, _ := filepath.Match(pattern, candidate); ok {
+				return pattern, true
+			}
+		}
+	}
+	return "", false
+}
+
+// validateGlobs checks that each --exclude-repo pattern is well formed.
+func validateGlobs(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid --exclude-repo pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
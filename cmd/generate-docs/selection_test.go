@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+var discovered = []scanner.Repository{
+	{Name: "api", RelativePath: "services/api"},
+	{Name: "web", RelativePath: "apps/web"},
+	{Name: "worker", RelativePath: "services/worker"},
+	{Name: "docs", RelativePath: "docs"},
+}
+
+func repoNames(repos []scanner.Repository) []string {
+	var names []string
+	for _, r := range repos {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+func TestSelectRepositories_Only(t *testing.T) {
+	tests := []struct {
+		name    string
+		only    string
+		want    []string
+		wantErr string
+	}{
+		{"names keep discovery order", "worker, api", []string{"api", "worker"}, ""},
+		{"relative path", "apps/web", []string{"web"}, ""},
+		{"unknown name", "api,billing", nil, `no discovered repository named "billing"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config{only: tt.only}
+			got, err := selectRepositories(cfg, discovered, terminal{}, logger.New(false))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("selectRepositories() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectRepositories() error = %v", err)
+			}
+			if names := repoNames(got); !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("selectRepositories() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectRepositories_Select(t *testing.T) {
+	var out bytes.Buffer
+	term := terminal{in: strings.NewReader("9\n2-3, 1\n"), out: &out, interactive: true}
+
+	got, err := selectRepositories(&config{selectRepos: true}, discovered, term, logger.New(false))
+	if err != nil {
+		t.Fatalf("selectRepositories() error = %v", err)
+	}
+	if names := repoNames(got); !reflect.DeepEqual(names, []string{"api", "web", "worker"}) {
+		t.Errorf("selectRepositories() = %v, want [api web worker]", names)
+	}
+	if !strings.Contains(out.String(), "  4) docs (docs)") {
+		t.Errorf("prompt should list the repositories, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), `invalid selection "9"`) {
+		t.Errorf("prompt should reject an out-of-range index, got:\n%s", out.String())
+	}
+}
+
+func TestSelectRepositories_SelectRequiresTerminal(t *testing.T) {
+	_, err := selectRepositories(&config{selectRepos: true}, discovered, terminal{}, logger.New(false))
+	if err == nil || !strings.Contains(err.Error(), "--only") {
+		t.Errorf("selectRepositories() error = %v, want a hint to use --only", err)
+	}
+}
+
+func TestFileTerminal_NotATerminal(t *testing.T) {
+	// /dev/null is a character device but not a terminal.
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	for name, in := range map[string]*os.File{"null device": devNull, "pipe": r} {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			term := fileTerminal(in, &out)
+			if term.interactive {
+				t.Fatalf("fileTerminal(%s).interactive = true, want false", in.Name())
+			}
+			_, err := selectRepositories(&config{selectRepos: true}, discovered, term, logger.New(false))
+			if err == nil || !strings.Contains(err.Error(), "--only") {
+				t.Errorf("selectRepositories() error = %v, want a hint to use --only", err)
+			}
+			if out.Len() != 0 {
+				t.Errorf("selection prompt shown without a terminal: %q", out.String())
+			}
+		})
+	}
+}
+
+func TestParseIndexRanges(t *testing.T) {
+	tests := []struct {
+		answer  string
+		want    []int
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"  \n", nil, false},
+		{"2", []int{1}, false},
+		{"1-3,5", []int{0, 1, 2, 4}, false},
+		{"3,1-2,2", []int{0, 1, 2}, false},
+		{"0", nil, true},
+		{"6", nil, true},
+		{"3-1", nil, true},
+		{"a-b", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseIndexRanges(tt.answer, 5)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseIndexRanges(%q) error = %v, wantErr %v", tt.answer, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseIndexRanges(%q) = %v, want %v", tt.answer, got, tt.want)
+		}
+	}
+}
+
+func TestParseFlags_SelectConflicts(t *testing.T) {
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{"--select", "--only", "api", "/x"}, "cannot be used together"},
+		{[]string{"--select", "--watch", "/x"}, "cannot be used with --watch"},
+	}
+
+	for _, tt := range tests {
+		_, err := parseFlags(tt.args)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("parseFlags(%v) error = %v, want containing %q", tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+func TestExcludeRepositories(t *testing.T) {
+	repos := []scanner.Repository{
+		{Name: "api", RelativePath: "services/api"},
+		{Name: "api-archived", RelativePath: "archive/api-archived"},
+		{Name: "legacy", RelativePath: "archive/old/legacy"},
+		{Name: "demo", RelativePath: "examples/demo"},
+		{Name: "web", RelativePath: "apps/web"},
+	}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+	}{
+		{"no patterns keeps all", nil, []string{"api", "api-archived", "legacy", "demo", "web"}},
+		{"by name", []string{"*-archived"}, []string{"api", "legacy", "demo", "web"}},
+		{"by exact name", []string{"web"}, []string{"api", "api-archived", "legacy", "demo"}},
+		{"by path glob", []string{"examples/*"}, []string{"api", "api-archived", "legacy", "web"}},
+		{"by path prefix", []string{"archive"}, []string{"api", "demo", "web"}},
+		{"repeated patterns", []string{"archive", "apps/*"}, []string{"api", "demo"}},
+		{"star does not cross directories", []string{"services/*/x"}, []string{"api", "api-archived", "legacy", "demo", "web"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := repoNames(excludeRepositories(repos, tt.patterns, logger.New(false)))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("excludeRepositories(%v) = %v, want %v", tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFlags_ExcludeRepo(t *testing.T) {
+	cfg, err := parseFlags([]string{"--exclude-repo", "archive", "--exclude-repo", "examples/*", "/x"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	if want := (stringList{"archive", "examples/*"}); !reflect.DeepEqual(cfg.excludeRepos, want) {
+		t.Errorf("excludeRepos = %v, want %v", cfg.excludeRepos, want)
+	}
+
+	if _, err := parseFlags([]string{"--exclude-repo", "[", "/x"}); err == nil || !strings.Contains(err.Error(), "invalid --exclude-repo") {
+		t.Errorf("parseFlags() error = %v, want invalid pattern error", err)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// sinceDateLayouts are the date formats accepted by --since, tried in
+// order.
+var sinceDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"2 Jan 2006",
+	"2 January 2006",
+}
+
+// sinceDayUnits maps the day-based duration suffixes accepted by --since,
+// which time.ParseDuration does not know, to their length in days.
+var sinceDayUnits = map[byte]int{
+	'd': 1,
+	'w': 7,
+}
+
+// parseSince converts a --since value into a cutoff time. The value is
+// either a date in one of sinceDateLayouts (dates without a zone are
+// local) or a duration before now such as "30d", "2w", or "36h".
+func parseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("--since must not be empty")
+	}
+	for _, layout := range sinceDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if days, ok := sinceDayUnits[value[len(value)-1]]; ok {
+		if n, err := strconv.Atoi(value[:len(value)-1]); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n*days), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: want a date such as 2024-01-01 or a duration such as 30d", value)
+}
+
+// dropReposBefore keeps the repositories whose last commit is at or after
+// cutoff, logging each one dropped. Repositories whose last commit date is
+// unknown are kept.
+func dropReposBefore(repos []scanner.Repository, cutoff time.Time, log *logger.Logger) []scanner.Repository {
+	var kept []scanner.Repository
+	for _, repo := range repos {
+		if repo.LastCommitDate.IsZero() {
+			log.Debug("Keeping %s: last commit date unknown", repo.RelativePath)
+		} else if repo.LastCommitDate.Before(cutoff) {
+			log.Info("Skipping %s: last commit %s predates --since %s",
+				repo.RelativePath, repo.LastCommitDate.Format("2006-01-02"), cutoff.Format("2006-01-02"))
+			continue
+		}
+		kept = append(kept, repo)
+	}
+	return kept
+}
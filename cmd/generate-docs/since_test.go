@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+	"github.com/bordenet/codebase-reviewer/pkg/reviewer"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2024-01-01", want: time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)},
+		{value: "2024/01/01", want: time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)},
+		{value: "Jan 2, 2024", want: time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local)},
+		{value: "2024-01-01T08:00:00Z", want: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)},
+		{value: "30d", want: now.AddDate(0, 0, -30)},
+		{value: "2w", want: now.AddDate(0, 0, -14)},
+		{value: "36h", want: now.Add(-36 * time.Hour)},
+		{value: "yesterday", wantErr: true},
+		{value: "-5d", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseSince(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSince(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("parseSince(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFlags_InvalidSince(t *testing.T) {
+	if _, err := parseFlags([]string{"--since", "last tuesday", "/x"}); err == nil || !strings.Contains(err.Error(), "invalid --since") {
+		t.Errorf("parseFlags() error = %v, want invalid --since", err)
+	}
+}
+
+// writeCommitLog makes dir a git repository whose last commit was at when.
+func writeCommitLog(t *testing.T, dir string, when time.Time) {
+	t.Helper()
+	logs := filepath.Join(dir, ".git", "logs")
+	if err := os.MkdirAll(logs, 0755); err != nil {
+		t.Fatal(err)
+	}
+	entry := fmt.Sprintf("%040d %040d Dev <dev@example.com> %d +0000\tcommit: work\n", 0, 1, when.Unix())
+	if err := os.WriteFile(filepath.Join(logs, "HEAD"), []byte(entry), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDropReposBefore(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+	writeCommitLog(t, filepath.Join(root, "stale"), now.AddDate(-2, 0, 0))
+	writeCommitLog(t, filepath.Join(root, "fresh"), now.AddDate(0, 0, -3))
+	if err := os.MkdirAll(filepath.Join(root, "unknown", ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	repos, err := reviewer.Discover(context.Background(), root, (&config{}).scanOptions(nil), logger.New(false))
+	if err != nil {
+		t.Fatalf("reviewer.Discover() error = %v", err)
+	}
+	cutoff, err := parseSince("30d", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var logged []string
+	log := logger.New(false)
+	log.OnLog(func(_ logger.Level, msg string) { logged = append(logged, msg) })
+
+	got := repoNames(dropReposBefore(repos, cutoff, log))
+	if strings.Join(got, ",") != "fresh,unknown" {
+		t.Errorf("kept repos = %v, want [fresh unknown]", got)
+	}
+	if !strings.Contains(strings.Join(logged, "\n"), "Skipping stale: last commit") {
+		t.Errorf("stale repo should be logged as skipped, got %q", logged)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sizeUnits maps the suffixes accepted by byteSize to their multiplier.
+// Units are binary: 1KB is 1024 bytes.
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"G", 1 << 30},
+	{"M", 1 << 20},
+	{"K", 1 << 10},
+	{"B", 1},
+}
+
+// byteSize is a flag holding a positive size in bytes, written as a plain
+// number of bytes or with a unit such as "500KB", "5MB", or "1.5GB".
+type byteSize int64
+
+func (s *byteSize) String() string { return formatByteSize(int64(*s)) }
+
+func (s *byteSize) Set(value string) error {
+	n, err := parseByteSize(value)
+	if err != nil {
+		return err
+	}
+	*s = byteSize(n)
+	return nil
+}
+
+// parseByteSize parses a size such as "5MB" into bytes. It rejects values
+// that are not positive.
+func parseByteSize(value string) (int64, error) {
+	v := strings.ToUpper(strings.TrimSpace(value))
+	multiplier := int64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(v, u.suffix) {
+			v = strings.TrimSpace(strings.TrimSuffix(v, u.suffix))
+			multiplier = u.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil || n*float64(multiplier) < 1 {
+		return 0, fmt.Errorf("invalid size %q: want a positive size such as 5MB, 500KB, or 1048576", value)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// formatByteSize renders n with the largest unit that divides it evenly,
+// so that a parsed default such as 5MB prints back as "5MB".
+func formatByteSize(n int64) string {
+	for _, u := range sizeUnits[:3] {
+		if n >= u.bytes && n%u.bytes == 0 {
+			return fmt.Sprintf("%d%s", n/u.bytes, u.suffix)
+		}
+	}
+	return strconv.FormatInt(n, 10)
+}
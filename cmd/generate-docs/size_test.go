@@ -0,0 +1,73 @@
+package main
+
+import "testing"
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{"1048576", 1 << 20, false},
+		{"5MB", 5 << 20, false},
+		{"5mb", 5 << 20, false},
+		{"500 KB", 500 << 10, false},
+		{"1.5G", 3 << 29, false},
+		{"100B", 100, false},
+		{"0", 0, true},
+		{"-5MB", 0, true},
+		{"MB", 0, true},
+		{"5XB", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseByteSize(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseByteSize(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseByteSize(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{5 << 20, "5MB"},
+		{1 << 30, "1GB"},
+		{1536 << 10, "1536KB"},
+		{1000, "1000"},
+	}
+	for _, tt := range tests {
+		if got := formatByteSize(tt.n); got != tt.want {
+			t.Errorf("formatByteSize(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestParseFlags_LargeFileThreshold(t *testing.T) {
+	cfg, err := parseFlags([]string{"/x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.scanOptions(nil).LargeFileThreshold; got != 5<<20 {
+		t.Errorf("default LargeFileThreshold = %d, want %d", got, 5<<20)
+	}
+
+	cfg, err = parseFlags([]string{"--large-file-threshold", "20MB", "/x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.scanOptions(nil).LargeFileThreshold; got != 20<<20 {
+		t.Errorf("LargeFileThreshold = %d, want %d", got, 20<<20)
+	}
+
+	if _, err := parseFlags([]string{"--large-file-threshold", "big", "/x"}); err == nil {
+		t.Error("parseFlags() error = nil, want invalid size")
+	}
+}
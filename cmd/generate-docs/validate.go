@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bordenet/codebase-reviewer/internal/prompt"
+)
+
+// validateTemplateFile checks the prompt template at path, writing each
+// problem (or an OK line) to w. It returns the process exit code: 0 when
+// the template is valid and 1 otherwise. Variables the template uses but
+// this version does not provide, or required ones it omits, are reported
+// as warnings without failing validation.
+func validateTemplateFile(path string, w io.Writer) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(w, "%s: %v\n", path, err)
+		return 1
+	}
+
+	tmpl, err := prompt.ParseTemplate(data, path)
+	if err != nil {
+		fmt.Fprintf(w, "%s: invalid YAML: %v\n", path, err)
+		return 1
+	}
+
+	for _, warning := range prompt.CompatibilityWarnings(tmpl) {
+		fmt.Fprintf(w, "%s: warning: %s\n", path, warning)
+	}
+
+	errs := prompt.ValidateTemplate(tmpl)
+	if len(errs) == 0 {
+		fmt.Fprintf(w, "%s: OK\n", path)
+		return 0
+	}
+	for _, err := range errs {
+		fmt.Fprintf(w, "%s: %v\n", path, err)
+	}
+	fmt.Fprintf(w, "%s: %d problem(s) found\n", path, len(errs))
+	return 1
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateTemplateFile(t *testing.T) {
+	valid := `metadata:
+  version: "1.0"
+prompt:
+  tasks:
+    - task_id: T1
+      name: Analyze
+  output_requirements:
+    primary_output: out.md
+`
+	missingTasks := `metadata:
+  version: "1.0"
+prompt:
+  output_requirements:
+    primary_output: out.md
+`
+	tests := []struct {
+		name     string
+		content  string
+		wantCode int
+		want     string
+	}{
+		{"valid", valid, 0, ": OK"},
+		{"missing tasks", missingTasks, 1, "prompt.tasks: missing"},
+		{"invalid yaml", "metadata: [", 1, "invalid YAML"},
+		{"retired variable warns", strings.Replace(valid, "out.md", `"{{OLD_DIR}}/out.md"`, 1), 0, "warning: template uses variables this version does not provide: OLD_DIR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "template.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			if code := validateTemplateFile(path, &out); code != tt.wantCode {
+				t.Errorf("validateTemplateFile() = %d, want %d; output:\n%s", code, tt.wantCode, out.String())
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("output should contain %q, got:\n%s", tt.want, out.String())
+			}
+		})
+	}
+}
+
+func TestValidateTemplateFile_Missing(t *testing.T) {
+	var out bytes.Buffer
+	if code := validateTemplateFile(filepath.Join(t.TempDir(), "nope.yaml"), &out); code != 1 {
+		t.Errorf("validateTemplateFile() = %d, want 1 for a missing file", code)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+	"github.com/bordenet/codebase-reviewer/pkg/reviewer"
+	"github.com/fsnotify/fsnotify"
+)
+
+// defaultWatchInterval is how long the tree must be quiet after a change
+// before --watch regenerates.
+const defaultWatchInterval = 2 * time.Second
+
+// debouncer coalesces bursts of file events into a single regeneration
+// once no event has arrived for interval.
+type debouncer struct {
+	interval   time.Duration
+	regenerate func()
+	// onEvent, if set, sees every event before debouncing.
+	onEvent func(fsnotify.Event)
+	log     *logger.Logger
+}
+
+// run processes events until stop is closed or a channel is closed.
+// Watcher errors are logged and do not end the loop.
+func (d *debouncer) run(events <-chan fsnotify.Event, errs <-chan error, stop <-chan struct{}) {
+	var timer *time.Timer
+	var fire <-chan time.Time
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			d.log.Debug("File event: %s", ev)
+			if d.onEvent != nil {
+				d.onEvent(ev)
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.NewTimer(d.interval)
+			fire = timer.C
+		case err, ok := <-errs:
+			if !ok {
+				return
+			}
+			d.log.Warn("File watcher error: %v", err)
+		case <-fire:
+			fire = nil
+			d.regenerate()
+		}
+	}
+}
+
+// watchAndRegenerate watches the target tree and re-runs the pipeline after
+// each burst of changes until ctx is cancelled.
+func watchAndRegenerate(ctx context.Context, cfg *config, absPath string, log *logger.Logger) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	defer w.Close()
+
+	opts := cfg.scanOptions(log)
+	outputDir := watchedOutputBase(cfg)
+	if err := addWatchTree(w, absPath, opts, outputDir, log); err != nil {
+		return err
+	}
+
+	runs := 0
+	d := &debouncer{
+		interval: cfg.watchInterval,
+		log:      log,
+		onEvent: func(ev fsnotify.Event) {
+			// Watch directories created after startup.
+			if ev.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+					if err := addWatchTree(w, ev.Name, opts, outputDir, log); err != nil {
+						log.Warn("%v", err)
+					}
+				}
+			}
+		},
+		regenerate: func() {
+			runs++
+			log.Info("")
+			log.Info("Change detected; regenerating (run %d)...", runs)
+			if err := run(ctx, cfg, absPath, log); err != nil {
+				log.Error("Regeneration failed: %v", err)
+			}
+		},
+	}
+
+	log.Info("")
+	log.Info("Watching %s for changes (Ctrl+C to stop)...", absPath)
+	d.run(w.Events, w.Errors, ctx.Done())
+	log.Info("Stopped watching")
+	return nil
+}
+
+// watchedOutputBase returns the directory every output directory is
+// created under, --output-dir or reviewer.DefaultOutputBase. --watch
+// leaves it unwatched so writing the outputs does not trigger another run.
+func watchedOutputBase(cfg *config) string {
+	if cfg.outputDir == "" {
+		return reviewer.DefaultOutputBase
+	}
+	return filepath.Clean(cfg.outputDir)
+}
+
+// addWatchTree adds root and its subdirectories to w, skipping directories
+// the scanner skips and outputDir.
+func addWatchTree(w *fsnotify.Watcher, root string, opts scanner.ScanOptions, outputDir string, log *logger.Logger) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		if path != root && opts.SkipsDir(info.Name()) {
+			return filepath.SkipDir
+		}
+		if path == outputDir || strings.HasPrefix(path, outputDir+string(filepath.Separator)) {
+			return filepath.SkipDir
+		}
+		if err := w.Add(path); err != nil {
+			log.Warn("Cannot watch %s: %v", path, err)
+		}
+		return nil
+	})
+}
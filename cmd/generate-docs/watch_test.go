@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+	"github.com/bordenet/codebase-reviewer/pkg/reviewer"
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestDebouncer_CoalescesEvents(t *testing.T) {
+	var buf bytes.Buffer
+	events := make(chan fsnotify.Event)
+	errs := make(chan error)
+	stop := make(chan struct{})
+	regenerated := make(chan struct{}, 10)
+
+	var runs int32
+	d := &debouncer{
+		interval: 50 * time.Millisecond,
+		log:      logger.NewWithWriter(&buf, false),
+		regenerate: func() {
+			atomic.AddInt32(&runs, 1)
+			regenerated <- struct{}{}
+		},
+	}
+	done := make(chan struct{})
+	go func() {
+		d.run(events, errs, stop)
+		close(done)
+	}()
+
+	// A burst of events and a watcher error, all inside one interval.
+	for i := 0; i < 5; i++ {
+		events <- fsnotify.Event{Name: "main.go", Op: fsnotify.Write}
+	}
+	errs <- errors.New("queue overflow")
+
+	select {
+	case <-regenerated:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no regeneration after events")
+	}
+	// Give a spurious second regeneration a chance to happen.
+	time.Sleep(150 * time.Millisecond)
+	close(stop)
+	<-done
+
+	if got := atomic.LoadInt32(&runs); got != 1 {
+		t.Errorf("regenerations = %d, want 1", got)
+	}
+	if !strings.Contains(buf.String(), "queue overflow") {
+		t.Errorf("watcher error not logged:\n%s", buf.String())
+	}
+}
+
+func TestAddWatchTree_SkipsDirectories(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"src", "node_modules/pkg", ".git/objects", "generated", "out"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Skipf("fsnotify unavailable: %v", err)
+	}
+	defer w.Close()
+
+	opts := scanner.ScanOptions{SkipDirs: []string{"generated"}}
+	if err := addWatchTree(w, root, opts, filepath.Join(root, "out"), logger.New(false)); err != nil {
+		t.Fatalf("addWatchTree() error = %v", err)
+	}
+
+	got := make(map[string]bool)
+	for _, p := range w.WatchList() {
+		rel, _ := filepath.Rel(root, p)
+		got[rel] = true
+	}
+	if !got["."] || !got["src"] {
+		t.Errorf("watch list %v should include root and src", got)
+	}
+	for _, skipped := range []string{"node_modules", ".git", "generated", "out"} {
+		if got[skipped] {
+			t.Errorf("watch list should not include %s: %v", skipped, got)
+		}
+	}
+}
+
+func TestWatchedOutputBase(t *testing.T) {
+	custom := filepath.Join(t.TempDir(), "out")
+	tests := []struct {
+		name      string
+		outputDir string
+		want      string
+	}{
+		{"default", "", reviewer.DefaultOutputBase},
+		{"--output-dir", custom + string(filepath.Separator), custom},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := watchedOutputBase(&config{outputDir: tt.outputDir}); got != tt.want {
+				t.Errorf("watchedOutputBase() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
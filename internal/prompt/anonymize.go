@@ -0,0 +1,241 @@
+package prompt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// AnonymizationMapFileName is the file, written next to an anonymized
+// prompt, that maps each token back to the text it replaced.
+const AnonymizationMapFileName = "anonymization-map.json"
+
+// Tokens standing for the scan root and the output directory in an
+// anonymized prompt.
+const (
+	rootToken      = "<root>"
+	outputDirToken = "<output_dir>"
+)
+
+// anonymizer replaces repository and file paths, repository names, and
+// remote URLs with stable tokens such as <repo1>/<dir2>/<file3>.go. Each
+// path segment gets its own token, so the prompt keeps its structure and
+// file extensions, and replacing every token with its mapping restores
+// the original text (see Deanonymize). Tokens are numbered in the order
+// they are first needed, so the same input always yields the same tokens.
+type anonymizer struct {
+	root    string
+	tokens  map[string]string
+	counts  map[string]int
+	mapping map[string]string
+}
+
+// newAnonymizer returns an anonymizer for repositories scanned under root.
+func newAnonymizer(root, outputDir string) *anonymizer {
+	return &anonymizer{
+		root:    root,
+		tokens:  make(map[string]string),
+		counts:  make(map[string]int),
+		mapping: map[string]string{rootToken: root, outputDirToken: outputDir},
+	}
+}
+
+// token returns the token of the given kind for key, allocating the next
+// one on first use and recording that it stands for text.
+func (an *anonymizer) token(kind, key, text string) string {
+	k := kind + "\x00" + key
+	if t, ok := an.tokens[k]; ok {
+		return t
+	}
+	an.counts[kind]++
+	t := fmt.Sprintf("<%s%d>", kind, an.counts[kind])
+	an.tokens[k] = t
+	an.mapping[t] = text
+	return t
+}
+
+// relPath anonymizes rel, a slash-separated path relative to base, one
+// segment at a time. The last segment is a directory when dir is set and
+// otherwise a file, whose extension is kept. "" and "." are returned as is.
+func (an *anonymizer) relPath(base, rel string, dir bool) string {
+	if rel == "" || rel == "." {
+		return rel
+	}
+	segs := strings.Split(rel, "/")
+	out := make([]string, len(segs))
+	for i, seg := range segs {
+		key := base + "/" + strings.Join(segs[:i+1], "/")
+		if dir || i < len(segs)-1 {
+			out[i] = an.token("dir", key, seg)
+			continue
+		}
+		ext := path.Ext(seg)
+		stem := strings.TrimSuffix(seg, ext)
+		if stem == "" {
+			stem, ext = seg, ""
+		}
+		out[i] = an.token("file", key, stem) + ext
+	}
+	return strings.Join(out, "/")
+}
+
+// repository anonymizes a repository's name, paths, remote, and
+// submodules. The repository at the scan root becomes <root>; one below it
+// keeps its directory structure, as in <root>/<dir1>/<repo2>.
+func (an *anonymizer) repository(r scanner.Repository) scanner.Repository {
+	real := r.Path
+	rel := filepath.ToSlash(r.RelativePath)
+	switch {
+	case filepath.Clean(real) == filepath.Clean(an.root):
+		r.Name, r.Path = rootToken, rootToken
+	case rel == "" || rel == "." || rel == ".." || strings.HasPrefix(rel, "../"):
+		// Outside the scan root, as with --repos-from.
+		tok := an.token("repo", real, real)
+		r.Name, r.Path, r.RelativePath = tok, tok, tok
+	default:
+		tok := an.token("repo", real, filepath.Base(real))
+		if parent := path.Dir(rel); parent != "." {
+			tok = an.relPath(an.root, parent, true) + "/" + tok
+		}
+		r.Name, r.Path, r.RelativePath = path.Base(tok), rootToken+"/"+tok, tok
+	}
+	if r.RemoteURL != "" {
+		r.RemoteURL = an.token("remote", r.RemoteURL, r.RemoteURL)
+	}
+	if r.Submodules != nil {
+		subs := make([]scanner.Submodule, len(r.Submodules))
+		for i, s := range r.Submodules {
+			p := an.relPath(real, filepath.ToSlash(s.Path), true)
+			subs[i] = scanner.Submodule{Name: p, Path: p}
+			if s.URL != "" {
+				subs[i].URL = an.token("remote", s.URL, s.URL)
+			}
+		}
+		r.Submodules = subs
+	}
+	return r
+}
+
+// analysis returns an anonymized copy of a, sharing nothing that it
+// changes with the original.
+func (an *anonymizer) analysis(a *scanner.RepositoryAnalysis) *scanner.RepositoryAnalysis {
+	real := a.Repository.Path
+	out := *a
+	out.Repository = an.repository(a.Repository)
+
+	paths := make([]string, 0, len(a.Files))
+	for p := range a.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	out.Files = make(map[string]scanner.FileStat, len(a.Files))
+	for _, p := range paths {
+		out.Files[an.relPath(real, p, false)] = a.Files[p]
+	}
+
+	files := func(list []string) []string {
+		if list == nil {
+			return nil
+		}
+		anon := make([]string, len(list))
+		for i, p := range list {
+			anon[i] = an.relPath(real, p, false)
+		}
+		return anon
+	}
+	out.Docs = scanner.DocInventory{
+		READMEs:      files(a.Docs.READMEs),
+		Docs:         files(a.Docs.Docs),
+		ADRs:         files(a.Docs.ADRs),
+		Changelogs:   files(a.Docs.Changelogs),
+		Contributing: files(a.Docs.Contributing),
+	}
+	out.SkippedPaths = files(a.SkippedPaths)
+
+	out.LargeFiles = nil
+	for _, f := range a.LargeFiles {
+		out.LargeFiles = append(out.LargeFiles, scanner.LargeFile{Path: an.relPath(real, f.Path, false), SizeBytes: f.SizeBytes})
+	}
+	out.Secrets = nil
+	for _, s := range a.Secrets {
+		s.Path = an.relPath(real, s.Path, false)
+		out.Secrets = append(out.Secrets, s)
+	}
+	out.Services = nil
+	for _, s := range a.Services {
+		anon := scanner.Service{Kind: s.Kind, Path: an.relPath(real, s.Path, true)}
+		anon.Name = an.token("service", real+"\x00"+s.Name, s.Name)
+		out.Services = append(out.Services, anon)
+	}
+	out.GoModules = nil
+	for _, m := range a.GoModules {
+		anon := scanner.GoModule{Dir: an.relPath(real, m.Dir, true), GoVersion: m.GoVersion}
+		if m.Path != "" {
+			anon.Path = an.token("module", m.Path, m.Path)
+		}
+		out.GoModules = append(out.GoModules, anon)
+	}
+	return &out
+}
+
+// anonymize returns anonymized copies of the repositories and analyses
+// passed to buildTemplateVars.
+func (an *anonymizer) anonymize(repos []scanner.Repository, analyses []*scanner.RepositoryAnalysis) ([]scanner.Repository, []*scanner.RepositoryAnalysis) {
+	anonRepos := make([]scanner.Repository, len(repos))
+	for i, r := range repos {
+		anonRepos[i] = an.repository(r)
+	}
+	anonAnalyses := make([]*scanner.RepositoryAnalysis, len(analyses))
+	for i, a := range analyses {
+		anonAnalyses[i] = an.analysis(a)
+	}
+	return anonRepos, anonAnalyses
+}
+
+// writeAnonymizationMap writes the token mapping as JSON to outputDir.
+func (an *anonymizer) writeAnonymizationMap(outputDir string, dryRun bool, log *logger.Logger) error {
+	data, err := json.MarshalIndent(an.mapping, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(outputDir, AnonymizationMapFileName)
+	if err := writeOutput(path, append(data, '\n'), dryRun, log); err != nil {
+		return fmt.Errorf("failed to write anonymization map: %w", err)
+	}
+	if !dryRun {
+		log.Info("Anonymization map written: %s (keep it private)", path)
+	}
+	return nil
+}
+
+// ReadAnonymizationMap reads a mapping written alongside an anonymized
+// prompt.
+func ReadAnonymizationMap(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var mapping map[string]string
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("invalid anonymization map %s: %w", path, err)
+	}
+	return mapping, nil
+}
+
+// Deanonymize replaces every token in text with the original it stands
+// for in mapping, such as an LLM's answer to an anonymized prompt. JSON
+// outputs escape < and > in tokens, so decode them first.
+func Deanonymize(text string, mapping map[string]string) string {
+	pairs := make([]string, 0, 2*len(mapping))
+	for token, original := range mapping {
+		pairs = append(pairs, token, original)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
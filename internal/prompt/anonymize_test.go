@@ -0,0 +1,157 @@
+package prompt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// writeFiles creates files, keyed by slash-separated path, under dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGenerate_AnonymizeLeaksNoPaths(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "workspace-zq7")
+	writeFiles(t, root, map[string]string{
+		"services/billing-zq7/.git/HEAD":                      "ref: refs/heads/main\n",
+		"services/billing-zq7/.git/config":                    "[remote \"origin\"]\n\turl = https://github.com/acme-zq7/billing.git\n",
+		"services/billing-zq7/go.mod":                         "module github.com/acme-zq7/billing\n\ngo 1.21\n",
+		"services/billing-zq7/cmd/billing-zq7-server/main.go": "package main\n\nfunc main() {}\n",
+		"services/billing-zq7/cmd/zq7-worker/main.go":         "package main\n\nfunc main() {}\n",
+		"services/billing-zq7/README.md":                      "# Billing\n",
+		"services/billing-zq7/docs/design-zq7.md":             "# Design\n",
+		"web-zq7/.git/HEAD":                                   "ref: refs/heads/main\n",
+		"web-zq7/src/zq7-app.ts":                              "export const app = 1;\n",
+		"web-zq7/src/zq7-app.test.ts":                         "test('app', () => {});\n",
+	})
+	repos, err := scanner.FindGitRepos(root, logger.New(false))
+	if err != nil || len(repos) != 2 {
+		t.Fatalf("FindGitRepos() = %v, %v", repos, err)
+	}
+	outputDir := filepath.Join(t.TempDir(), "out-zq7")
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := Options{
+		Anonymize: true,
+		Formats:   []string{FormatMarkdown, FormatYAML, FormatJSON},
+		Report:    ReportJSON,
+		NoCache:   true,
+	}
+	promptPath, err := Generate(context.Background(), root, repos, outputDir, opts, logger.New(false))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Name() == AnonymizationMapFileName {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(outputDir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, leak := range []string{root, outputDir, filepath.Dir(root), "zq7"} {
+			if strings.Contains(string(data), leak) {
+				t.Errorf("%s leaks %q", e.Name(), leak)
+			}
+		}
+	}
+
+	data, err := os.ReadFile(promptPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prompt := string(data)
+	for _, want := range []string{`"Name":"<repo1>"`, `"RelativePath":"<dir1>/<repo1>"`, `"Languages":{"Go":2,"Markdown":2}`, `"Languages":{"TypeScript":2}`, `"RemoteURL":"<remote1>"`} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("anonymized prompt is missing %q", want)
+		}
+	}
+
+	mapping, err := ReadAnonymizationMap(filepath.Join(outputDir, AnonymizationMapFileName))
+	if err != nil {
+		t.Fatalf("ReadAnonymizationMap() error = %v", err)
+	}
+	restored := Deanonymize(prompt, mapping)
+	for _, want := range []string{`"Name":"billing-zq7"`, `"RelativePath":"services/billing-zq7"`, `"Path":"` + filepath.Join(root, "web-zq7") + `"`, "https://github.com/acme-zq7/billing.git", outputDir} {
+		if !strings.Contains(restored, want) {
+			t.Errorf("de-anonymized prompt is missing %q", want)
+		}
+	}
+}
+
+func TestAnonymizer_StableTokens(t *testing.T) {
+	repos := []scanner.Repository{
+		{Path: "/src/ws", Name: "ws", RelativePath: "."},
+		{Path: "/src/ws/libs/core", Name: "core", RelativePath: "libs/core"},
+		{Path: "/elsewhere/tool", Name: "tool", RelativePath: "../../elsewhere/tool"},
+	}
+	analyses := []*scanner.RepositoryAnalysis{{
+		Repository: repos[1],
+		Files:      map[string]scanner.FileStat{"b/main.go": {}, "a/util.go": {}, ".gitignore": {}},
+		LargeFiles: []scanner.LargeFile{{Path: "b/main.go", SizeBytes: 10}},
+	}}
+
+	anonymizeOnce := func() ([]scanner.Repository, []*scanner.RepositoryAnalysis, map[string]string) {
+		an := newAnonymizer("/src/ws", "/tmp/out")
+		r, a := an.anonymize(repos, analyses)
+		return r, a, an.mapping
+	}
+	gotRepos, gotAnalyses, mapping := anonymizeOnce()
+	againRepos, againAnalyses, _ := anonymizeOnce()
+	if !reflect.DeepEqual(gotRepos, againRepos) || !reflect.DeepEqual(gotAnalyses, againAnalyses) {
+		t.Error("anonymize() is not stable across runs")
+	}
+
+	wantPaths := []string{"<root>", "<root>/<dir1>/<repo1>", "<repo2>"}
+	for i, want := range wantPaths {
+		if gotRepos[i].Path != want {
+			t.Errorf("repos[%d].Path = %q, want %q", i, gotRepos[i].Path, want)
+		}
+	}
+	if gotAnalyses[0].Repository.Path != gotRepos[1].Path {
+		t.Errorf("analysis repository %q does not match %q", gotAnalyses[0].Repository.Path, gotRepos[1].Path)
+	}
+	wantFiles := map[string]scanner.FileStat{"<file1>": {}, "<dir2>/<file2>.go": {}, "<dir3>/<file3>.go": {}}
+	if !reflect.DeepEqual(gotAnalyses[0].Files, wantFiles) {
+		t.Errorf("Files = %v, want %v", gotAnalyses[0].Files, wantFiles)
+	}
+	if got := gotAnalyses[0].LargeFiles[0].Path; got != "<dir3>/<file3>.go" {
+		t.Errorf("LargeFiles[0].Path = %q, want the token used in Files", got)
+	}
+	if analyses[0].Repository.Path != "/src/ws/libs/core" || !reflect.DeepEqual(analyses[0].Files["b/main.go"], scanner.FileStat{}) {
+		t.Error("anonymize() modified its input")
+	}
+
+	for anon, real := range map[string]string{
+		gotRepos[1].Path: "/src/ws/libs/core",
+		gotRepos[2].Path: "/elsewhere/tool",
+		"<root>/<dir1>/<repo1>/<dir3>/<file3>.go": "/src/ws/libs/core/b/main.go",
+	} {
+		if got := Deanonymize(anon, mapping); got != real {
+			t.Errorf("Deanonymize(%q) = %q, want %q", anon, got, real)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+)
+
+// buildCommandsLine renders the BUILD_COMMANDS variable: the likely build
+// command of each repository, prefixed with its name when more than one
+// repository was analyzed, as in "api: go build ./...; web: make or npm
+// run build (ambiguous)". A repository whose build system was not
+// recognized shows "none detected".
+func buildCommandsLine(analyses []*scanner.RepositoryAnalysis) string {
+	if len(analyses) == 0 {
+		return "none detected"
+	}
+	parts := make([]string, len(analyses))
+	for i, analysis := range analyses {
+		parts[i] = buildCommandsOf(analysis.Tooling.BuildCommands)
+		if len(analyses) > 1 {
+			parts[i] = analysis.Repository.Name + ": " + parts[i]
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// buildCommandsOf describes one repository's candidate build commands.
+func buildCommandsOf(cmds []string) string {
+	switch len(cmds) {
+	case 0:
+		return "none detected"
+	case 1:
+		return cmds[0]
+	}
+	return fmt.Sprintf("%s (ambiguous)", strings.Join(cmds, " or "))
+}
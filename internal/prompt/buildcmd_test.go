@@ -0,0 +1,52 @@
+package prompt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestBuildTemplateVars_BuildCommands(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, filepath.Join(root, "api"), map[string]string{
+		"go.mod":  "module example.com/api\n\ngo 1.21\n",
+		"main.go": "package main\n",
+	})
+	writeFiles(t, filepath.Join(root, "web"), map[string]string{
+		"Makefile":     "all:\n\tnpm run build\n",
+		"package.json": `{"scripts": {"build": "vite build"}}`,
+	})
+	writeFiles(t, filepath.Join(root, "notes"), map[string]string{"README.md": "# notes\n"})
+
+	analyze := func(name string) *scanner.RepositoryAnalysis {
+		t.Helper()
+		analysis, err := scanner.AnalyzeRepository(scanner.Repository{Path: filepath.Join(root, name), Name: name}, logger.New(false))
+		if err != nil {
+			t.Fatalf("AnalyzeRepository(%s) error = %v", name, err)
+		}
+		return analysis
+	}
+	api, web, notes := analyze("api"), analyze("web"), analyze("notes")
+
+	tests := []struct {
+		name     string
+		analyses []*scanner.RepositoryAnalysis
+		want     string
+	}{
+		{"go repo", []*scanner.RepositoryAnalysis{api}, "go build ./..."},
+		{"makefile and package.json", []*scanner.RepositoryAnalysis{web}, "make or npm run build (ambiguous)"},
+		{"several repos", []*scanner.RepositoryAnalysis{api, web, notes},
+			"api: go build ./...; web: make or npm run build (ambiguous); notes: none detected"},
+		{"no analyses", nil, "none detected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := buildTemplateVars(root, nil, tt.analyses, "/tmp/out", false, false)
+			if got := vars["BUILD_COMMANDS"]; got != tt.want {
+				t.Errorf("BUILD_COMMANDS = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// analyzeRepositories analyzes each repository, reusing the analysis cache
+// in outputDir when the codebase fingerprint is unchanged. The cache is
+// bypassed by Scorch or NoCache and is never written during a dry run. With
+// Incremental, a stale cache is brought up to date from the files git
+// reports as changed instead of re-walking each repository. A repository
+// that fails to analyze is logged and skipped, but if every repository
+// fails an error reporting how many is returned. Each completed analysis
+// is checkpointed in outputDir until every repository is done, and with
+// Resume the analyses checkpointed by an interrupted run are reused. The
+// fingerprint is returned when one was computed. Cancelling ctx aborts the
+// analysis with ctx.Err().
+func analyzeRepositories(ctx context.Context, repos []scanner.Repository, outputDir string, opts Options, log *logger.Logger) ([]*scanner.RepositoryAnalysis, string, error) {
+	useCache := !opts.Scorch && !opts.NoCache
+
+	var fingerprint string
+	if useCache || opts.Report != "" {
+		fp, err := scanner.Fingerprint(ctx, repos, opts.Scan)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, "", ctxErr
+		}
+		if err != nil {
+			log.Warn("Failed to fingerprint codebase; analysis cache disabled: %v", err)
+			useCache = false
+		}
+		fingerprint = fp
+	}
+
+	if useCache {
+		if cached, ok := scanner.LoadAnalysisCache(outputDir, fingerprint); ok {
+			log.Info("Codebase unchanged; reusing cached analysis")
+			return cached, fingerprint, nil
+		}
+	}
+
+	incremental := useCache && opts.Incremental
+	var previous map[string]*scanner.RepositoryAnalysis
+	if incremental {
+		previous = previousAnalyses(outputDir, opts.Scan)
+	}
+
+	done := resumedAnalyses(repos, outputDir, opts, log)
+	checkpoint := startCheckpoint(repos, done, outputDir, opts, log)
+	defer checkpoint.close()
+
+	var analyses []*scanner.RepositoryAnalysis
+	var failed int
+	var lastErr error
+	for _, repo := range repos {
+		if analysis, ok := done[repo.Path]; ok {
+			log.Debug("Skipping %s; analyzed before the interruption", repo.Name)
+			analyses = append(analyses, analysis)
+			continue
+		}
+		if incremental {
+			if analysis, ok := updateIncrementally(ctx, repo, previous[repo.Path], opts.Scan, log); ok {
+				checkpoint.add(analysis)
+				analyses = append(analyses, analysis)
+				continue
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, "", ctxErr
+			}
+		}
+		analysis, err := scanner.AnalyzeRepositoryWithOptions(ctx, repo, opts.Scan, log)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, "", ctxErr
+		}
+		if err != nil {
+			log.Warn("Failed to analyze %s: %v", repo.Name, err)
+			failed++
+			lastErr = err
+			continue
+		}
+		if n := len(analysis.SkippedPaths); n > 0 {
+			log.Warn("Could not read %d path(s) in %s; analysis is incomplete", n, repo.Name)
+		}
+		checkpoint.add(analysis)
+		analyses = append(analyses, analysis)
+	}
+	checkpoint.finish()
+
+	if len(analyses) == 0 && failed > 0 {
+		return nil, "", fmt.Errorf("all %d repositories failed to analyze; last error: %w", failed, lastErr)
+	}
+	if failed > 0 {
+		log.Warn("%d of %d repositories failed to analyze; the prompt notes which are missing", failed, len(repos))
+	}
+
+	if useCache && !opts.DryRun {
+		if err := scanner.SaveAnalysisCache(outputDir, fingerprint, opts.Scan, analyses); err != nil {
+			log.Warn("Failed to save analysis cache: %v", err)
+		}
+	}
+	return analyses, fingerprint, nil
+}
@@ -0,0 +1,146 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// seedCache writes a sentinel analysis for repos' current fingerprint so a
+// cache hit is distinguishable from a fresh walk.
+func seedCache(t *testing.T, repos []scanner.Repository, outputDir string) {
+	t.Helper()
+	fp, err := scanner.Fingerprint(context.Background(), repos, scanner.ScanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sentinel := []*scanner.RepositoryAnalysis{{Repository: repos[0], TotalFiles: 999}}
+	if err := scanner.SaveAnalysisCache(outputDir, fp, scanner.ScanOptions{}, sentinel); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAnalyzeRepositories_Cache(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      Options
+		touch     bool
+		wantFiles int
+	}{
+		{name: "hit reuses cached analysis", wantFiles: 999},
+		{name: "miss after fingerprint change", touch: true, wantFiles: 1},
+		{name: "no-cache bypasses cache", opts: Options{NoCache: true}, wantFiles: 1},
+		{name: "scorch bypasses cache", opts: Options{Scorch: true}, wantFiles: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := t.TempDir()
+			outputDir := t.TempDir()
+			file := filepath.Join(repoDir, "main.go")
+			if err := os.WriteFile(file, []byte("package main\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			repos := []scanner.Repository{{Path: repoDir, Name: "repo"}}
+			seedCache(t, repos, outputDir)
+
+			if tt.touch {
+				later := time.Now().Add(time.Hour)
+				if err := os.Chtimes(file, later, later); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			analyses, _, err := analyzeRepositories(context.Background(), repos, outputDir, tt.opts, logger.New(false))
+			if err != nil {
+				t.Fatalf("analyzeRepositories() error = %v", err)
+			}
+			if len(analyses) != 1 {
+				t.Fatalf("got %d analyses, want 1", len(analyses))
+			}
+			if analyses[0].TotalFiles != tt.wantFiles {
+				t.Errorf("TotalFiles = %d, want %d", analyses[0].TotalFiles, tt.wantFiles)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepositories_WritesCache(t *testing.T) {
+	repoDir := t.TempDir()
+	outputDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repos := []scanner.Repository{{Path: repoDir, Name: "repo"}}
+
+	if _, _, err := analyzeRepositories(context.Background(), repos, outputDir, Options{DryRun: true}, logger.New(false)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, scanner.CacheFileName)); !os.IsNotExist(err) {
+		t.Errorf("dry run wrote a cache file (stat err = %v)", err)
+	}
+
+	if _, _, err := analyzeRepositories(context.Background(), repos, outputDir, Options{}, logger.New(false)); err != nil {
+		t.Fatal(err)
+	}
+	fp, _ := scanner.Fingerprint(context.Background(), repos, scanner.ScanOptions{})
+	if _, ok := scanner.LoadAnalysisCache(outputDir, fp); !ok {
+		t.Error("analysis cache not written after a fresh analysis")
+	}
+}
+
+func TestGenerate_AllRepositoriesFail(t *testing.T) {
+	chdir(t, t.TempDir())
+	root := t.TempDir()
+	repos := []scanner.Repository{
+		{Path: filepath.Join(root, "gone-a"), Name: "gone-a"},
+		{Path: filepath.Join(root, "gone-b"), Name: "gone-b"},
+	}
+	outputDir := t.TempDir()
+
+	_, err := Generate(context.Background(), root, repos, outputDir, Options{NoCache: true}, logger.New(false))
+	if err == nil || !strings.Contains(err.Error(), "all 2 repositories failed") {
+		t.Fatalf("Generate() error = %v, want all 2 repositories to be reported as failed", err)
+	}
+	if !errors.Is(err, scanner.ErrPathNotFound) {
+		t.Errorf("Generate() error = %v, want the last analysis error wrapped", err)
+	}
+	if entries, _ := os.ReadDir(outputDir); len(entries) != 0 {
+		t.Errorf("nothing should be written when every repository fails, got %d entries", len(entries))
+	}
+}
+
+func TestGenerate_PartialFailure(t *testing.T) {
+	chdir(t, t.TempDir())
+	root := t.TempDir()
+	appDir := filepath.Join(root, "app")
+	if err := os.MkdirAll(appDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(appDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repos := []scanner.Repository{
+		{Path: appDir, Name: "app"},
+		{Path: filepath.Join(root, "gone"), Name: "gone"},
+	}
+
+	promptPath, err := Generate(context.Background(), root, repos, t.TempDir(), Options{NoCache: true}, logger.New(false))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	data, err := os.ReadFile(promptPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Note: 1 of 2 repositories (gone) could not be analyzed") {
+		t.Errorf("prompt should note the repository that failed to analyze:\n%s", data)
+	}
+}
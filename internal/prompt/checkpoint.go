@@ -0,0 +1,98 @@
+package prompt
+
+import (
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// resumedAnalyses returns, keyed by repository path, the analyses of repos
+// completed by an interrupted run whose checkpoint is in outputDir. It
+// returns nil unless opts.Resume is set, and always with opts.Scorch. A
+// repository with commits since its checkpointed analysis is analyzed
+// again.
+func resumedAnalyses(repos []scanner.Repository, outputDir string, opts Options, log *logger.Logger) map[string]*scanner.RepositoryAnalysis {
+	if !opts.Resume || opts.Scorch {
+		return nil
+	}
+	recorded, ok := scanner.LoadCheckpoint(outputDir, opts.Scan)
+	if !ok {
+		log.Info("No checkpoint to resume from; analyzing every repository")
+		return nil
+	}
+	done := make(map[string]*scanner.RepositoryAnalysis)
+	for _, repo := range repos {
+		analysis, ok := recorded[repo.Path]
+		if !ok {
+			continue
+		}
+		if analysis.Repository.LastCommitHash != repo.LastCommitHash {
+			log.Debug("%s has new commits since the checkpoint; analyzing it again", repo.Name)
+			continue
+		}
+		done[repo.Path] = analysis
+	}
+	log.Info("Resuming: %d of %d repositories already analyzed", len(done), len(repos))
+	return done
+}
+
+// checkpointer records completed analyses in a scanner.Checkpoint. A nil
+// checkpointer, as during a dry run, records nothing, and a failure to
+// write is logged once and stops further recording.
+type checkpointer struct {
+	dir        string
+	checkpoint *scanner.Checkpoint
+	log        *logger.Logger
+}
+
+// startCheckpoint starts recording analyses in outputDir, beginning with
+// those of repos found in done. It returns nil during a dry run.
+func startCheckpoint(repos []scanner.Repository, done map[string]*scanner.RepositoryAnalysis, outputDir string, opts Options, log *logger.Logger) *checkpointer {
+	if opts.DryRun {
+		return nil
+	}
+	var carried []*scanner.RepositoryAnalysis
+	for _, repo := range repos {
+		if analysis, ok := done[repo.Path]; ok {
+			carried = append(carried, analysis)
+		}
+	}
+	checkpoint, err := scanner.CreateCheckpoint(outputDir, opts.Scan, carried)
+	if err != nil {
+		log.Warn("Checkpointing disabled; an interrupted scan cannot be resumed: %v", err)
+		return nil
+	}
+	return &checkpointer{dir: outputDir, checkpoint: checkpoint, log: log}
+}
+
+// add records a completed analysis.
+func (c *checkpointer) add(analysis *scanner.RepositoryAnalysis) {
+	if c == nil || c.checkpoint == nil {
+		return
+	}
+	if err := c.checkpoint.Add(analysis); err != nil {
+		c.log.Warn("Checkpointing disabled; an interrupted scan cannot be resumed: %v", err)
+		c.checkpoint.Close()
+		c.checkpoint = nil
+	}
+}
+
+// close closes the checkpoint, leaving it for --resume.
+func (c *checkpointer) close() {
+	if c == nil || c.checkpoint == nil {
+		return
+	}
+	c.checkpoint.Close()
+	c.checkpoint = nil
+}
+
+// finish closes and removes the checkpoint once every repository has
+// been analyzed.
+func (c *checkpointer) finish() {
+	if c == nil {
+		return
+	}
+	c.close()
+	if err := scanner.RemoveCheckpoint(c.dir); err != nil {
+		c.log.Warn("%v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// checkpointRepos returns three single-file repositories, alpha, beta,
+// and gamma.
+func checkpointRepos(t *testing.T) []scanner.Repository {
+	t.Helper()
+	root := t.TempDir()
+	var repos []scanner.Repository
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		writeFiles(t, filepath.Join(root, name), map[string]string{"main.go": "package main\n"})
+		repos = append(repos, scanner.Repository{Path: filepath.Join(root, name), Name: name, LastCommitHash: name + "1"})
+	}
+	return repos
+}
+
+func TestAnalyzeRepositories_ResumeSkipsCheckpointedRepos(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      Options
+		newCommit bool
+		wantFiles []int
+	}{
+		// A sentinel of 999 files marks an analysis taken from the checkpoint.
+		{name: "resume skips completed repos", opts: Options{Resume: true}, wantFiles: []int{999, 999, 1}},
+		{name: "without resume every repo is analyzed", wantFiles: []int{1, 1, 1}},
+		{name: "scorch ignores the checkpoint", opts: Options{Resume: true, Scorch: true}, wantFiles: []int{1, 1, 1}},
+		{name: "repo with new commits is analyzed again", opts: Options{Resume: true}, newCommit: true, wantFiles: []int{1, 999, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := checkpointRepos(t)
+			outputDir := t.TempDir()
+			// A partial checkpoint, as left by a run interrupted in gamma.
+			checkpoint, err := scanner.CreateCheckpoint(outputDir, scanner.ScanOptions{}, []*scanner.RepositoryAnalysis{
+				{Repository: repos[0], TotalFiles: 999},
+				{Repository: repos[1], TotalFiles: 999},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkpoint.Close()
+			if tt.newCommit {
+				repos[0].LastCommitHash = "alpha2"
+			}
+
+			analyses, _, err := analyzeRepositories(context.Background(), repos, outputDir, tt.opts, logger.New(false))
+			if err != nil {
+				t.Fatalf("analyzeRepositories() error = %v", err)
+			}
+			if len(analyses) != len(tt.wantFiles) {
+				t.Fatalf("got %d analyses, want %d", len(analyses), len(tt.wantFiles))
+			}
+			for i, analysis := range analyses {
+				if analysis.Repository.Name != repos[i].Name {
+					t.Errorf("analyses[%d] is %s, want %s", i, analysis.Repository.Name, repos[i].Name)
+				}
+				if analysis.TotalFiles != tt.wantFiles[i] {
+					t.Errorf("%s TotalFiles = %d, want %d", repos[i].Name, analysis.TotalFiles, tt.wantFiles[i])
+				}
+			}
+			if _, err := os.Stat(filepath.Join(outputDir, scanner.CheckpointFileName)); !os.IsNotExist(err) {
+				t.Errorf("checkpoint left after a complete run: %v", err)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepositories_InterruptionLeavesCheckpoint(t *testing.T) {
+	repos := checkpointRepos(t)
+	outputDir := t.TempDir()
+
+	// Interrupt the run while it walks gamma.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scan := scanner.ScanOptions{ProgressInterval: 1}
+	var analyzed int
+	scan.OnProgress = func(int) {
+		if analyzed++; analyzed == len(repos) {
+			cancel()
+		}
+	}
+	_, _, err := analyzeRepositories(ctx, repos, outputDir, Options{NoCache: true, Scan: scan}, logger.New(false))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("analyzeRepositories() error = %v, want context.Canceled", err)
+	}
+
+	done, ok := scanner.LoadCheckpoint(outputDir, scanner.ScanOptions{})
+	if !ok {
+		t.Fatal("LoadCheckpoint() missed after an interrupted run")
+	}
+	if len(done) != 2 || done[repos[0].Path] == nil || done[repos[1].Path] == nil {
+		t.Errorf("checkpoint holds %v, want alpha and beta", done)
+	}
+
+	analyses, _, err := analyzeRepositories(context.Background(), repos, outputDir, Options{NoCache: true, Resume: true}, logger.New(false))
+	if err != nil {
+		t.Fatalf("resumed analyzeRepositories() error = %v", err)
+	}
+	if len(analyses) != len(repos) {
+		t.Errorf("resumed run returned %d analyses, want %d", len(analyses), len(repos))
+	}
+}
+
+func TestAnalyzeRepositories_DryRunWritesNoCheckpoint(t *testing.T) {
+	repos := checkpointRepos(t)
+	outputDir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	scan := scanner.ScanOptions{ProgressInterval: 1, OnProgress: func(int) { cancel() }}
+
+	if _, _, err := analyzeRepositories(ctx, repos, outputDir, Options{DryRun: true, Scan: scan}, logger.New(false)); err == nil {
+		t.Fatal("analyzeRepositories() error = nil, want context.Canceled")
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, scanner.CheckpointFileName)); !os.IsNotExist(err) {
+		t.Errorf("dry run wrote a checkpoint: %v", err)
+	}
+}
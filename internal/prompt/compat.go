@@ -0,0 +1,88 @@
+package prompt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// requiredTemplateVars are the variables a prompt template must use for
+// the prompt to be actionable: what to analyze, the scan results, and
+// where to write outputs.
+var requiredTemplateVars = []string{"TARGET_PATH", "NESTED_REPOS", "OUTPUT_DIR"}
+
+// TemplateVarNames returns the names of the variables Generate substitutes
+// into templates, sorted.
+func TemplateVarNames() []string {
+	vars := buildTemplateVars("", nil, nil, "", false, false)
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckTemplateCompatibility compares the {{VAR}} placeholders used in a
+// parsed template against knownVars. It returns the required variables the
+// template never uses and the placeholders that are not known, such as
+// variables retired in a newer version; both are sorted.
+func CheckTemplateCompatibility(data map[string]interface{}, knownVars []string) (missing, unknown []string) {
+	used := make(map[string]bool)
+	collectPlaceholders(data, used)
+
+	known := make(map[string]bool, len(knownVars))
+	for _, name := range knownVars {
+		known[name] = true
+	}
+	for _, name := range requiredTemplateVars {
+		if !used[name] {
+			missing = append(missing, name)
+		}
+	}
+	for name := range used {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return missing, unknown
+}
+
+// collectPlaceholders adds the names of the {{VAR}} placeholders in every
+// string within value to used.
+func collectPlaceholders(value interface{}, used map[string]bool) {
+	switch v := value.(type) {
+	case string:
+		for _, token := range placeholderPattern.FindAllString(v, -1) {
+			used[strings.TrimSuffix(strings.TrimPrefix(token, "{{"), "}}")] = true
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			collectPlaceholders(item, used)
+		}
+	case map[interface{}]interface{}:
+		for _, item := range v {
+			collectPlaceholders(item, used)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectPlaceholders(item, used)
+		}
+	}
+}
+
+// CompatibilityWarnings checks a parsed template against the variables
+// this version provides (see CheckTemplateCompatibility) and describes
+// each kind of mismatch in one message, or returns nil when there is none.
+func CompatibilityWarnings(data map[string]interface{}) []string {
+	missing, unknown := CheckTemplateCompatibility(data, TemplateVarNames())
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("template does not use required variables: %s", strings.Join(missing, ", ")))
+	}
+	if len(unknown) > 0 {
+		problems = append(problems, fmt.Sprintf("template uses variables this version does not provide: %s", strings.Join(unknown, ", ")))
+	}
+	return problems
+}
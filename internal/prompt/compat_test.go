@@ -0,0 +1,103 @@
+package prompt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestCheckTemplateCompatibility(t *testing.T) {
+	known := []string{"TARGET_PATH", "NESTED_REPOS", "OUTPUT_DIR", "CODEBASE_NAME"}
+	tests := []struct {
+		name        string
+		data        map[string]interface{}
+		wantMissing []string
+		wantUnknown []string
+	}{
+		{
+			name: "compatible",
+			data: map[string]interface{}{
+				"prompt": map[string]interface{}{
+					"context": "Analyze {{CODEBASE_NAME}} at {{TARGET_PATH}}.",
+					"repos":   "{{NESTED_REPOS}}",
+					"outputs": []interface{}{"{{OUTPUT_DIR}}/analysis.md"},
+				},
+			},
+		},
+		{
+			name: "retired variable",
+			data: map[string]interface{}{
+				"prompt": map[string]interface{}{
+					"context": "{{TARGET_PATH}} has {{REPO_COUNT}} repos: {{NESTED_REPOS}}",
+					"outputs": map[interface{}]interface{}{1: "{{OUTPUT_DIR}}"},
+				},
+			},
+			wantUnknown: []string{"REPO_COUNT"},
+		},
+		{
+			name: "missing required variable",
+			data: map[string]interface{}{
+				"prompt": map[string]interface{}{
+					"context": "{{TARGET_PATH}}: {{NESTED_REPOS}}",
+					"outputs": "/tmp/out",
+				},
+			},
+			wantMissing: []string{"OUTPUT_DIR"},
+		},
+		{
+			name:        "no placeholders",
+			data:        map[string]interface{}{"prompt": "plain"},
+			wantMissing: []string{"TARGET_PATH", "NESTED_REPOS", "OUTPUT_DIR"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missing, unknown := CheckTemplateCompatibility(tt.data, known)
+			if !reflect.DeepEqual(missing, tt.wantMissing) {
+				t.Errorf("missing = %v, want %v", missing, tt.wantMissing)
+			}
+			if !reflect.DeepEqual(unknown, tt.wantUnknown) {
+				t.Errorf("unknown = %v, want %v", unknown, tt.wantUnknown)
+			}
+		})
+	}
+}
+
+func TestTemplateVarNames(t *testing.T) {
+	names := TemplateVarNames()
+	for _, want := range append([]string{"CODEBASE_SUMMARY", "RISK_AREAS"}, requiredTemplateVars...) {
+		found := false
+		for _, name := range names {
+			found = found || name == want
+		}
+		if !found {
+			t.Errorf("TemplateVarNames() = %v, missing %s", names, want)
+		}
+	}
+}
+
+func TestCompatibilityWarnings(t *testing.T) {
+	data := map[string]interface{}{"prompt": "{{TARGET_PATH}} {{NESTED_REPOS}} {{REPO_COUNT}}"}
+	got := CompatibilityWarnings(data)
+	want := []string{
+		"template does not use required variables: OUTPUT_DIR",
+		"template uses variables this version does not provide: REPO_COUNT",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CompatibilityWarnings() = %q, want %q", got, want)
+	}
+}
+
+func TestCompatibilityWarnings_DefaultTemplate(t *testing.T) {
+	chdir(t, t.TempDir())
+	tmpl, err := loadPromptTemplate("", logger.New(false))
+	if err != nil {
+		t.Fatalf("loadPromptTemplate() error = %v", err)
+	}
+	if warnings := CompatibilityWarnings(tmpl); len(warnings) > 0 {
+		t.Errorf("default template is incompatible: %s", strings.Join(warnings, "; "))
+	}
+}
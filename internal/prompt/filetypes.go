@@ -0,0 +1,32 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+)
+
+// maxListedUnclassified caps how many extensions unclassifiedDetail lists.
+const maxListedUnclassified = 5
+
+// unclassifiedDetail renders the "- Unclassified File Types:" line, listing
+// the most common extensions that map to no language, or "" when every
+// extension was recognized. Users can add mappings for them with lang_map.
+func unclassifiedDetail(types map[string]int) string {
+	sorted := scanner.SortCounts(types)
+	if len(sorted) == 0 {
+		return ""
+	}
+	listed := sorted
+	var more string
+	if len(listed) > maxListedUnclassified {
+		listed = listed[:maxListedUnclassified]
+		more = fmt.Sprintf(" (+%d more)", len(sorted)-maxListedUnclassified)
+	}
+	parts := make([]string, len(listed))
+	for i, c := range listed {
+		parts[i] = fmt.Sprintf("%s %d", c.Name, c.Count)
+	}
+	return fmt.Sprintf("- Unclassified File Types: %s%s\n", strings.Join(parts, ", "), more)
+}
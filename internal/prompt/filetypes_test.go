@@ -0,0 +1,38 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+)
+
+func TestUnclassifiedDetail(t *testing.T) {
+	tests := []struct {
+		name  string
+		types map[string]int
+		want  string
+	}{
+		{"none", nil, ""},
+		{"most common first", map[string]int{".bar": 1, ".foo": 3}, "- Unclassified File Types: .foo 3, .bar 1\n"},
+		{"capped", map[string]int{".a": 7, ".b": 6, ".c": 5, ".d": 4, ".e": 3, ".f": 2, ".g": 1},
+			"- Unclassified File Types: .a 7, .b 6, .c 5, .d 4, .e 3 (+2 more)\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unclassifiedDetail(tt.types); got != tt.want {
+				t.Errorf("unclassifiedDetail() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_UnclassifiedTypes(t *testing.T) {
+	analysis := &scanner.RepositoryAnalysis{
+		Repository:        scanner.Repository{Name: "app"},
+		UnclassifiedTypes: map[string]int{".foo": 2},
+	}
+	if prompt := renderedPrompt(t, []*scanner.RepositoryAnalysis{analysis}); !strings.Contains(prompt, "- Unclassified File Types: .foo 2\n") {
+		t.Errorf("expected unclassified types in prompt, got:\n%s", prompt)
+	}
+}
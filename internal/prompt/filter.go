@@ -0,0 +1,61 @@
+package prompt
+
+import (
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// dropSmallRepos removes repositories whose analysis counted fewer than
+// minFiles files, logging each one dropped. Repositories without an
+// analysis are left alone. A minFiles of zero or less keeps everything.
+func dropSmallRepos(repos []scanner.Repository, analyses []*scanner.RepositoryAnalysis, minFiles int, log *logger.Logger) ([]scanner.Repository, []*scanner.RepositoryAnalysis) {
+	if minFiles <= 0 {
+		return repos, analyses
+	}
+
+	dropped := make(map[string]bool)
+	var keptAnalyses []*scanner.RepositoryAnalysis
+	for _, a := range analyses {
+		if a.TotalFiles < minFiles {
+			log.Info("Skipping %s: %d files is below --min-files %d", a.Repository.Name, a.TotalFiles, minFiles)
+			dropped[a.Repository.Path] = true
+			continue
+		}
+		keptAnalyses = append(keptAnalyses, a)
+	}
+
+	var keptRepos []scanner.Repository
+	for _, r := range repos {
+		if !dropped[r.Path] {
+			keptRepos = append(keptRepos, r)
+		}
+	}
+	return keptRepos, keptAnalyses
+}
+
+// dropEmptyRepos removes repositories whose analysis found nothing but
+// .git (see scanner.RepositoryAnalysis.IsEmpty), warning about each one
+// dropped so that a zero-file analysis never reaches the prompt.
+func dropEmptyRepos(repos []scanner.Repository, analyses []*scanner.RepositoryAnalysis, log *logger.Logger) ([]scanner.Repository, []*scanner.RepositoryAnalysis) {
+	dropped := make(map[string]bool)
+	var keptAnalyses []*scanner.RepositoryAnalysis
+	for _, a := range analyses {
+		if a.IsEmpty {
+			log.Warn("Skipping %s: repository is empty (nothing but .git); use --include-empty to keep it", a.Repository.Name)
+			dropped[a.Repository.Path] = true
+			continue
+		}
+		keptAnalyses = append(keptAnalyses, a)
+	}
+	if len(dropped) == 0 {
+		return repos, analyses
+	}
+
+	var keptRepos []scanner.Repository
+	for _, r := range repos {
+		if !dropped[r.Path] {
+			keptRepos = append(keptRepos, r)
+		}
+	}
+	return keptRepos, keptAnalyses
+}
@@ -0,0 +1,147 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestDropSmallRepos(t *testing.T) {
+	repos := []scanner.Repository{
+		{Path: "/src/config", Name: "config"},
+		{Path: "/src/app", Name: "app"},
+		{Path: "/src/broken", Name: "broken"},
+	}
+	analyses := []*scanner.RepositoryAnalysis{
+		{Repository: repos[0], TotalFiles: 2},
+		{Repository: repos[1], TotalFiles: 40},
+	}
+
+	tests := []struct {
+		name     string
+		minFiles int
+		want     []string
+	}{
+		{"zero keeps all", 0, []string{"config", "app", "broken"}},
+		{"threshold drops small repo", 5, []string{"app", "broken"}},
+		{"threshold equal to count keeps repo", 2, []string{"config", "app", "broken"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRepos, gotAnalyses := dropSmallRepos(repos, analyses, tt.minFiles, logger.New(false))
+			var names []string
+			for _, r := range gotRepos {
+				names = append(names, r.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("repos = %v, want %v", names, tt.want)
+			}
+			for _, a := range gotAnalyses {
+				if a.TotalFiles < tt.minFiles {
+					t.Errorf("analysis for %s with %d files was kept", a.Repository.Name, a.TotalFiles)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_MinFiles(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	tinyDir := t.TempDir()
+	for _, name := range []string{"a.yaml", "b.yaml"} {
+		if err := os.WriteFile(filepath.Join(tinyDir, name), []byte("k: v\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	appDir := t.TempDir()
+	for i := 0; i < 6; i++ {
+		if err := os.WriteFile(filepath.Join(appDir, fmt.Sprintf("f%d.go", i)), []byte("package app\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repos := []scanner.Repository{
+		{Path: tinyDir, Name: "tiny-config", RelativePath: "tiny-config"},
+		{Path: appDir, Name: "app", RelativePath: "app"},
+	}
+	outputDir := t.TempDir()
+
+	promptPath, err := Generate(context.Background(), filepath.Dir(appDir), repos, outputDir, Options{MinFiles: 5}, logger.New(false))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	data, err := os.ReadFile(promptPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "tiny-config") {
+		t.Error("repository below --min-files should be left out of the prompt")
+	}
+	if !strings.Contains(string(data), `"Name":"app"`) {
+		t.Error("repository meeting --min-files should remain in the prompt")
+	}
+
+	if _, err := Generate(context.Background(), filepath.Dir(appDir), repos[:1], outputDir, Options{MinFiles: 5, NoCache: true}, logger.New(false)); !errors.Is(err, scanner.ErrNoRepos) {
+		t.Errorf("Generate() error = %v, want scanner.ErrNoRepos when every repository is below --min-files", err)
+	}
+}
+
+func TestGenerate_EmptyRepos(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	emptyDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(emptyDir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	appDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(appDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repos := []scanner.Repository{
+		{Path: emptyDir, Name: "fresh-init", RelativePath: "fresh-init"},
+		{Path: appDir, Name: "app", RelativePath: "app"},
+	}
+
+	tests := []struct {
+		name      string
+		include   bool
+		wantEmpty bool
+	}{
+		{"dropped by default", false, false},
+		{"kept with IncludeEmpty", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			log := logger.NewWithWriter(&out, false)
+			promptPath, err := Generate(context.Background(), filepath.Dir(appDir), repos, t.TempDir(), Options{IncludeEmpty: tt.include, NoCache: true}, log)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			data, err := os.ReadFile(promptPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(string(data), "fresh-init"); got != tt.wantEmpty {
+				t.Errorf("prompt mentions the empty repository = %v, want %v", got, tt.wantEmpty)
+			}
+			warned := strings.Contains(out.String(), "[WARN] Skipping fresh-init: repository is empty")
+			if warned == tt.include {
+				t.Errorf("empty-repository warning logged = %v, want %v:\n%s", warned, !tt.include, out.String())
+			}
+		})
+	}
+
+	if _, err := Generate(context.Background(), emptyDir, repos[:1], t.TempDir(), Options{NoCache: true}, logger.New(false)); !errors.Is(err, scanner.ErrNoRepos) {
+		t.Errorf("Generate() error = %v, want scanner.ErrNoRepos when every repository is empty", err)
+	}
+}
@@ -0,0 +1,133 @@
+package prompt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats supported by Generate.
+const (
+	FormatMarkdown = "md"
+	FormatYAML     = "yaml"
+	FormatJSON     = "json"
+)
+
+// DefaultFormats are written when Options.Formats is empty.
+var DefaultFormats = []string{FormatMarkdown, FormatYAML}
+
+// formatFiles maps each output format to its file name in the output directory.
+var formatFiles = map[string]string{
+	FormatMarkdown: "phase1-llm-prompt.md",
+	FormatYAML:     "phase1-llm-prompt.yaml",
+	FormatJSON:     "phase1-llm-prompt.json",
+}
+
+// ParseFormats parses a comma-separated list such as "md,yaml,json".
+// Names are case-insensitive; duplicates are dropped and unknown names are
+// rejected. An empty list yields DefaultFormats.
+func ParseFormats(list string) ([]string, error) {
+	var formats []string
+	seen := make(map[string]bool)
+	for _, f := range strings.Split(list, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" {
+			continue
+		}
+		if _, ok := formatFiles[f]; !ok {
+			return nil, fmt.Errorf("unknown output format %q (supported: md, yaml, json)", f)
+		}
+		if !seen[f] {
+			seen[f] = true
+			formats = append(formats, f)
+		}
+	}
+	if len(formats) == 0 {
+		return DefaultFormats, nil
+	}
+	return formats, nil
+}
+
+// hasFormat reports whether format is among formats.
+func hasFormat(formats []string, format string) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// substituteVars returns a deep copy of value with {{VAR}} placeholders in
+// every string replaced by r. Building r with varReplacer substitutes in
+// the single pass the Markdown prompt uses, so a value that itself
+// contains a placeholder is left as is in both. Maps with
+// non-string keys are converted to map[string]interface{} so the result
+// can be marshaled to JSON.
+func substituteVars(value interface{}, r *strings.Replacer) interface{} {
+	switch v := value.(type) {
+	case string:
+		return r.Replace(v)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = substituteVars(item, r)
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[fmt.Sprintf("%v", k)] = substituteVars(item, r)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = substituteVars(item, r)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// writeStructuredOutputs writes the YAML and/or JSON forms of the prompt
+// template, as selected by formats.
+func writeStructuredOutputs(promptTemplate map[string]interface{}, vars map[string]string, outputDir string, formats []string, dryRun bool, log *logger.Logger) error {
+	if hasFormat(formats, FormatYAML) {
+		// The YAML output keeps placeholders so it can be re-rendered.
+		yamlData, err := yaml.Marshal(promptTemplate)
+		if err != nil {
+			return fmt.Errorf("failed to marshal YAML: %w", err)
+		}
+		if err := writeOutput(filepath.Join(outputDir, formatFiles[FormatYAML]), yamlData, dryRun, log); err != nil {
+			return fmt.Errorf("failed to write YAML prompt: %w", err)
+		}
+	}
+
+	if hasFormat(formats, FormatJSON) {
+		jsonData, err := json.MarshalIndent(substituteVars(promptTemplate, varReplacer(vars)), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		if err := writeOutput(filepath.Join(outputDir, formatFiles[FormatJSON]), jsonData, dryRun, log); err != nil {
+			return fmt.Errorf("failed to write JSON prompt: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeOutput writes data to path, or only logs the planned write in dry-run mode.
+func writeOutput(path string, data []byte, dryRun bool, log *logger.Logger) error {
+	if dryRun {
+		log.Info("Dry run: would write %s (%d bytes)", path, len(data))
+		return nil
+	}
+	return os.WriteFile(path, data, 0644)
+}
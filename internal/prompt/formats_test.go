@@ -0,0 +1,153 @@
+package prompt
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestParseFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		list    string
+		want    []string
+		wantErr bool
+	}{
+		{"empty uses defaults", "", DefaultFormats, false},
+		{"all formats", "md,yaml,json", []string{"md", "yaml", "json"}, false},
+		{"trims and lowercases", " JSON , md ", []string{"json", "md"}, false},
+		{"drops duplicates", "json,json", []string{"json"}, false},
+		{"unknown format", "md,pdf", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFormats(tt.list)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormats(%q) error = %v, wantErr %v", tt.list, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFormats(%q) = %v, want %v", tt.list, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubstituteVarsNestedMaps(t *testing.T) {
+	input := map[string]interface{}{
+		"top": "{{NAME}}",
+		"nested": map[interface{}]interface{}{
+			"list": []interface{}{"a {{NAME}}", 3},
+			1:      true,
+		},
+	}
+
+	got := substituteVars(input, varReplacer(map[string]string{"NAME": "app"}))
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"nested":{"1":true,"list":["a app",3]},"top":"app"}`
+	if string(data) != want {
+		t.Errorf("substituted JSON = %s, want %s", data, want)
+	}
+}
+
+func TestSubstituteVars_SinglePass(t *testing.T) {
+	// A value holding another placeholder must not be substituted again,
+	// whatever order the map is iterated in.
+	vars := map[string]string{"A": "{{B}}", "B": "{{A}}", "C": "c"}
+	r := varReplacer(vars)
+	want := r.Replace("{{A}} {{B}} {{C}}")
+	if want != "{{B}} {{A}} c" {
+		t.Fatalf("varReplacer() = %q, want a single pass", want)
+	}
+	for i := 0; i < 20; i++ {
+		if got := substituteVars("{{A}} {{B}} {{C}}", varReplacer(vars)); got != want {
+			t.Fatalf("substituteVars() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestGenerate_JSONFormat(t *testing.T) {
+	repoDir := t.TempDir()
+	repos := []scanner.Repository{{Path: repoDir, Name: "app", RelativePath: "."}}
+	outputDir := t.TempDir()
+
+	opts := Options{Formats: []string{FormatMarkdown, FormatYAML, FormatJSON}}
+	if _, err := Generate(context.Background(), repoDir, repos, outputDir, opts, logger.New(false)); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "phase1-llm-prompt.json"))
+	if err != nil {
+		t.Fatalf("JSON prompt not written: %v", err)
+	}
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("JSON prompt does not parse: %v", err)
+	}
+	if _, ok := parsed["prompt"].(map[string]interface{}); !ok {
+		t.Errorf("expected nested prompt object, got %T", parsed["prompt"])
+	}
+	if strings.Contains(string(data), "{{TARGET_PATH}}") {
+		t.Error("JSON prompt should have variables substituted")
+	}
+	if !strings.Contains(string(data), repoDir) {
+		t.Error("JSON prompt should contain the resolved target path")
+	}
+}
+
+func TestGenerate_DefaultFormatsSkipJSON(t *testing.T) {
+	repoDir := t.TempDir()
+	repos := []scanner.Repository{{Path: repoDir, Name: "app", RelativePath: "."}}
+	outputDir := t.TempDir()
+
+	if _, err := Generate(context.Background(), repoDir, repos, outputDir, Options{}, logger.New(false)); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, name := range []string{"phase1-llm-prompt.md", "phase1-llm-prompt.yaml"} {
+		if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+			t.Errorf("expected %s to be written: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "phase1-llm-prompt.json")); !os.IsNotExist(err) {
+		t.Error("JSON prompt should not be written by default")
+	}
+}
+
+func TestGenerate_DryRunWritesNothing(t *testing.T) {
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repos := []scanner.Repository{{Path: repoDir, Name: "app", RelativePath: "."}}
+	outputDir := t.TempDir()
+
+	opts := Options{DryRun: true, Formats: []string{FormatMarkdown, FormatYAML, FormatJSON}}
+	promptPath, err := Generate(context.Background(), repoDir, repos, outputDir, opts, logger.New(false))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if promptPath != filepath.Join(outputDir, "phase1-llm-prompt.md") {
+		t.Errorf("Generate() path = %q, want planned markdown path", promptPath)
+	}
+
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("dry run created %d entries in output dir", len(entries))
+	}
+}
@@ -1,223 +1,328 @@
 package prompt
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
-	"text/template"
+	"unicode/utf8"
 
 	"github.com/bordenet/codebase-reviewer/internal/scanner"
 	"github.com/bordenet/codebase-reviewer/pkg/logger"
-	"gopkg.in/yaml.v3"
+	"github.com/bordenet/codebase-reviewer/prompts"
 )
 
-// Generate creates the LLM prompt for Phase 1 analysis
-func Generate(targetPath string, repos []scanner.Repository, outputDir string, verbose, scorch bool, log *logger.Logger) (string, error) {
-	log.Info("Loading prompt template...")
+// defaultTemplatePath is checked relative to the working directory so a
+// customized template can override the embedded default.
+const defaultTemplatePath = "prompts/templates/phase1-prompt-template.yaml"
+
+// Options controls how the Phase 1 prompt is generated.
+type Options struct {
+	Verbose bool
+	Scorch  bool
+	// TemplatePath, when set, selects a custom YAML template instead of the
+	// default. The file must exist and parse as YAML.
+	TemplatePath string
+	// AllowUnresolved permits {{PLACEHOLDER}} tokens that have no matching
+	// variable to remain in the output instead of failing generation.
+	AllowUnresolved bool
+	// MaxTokens is the estimated token count above which a warning is
+	// logged. Zero or negative uses DefaultMaxTokens.
+	MaxTokens int
+	// Formats selects the output files to write (see ParseFormats). Empty
+	// means DefaultFormats.
+	Formats []string
+	// Provider selects LLM-specific prompt scaffolding (see ParseProvider).
+	// Nil means the generic Markdown layout.
+	Provider Provider
+	// DryRun renders the prompt and logs the files that would be written
+	// without touching the filesystem.
+	DryRun bool
+	// Scan tunes the per-repository analysis.
+	Scan scanner.ScanOptions
+	// NoCache forces a fresh analysis and leaves the analysis cache alone.
+	NoCache bool
+	// Report selects a machine-readable scan report to write alongside the
+	// prompt (see ParseReport). Empty writes none.
+	Report string
+	// Incremental updates a stale cached analysis from the files git
+	// reports as changed rather than re-walking each repository. It falls
+	// back to a full analysis when there is no usable cache.
+	Incremental bool
+	// Summary, when set, receives a table of the analyzed repositories
+	// (see WriteSummaryTable) once the prompt is generated.
+	Summary io.Writer
+	// MinFiles drops repositories with fewer analyzed files than this from
+	// the prompt. Zero keeps all repositories.
+	MinFiles int
+	// IncludeEmpty keeps repositories holding nothing but .git in the
+	// prompt; by default they are dropped with a warning.
+	IncludeEmpty bool
+	// Anonymize replaces paths, repository names, and remotes in the
+	// prompt and scan report with stable tokens, writing the mapping back
+	// to AnonymizationMapFileName in the output directory.
+	Anonymize bool
+	// Resume skips the repositories already analyzed by an interrupted run,
+	// reusing the analyses it checkpointed in the output directory (see
+	// scanner.CheckpointFileName). It has no effect with Scorch.
+	Resume bool
+}
+
+// DefaultMaxTokens is the prompt size, in estimated tokens, above which
+// Generate warns that the prompt may not fit an LLM context window.
+const DefaultMaxTokens = 100000
+
+// charsPerToken is the rough characters-per-token ratio used by EstimateTokens.
+const charsPerToken = 4
+
+// EstimateTokens approximates the number of LLM tokens in rendered using a
+// simple characters/4 heuristic, rounded up.
+func EstimateTokens(rendered string) int {
+	return tokensForChars(utf8.RuneCountInString(rendered))
+}
+
+// tokensForChars converts a character count to EstimateTokens' estimate.
+func tokensForChars(chars int) int {
+	return (chars + charsPerToken - 1) / charsPerToken
+}
 
-	// Load template
-	templatePath := "prompts/templates/phase1-prompt-template.yaml"
-	templateData, err := os.ReadFile(templatePath)
+// placeholderPattern matches {{...}} tokens left in rendered output.
+var placeholderPattern = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+
+// Result describes a generated prompt and the analyses it was built from.
+type Result struct {
+	// PromptPath is the primary prompt file.
+	PromptPath string
+	// Repositories and Analyses are those included in the prompt, after
+	// empty and small repositories were dropped (see Options).
+	Repositories []scanner.Repository
+	Analyses     []*scanner.RepositoryAnalysis
+	// Tokens is the estimated token count of the prompt.
+	Tokens int
+	// Fingerprint identifies the analyzed codebase state, or is "" when
+	// none was computed, as with Scorch or NoCache.
+	Fingerprint string
+}
+
+// Generate creates the LLM prompt for Phase 1 analysis and returns the
+// path of the prompt file. If ctx is cancelled before the outputs are
+// written, Generate returns ctx.Err() and writes nothing.
+func Generate(ctx context.Context, targetPath string, repos []scanner.Repository, outputDir string, opts Options, log *logger.Logger) (string, error) {
+	res, err := GenerateResult(ctx, targetPath, repos, outputDir, opts, log)
 	if err != nil {
-		return "", fmt.Errorf("failed to read template: %w", err)
+		return "", err
 	}
+	return res.PromptPath, nil
+}
 
-	// Parse YAML template
-	var promptTemplate map[string]interface{}
-	if err := yaml.Unmarshal(templateData, &promptTemplate); err != nil {
-		return "", fmt.Errorf("failed to parse template YAML: %w", err)
+// GenerateResult is Generate, also returning the repositories and
+// analyses the prompt was built from.
+func GenerateResult(ctx context.Context, targetPath string, repos []scanner.Repository, outputDir string, opts Options, log *logger.Logger) (*Result, error) {
+	log.Info("Loading prompt template...")
+
+	promptTemplate, err := loadPromptTemplate(opts.TemplatePath, log)
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range CompatibilityWarnings(promptTemplate) {
+		log.Warn("Prompt template: %s", warning)
 	}
 
 	log.Info("Analyzing repositories...")
 
-	// Analyze each repository
-	var analyses []*scanner.RepositoryAnalysis
-	for _, repo := range repos {
-		analysis, err := scanner.AnalyzeRepository(repo, log)
-		if err != nil {
-			log.Warn("Failed to analyze %s: %v", repo.Name, err)
-			continue
+	analyses, fingerprint, err := analyzeRepositories(ctx, repos, outputDir, opts, log)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.IncludeEmpty {
+		repos, analyses = dropEmptyRepos(repos, analyses, log)
+		if len(repos) == 0 {
+			return nil, fmt.Errorf("%w: every repository is empty", scanner.ErrNoRepos)
 		}
-		analyses = append(analyses, analysis)
+	}
+	repos, analyses = dropSmallRepos(repos, analyses, opts.MinFiles, log)
+	if len(repos) == 0 {
+		return nil, fmt.Errorf("%w: no repository has at least %d files", scanner.ErrNoRepos, opts.MinFiles)
 	}
 
 	log.Info("Building prompt context...")
 
-	// Build substitution variables
-	vars := buildTemplateVars(targetPath, repos, analyses, outputDir, verbose, scorch)
+	// Build substitution variables, from anonymized copies when requested.
+	// The summary table is printed locally and keeps the real names.
+	shownTarget, shownOutputDir, shownRepos, shownAnalyses := targetPath, outputDir, repos, analyses
+	var anon *anonymizer
+	if opts.Anonymize {
+		anon = newAnonymizer(targetPath, outputDir)
+		shownRepos, shownAnalyses = anon.anonymize(repos, analyses)
+		shownTarget, shownOutputDir = rootToken, outputDirToken
+	}
+	vars := buildTemplateVars(shownTarget, shownRepos, shownAnalyses, shownOutputDir, opts.Verbose, opts.Scorch)
 
-	// Render template
-	rendered, err := renderTemplate(promptTemplate, vars)
+	provider := opts.Provider
+	if provider == nil {
+		provider = genericProvider{}
+	}
+
+	// The prompt is streamed to its file below rather than rendered in
+	// memory, so unresolved placeholders are found from its inputs.
+	templateYAML, err := marshalTemplate(promptTemplate)
 	if err != nil {
-		return "", fmt.Errorf("failed to render template: %w", err)
+		return nil, fmt.Errorf("failed to render template: %w", err)
 	}
+	render := promptRenderer(provider, templateYAML, vars)
 
-	// Write prompt to output directory
-	promptPath := filepath.Join(outputDir, "phase1-llm-prompt.md")
-	if err := os.WriteFile(promptPath, []byte(rendered), 0644); err != nil {
-		return "", fmt.Errorf("failed to write prompt: %w", err)
+	if unresolved := unresolvedPlaceholders(templateYAML, vars); len(unresolved) > 0 {
+		if !opts.AllowUnresolved {
+			return nil, fmt.Errorf("template has unresolved placeholders: %s (use --allow-unresolved to keep them)", strings.Join(unresolved, ", "))
+		}
+		log.Warn("Leaving unresolved placeholders in prompt: %s", strings.Join(unresolved, ", "))
 	}
 
-	log.Info("Prompt generated: %s", promptPath)
+	// Last chance to stop before anything is written.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	// Also write as YAML for programmatic access
-	yamlPath := filepath.Join(outputDir, "phase1-llm-prompt.yaml")
-	yamlData, err := yaml.Marshal(promptTemplate)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal YAML: %w", err)
+	if opts.Report == ReportJSON {
+		report := scanner.NewScanReport(shownTarget, fingerprint, shownAnalyses)
+		if err := writeScanReport(report, outputDir, opts.DryRun, log); err != nil {
+			return nil, err
+		}
+	}
+
+	formats := opts.Formats
+	if len(formats) == 0 {
+		formats = DefaultFormats
 	}
-	if err := os.WriteFile(yamlPath, yamlData, 0644); err != nil {
-		return "", fmt.Errorf("failed to write YAML prompt: %w", err)
+
+	// Write prompt to output directory
+	promptPath := filepath.Join(outputDir, formatFiles[formats[0]])
+	var tokens int
+	if hasFormat(formats, FormatMarkdown) {
+		promptPath = filepath.Join(outputDir, provider.FileName())
+		tokens, err = writePrompt(promptPath, render, opts.DryRun, log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write prompt: %w", err)
+		}
+	} else if tokens, err = measurePrompt(render); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
 	}
 
-	return promptPath, nil
+	// Also write structured forms for programmatic access
+	if err := writeStructuredOutputs(promptTemplate, vars, outputDir, formats, opts.DryRun, log); err != nil {
+		return nil, err
+	}
+	if anon != nil {
+		if err := anon.writeAnonymizationMap(outputDir, opts.DryRun, log); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Info("Prompt generated: %s (~%d tokens)", promptPath, tokens)
+	warnIfOversized(tokens, opts.MaxTokens, log)
+
+	if opts.Summary != nil {
+		if err := WriteSummaryTable(opts.Summary, analyses); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Result{PromptPath: promptPath, Repositories: repos, Analyses: analyses, Tokens: tokens, Fingerprint: fingerprint}, nil
 }
 
-func buildTemplateVars(targetPath string, repos []scanner.Repository, analyses []*scanner.RepositoryAnalysis, outputDir string, verbose, scorch bool) map[string]string {
-	codebaseName := filepath.Base(targetPath)
-
-	// Build nested repos detail
-	var reposDetail strings.Builder
-	for i, analysis := range analyses {
-		reposDetail.WriteString(fmt.Sprintf("\n### Repository %d: %s\n", i+1, analysis.Repository.Name))
-		reposDetail.WriteString(fmt.Sprintf("- Path: %s\n", analysis.Repository.RelativePath))
-		reposDetail.WriteString(fmt.Sprintf("- Primary Language: %s\n", analysis.PrimaryLanguage()))
-		reposDetail.WriteString(fmt.Sprintf("- Total Files: %d\n", analysis.TotalFiles))
-		reposDetail.WriteString("- Languages:\n")
-		for lang, count := range analysis.Languages {
-			reposDetail.WriteString(fmt.Sprintf("  - %s: %d files\n", lang, count))
+// loadPromptTemplate reads and parses the prompt template, inlining any
+// !include files (see ParseTemplate). A non-empty customPath must point to
+// an existing YAML file; otherwise the default template is used.
+func loadPromptTemplate(customPath string, log *logger.Logger) (map[string]interface{}, error) {
+	var templateData []byte
+	var err error
+	templatePath := defaultTemplatePath
+	if customPath != "" {
+		log.Info("Using custom template: %s", customPath)
+		templatePath = customPath
+		templateData, err = os.ReadFile(customPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read custom template %s: %w", customPath, err)
+		}
+	} else {
+		templateData, err = loadTemplate(defaultTemplatePath, log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
 		}
 	}
 
-	// Build repos JSON
-	reposJSON, _ := json.Marshal(repos)
+	promptTemplate, err := ParseTemplate(templateData, templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template YAML: %w", err)
+	}
+	if promptTemplate == nil {
+		return nil, fmt.Errorf("template is empty")
+	}
+
+	return promptTemplate, nil
+}
 
-	scanMode := "deep_scan"
-	if scorch {
-		scanMode = "scorch"
+// loadTemplate reads the template at path, falling back to the embedded
+// default when the file does not exist.
+func loadTemplate(path string, log *logger.Logger) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		log.Debug("Using template from %s", path)
+		return data, nil
 	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	log.Debug("Template %s not found, using embedded default", path)
+	return prompts.Phase1Template, nil
+}
 
-	return map[string]string{
-		"TARGET_PATH":         targetPath,
-		"CODEBASE_NAME":       codebaseName,
-		"SCAN_MODE":           scanMode,
-		"VERBOSE":             fmt.Sprintf("%v", verbose),
-		"NESTED_REPOS":        string(reposJSON),
-		"NESTED_REPOS_DETAIL": reposDetail.String(),
-		"OUTPUT_DIR":          outputDir,
+// warnIfOversized logs a warning when the token estimate exceeds maxTokens.
+func warnIfOversized(tokens, maxTokens int, log *logger.Logger) {
+	if maxTokens <= 0 {
+		maxTokens = DefaultMaxTokens
+	}
+	if tokens > maxTokens {
+		log.Warn("Prompt is ~%d tokens, exceeding the %d token limit; it may not fit your LLM's context window", tokens, maxTokens)
 	}
 }
 
-func renderTemplate(templateData map[string]interface{}, vars map[string]string) (string, error) {
-	// Convert template to YAML string
-	yamlBytes, err := yaml.Marshal(templateData)
-	if err != nil {
-		return "", err
+// findUnresolvedPlaceholders returns the distinct {{...}} tokens remaining
+// in rendered, sorted for stable error messages.
+func findUnresolvedPlaceholders(rendered string) []string {
+	matches := placeholderPattern.FindAllString(rendered, -1)
+	if len(matches) == 0 {
+		return nil
 	}
 
-	yamlStr := string(yamlBytes)
-
-	// Replace variables
-	for key, value := range vars {
-		placeholder := "{{" + key + "}}"
-		yamlStr = strings.ReplaceAll(yamlStr, placeholder, value)
-	}
-
-	// Convert to markdown for readability
-	var buf bytes.Buffer
-	buf.WriteString("# Phase 1 LLM Prompt - Codebase Analysis\n\n")
-	buf.WriteString("**SECURITY NOTICE:** This prompt contains references to proprietary code.\n")
-	buf.WriteString("All outputs must be written to /tmp or .gitignore'd locations.\n\n")
-	buf.WriteString("---\n\n")
-	buf.WriteString("```yaml\n")
-	buf.WriteString(yamlStr)
-	buf.WriteString("\n```\n\n")
-	buf.WriteString("---\n\n")
-	buf.WriteString("## Instructions for AI Assistant\n\n")
-	buf.WriteString("Please process the above YAML prompt and:\n\n")
-	buf.WriteString("1. Perform a deep scan of the codebase\n")
-	buf.WriteString("2. Design reference materials strategy\n")
-	buf.WriteString("3. Design Phase 2 tools\n")
-	buf.WriteString("4. Implement Phase 2 tools in Go\n")
-	buf.WriteString("5. Generate initial reference materials\n")
-	buf.WriteString("6. Validate security compliance\n\n")
-	buf.WriteString("All outputs must go to:\n")
-	buf.WriteString(fmt.Sprintf("- %s\n\n", vars["OUTPUT_DIR"]))
-
-	return buf.String(), nil
+	seen := make(map[string]bool, len(matches))
+	var unique []string
+	for _, m := range matches {
+		if !seen[m] {
+			seen[m] = true
+			unique = append(unique, m)
+		}
+	}
+	sort.Strings(unique)
+	return unique
 }
 
-// RenderMarkdown converts the YAML prompt to a readable markdown format
-func RenderMarkdown(yamlData map[string]interface{}) (string, error) {
-	tmpl := `# Codebase Analysis Prompt
-
-## Metadata
-- Version: {{.metadata.version}}
-- Type: {{.metadata.template_type}}
-- Security Level: {{.metadata.security_level}}
-
-## Context
-{{.prompt.context}}
-
-## Scan Parameters
-- Target Path: {{.prompt.scan_parameters.target_path}}
-- Scan Mode: {{.prompt.scan_parameters.scan_mode}}
-- Verbose: {{.prompt.scan_parameters.verbose}}
-
-## Nested Repositories
-{{.prompt.scan_parameters.nested_repos_detail}}
-
-## Tasks
-{{range .prompt.tasks}}
-### {{.name}} ({{.task_id}})
-{{.description}}
-{{end}}
-
-## Output Requirements
-- Primary Output: {{.prompt.output_requirements.primary_output}}
-- Phase 2 Tools: {{.prompt.output_requirements.phase2_tools}}
-- Reference Materials: {{.prompt.output_requirements.reference_materials}}
-
-## Success Criteria
-{{range .prompt.success_criteria}}
-- {{.}}
-{{end}}
-
-## Guidance Specification
-
-### Code Quality
-{{range .guidance_spec.code_quality}}
-- {{.}}
-{{end}}
-
-### Performance
-{{range .guidance_spec.performance}}
-- {{.}}
-{{end}}
-
-### Error Handling
-{{range .guidance_spec.error_handling}}
-- {{.}}
-{{end}}
-
-### Security
-{{range .guidance_spec.security}}
-- {{.}}
-{{end}}
-`
-
-	t, err := template.New("prompt").Parse(tmpl)
+// renderTemplateFor substitutes vars into the template and wraps the
+// resulting YAML in the provider's prompt scaffolding, returning the same
+// prompt Generate streams to its file.
+func renderTemplateFor(p Provider, templateData map[string]interface{}, vars map[string]string) (string, error) {
+	templateYAML, err := marshalTemplate(templateData)
 	if err != nil {
 		return "", err
 	}
-
-	var buf bytes.Buffer
-	if err := t.Execute(&buf, yamlData); err != nil {
+	var b strings.Builder
+	if err := promptRenderer(p, templateYAML, vars)(&b); err != nil {
 		return "", err
 	}
-
-	return buf.String(), nil
+	return b.String(), nil
 }
@@ -1,12 +1,39 @@
 package prompt
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+	"gopkg.in/yaml.v3"
 )
 
+// chdir changes the working directory for the duration of the test.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	orig, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(orig); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
 func TestBuildTemplateVars(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -85,7 +112,32 @@ func TestBuildTemplateVars_ScanMode(t *testing.T) {
 	}
 }
 
-func TestBuildTemplateVars_NestedReposDetail(t *testing.T) {
+// renderedPrompt writes the prompt for analyses from the default template
+// to a file, as Generate does, and returns the file's contents.
+func renderedPrompt(t *testing.T, analyses []*scanner.RepositoryAnalysis) string {
+	t.Helper()
+	log := logger.New(false)
+	promptTemplate, err := loadPromptTemplate("", log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	templateYAML, err := marshalTemplate(promptTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := buildTemplateVars("/path", nil, analyses, "/tmp", false, false)
+	path := filepath.Join(t.TempDir(), genericProvider{}.FileName())
+	if _, err := writePrompt(path, promptRenderer(genericProvider{}, templateYAML, vars), false, log); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestGenerate_NestedReposDetail(t *testing.T) {
 	analyses := []*scanner.RepositoryAnalysis{
 		{
 			Repository: scanner.Repository{Name: "sub-project", RelativePath: "sub"},
@@ -94,17 +146,41 @@ func TestBuildTemplateVars_NestedReposDetail(t *testing.T) {
 		},
 	}
 
-	vars := buildTemplateVars("/path", nil, analyses, "/tmp", false, false)
-	detail := vars["NESTED_REPOS_DETAIL"]
+	prompt := renderedPrompt(t, analyses)
 
-	if !strings.Contains(detail, "sub-project") {
-		t.Error("NESTED_REPOS_DETAIL should contain repository name")
+	for _, want := range []string{"### Codebase Summary", "### Repository 1: sub-project", "- Total Files: 20", "  - Go: 20 files (100%)"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q, got:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestGenerate_NestedReposDetailKeepsYAMLValid(t *testing.T) {
+	analyses := []*scanner.RepositoryAnalysis{
+		{Repository: scanner.Repository{Name: "api"}, Languages: map[string]int{"Go": 3}, TotalFiles: 3},
+		{Repository: scanner.Repository{Name: "web"}, Languages: map[string]int{"TypeScript": 2}, TotalFiles: 2},
+	}
+
+	prompt := renderedPrompt(t, analyses)
+	start := strings.Index(prompt, "```yaml\n")
+	end := strings.LastIndex(prompt, "\n```")
+	if start < 0 || end < start {
+		t.Fatalf("prompt has no YAML block:\n%s", prompt)
+	}
+
+	var parsed struct {
+		Prompt struct {
+			ScanParameters struct {
+				NestedReposDetail string `yaml:"nested_repos_detail"`
+			} `yaml:"scan_parameters"`
+		} `yaml:"prompt"`
 	}
-	if !strings.Contains(detail, "Go") {
-		t.Error("NESTED_REPOS_DETAIL should contain language")
+	if err := yaml.Unmarshal([]byte(prompt[start+len("```yaml\n"):end]), &parsed); err != nil {
+		t.Fatalf("prompt YAML does not parse: %v", err)
 	}
-	if !strings.Contains(detail, "20") {
-		t.Error("NESTED_REPOS_DETAIL should contain file count")
+	want := buildTemplateVars("/path", nil, analyses, "/tmp", false, false)["NESTED_REPOS_DETAIL"]
+	if got := parsed.Prompt.ScanParameters.NestedReposDetail; got != want {
+		t.Errorf("nested_repos_detail = %q, want %q", got, want)
 	}
 }
 
@@ -134,14 +210,14 @@ func TestRenderTemplate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := renderTemplate(tt.template, tt.vars)
+			result, err := renderTemplateFor(genericProvider{}, tt.template, tt.vars)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("renderTemplate() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("renderTemplateFor() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			for _, s := range tt.contains {
 				if !strings.Contains(result, s) {
-					t.Errorf("renderTemplate() result should contain %q", s)
+					t.Errorf("renderTemplateFor() result should contain %q", s)
 				}
 			}
 		})
@@ -225,3 +301,408 @@ func TestRenderMarkdown(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerate_EmbeddedTemplate(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repos := []scanner.Repository{{Path: repoDir, Name: "app", RelativePath: "."}}
+	outputDir := t.TempDir()
+
+	promptPath, err := Generate(context.Background(), repoDir, repos, outputDir, Options{}, logger.New(false))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(promptPath)
+	if err != nil {
+		t.Fatalf("failed to read generated prompt: %v", err)
+	}
+	if !strings.Contains(string(data), "phase1_codebase_analysis") {
+		t.Error("generated prompt should contain the embedded template content")
+	}
+	if !strings.Contains(string(data), repoDir) {
+		t.Error("generated prompt should contain the substituted target path")
+	}
+}
+
+func TestGenerate_CancelledWritesNothing(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repos := []scanner.Repository{{Path: repoDir, Name: "app", RelativePath: "."}}
+	outputDir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Generate(ctx, repoDir, repos, outputDir, Options{}, logger.New(false))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Generate() error = %v, want context.Canceled", err)
+	}
+
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("cancelled Generate() wrote %d entries to output dir, want none", len(entries))
+	}
+}
+
+func TestLoadTemplate_PrefersFileOnDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.yaml")
+	if err := os.WriteFile(path, []byte("custom: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := loadTemplate(path, logger.New(false))
+	if err != nil {
+		t.Fatalf("loadTemplate() error = %v", err)
+	}
+	if string(data) != "custom: true\n" {
+		t.Errorf("loadTemplate() = %q, want file contents", data)
+	}
+}
+
+func TestGenerate_CustomTemplate(t *testing.T) {
+	repoDir := t.TempDir()
+	repos := []scanner.Repository{{Path: repoDir, Name: "app", RelativePath: "."}}
+
+	templatePath := filepath.Join(t.TempDir(), "team.yaml")
+	custom := "metadata:\n  version: \"custom\"\nprompt:\n  context: \"TEAM-MARKER-7f3a for {{CODEBASE_NAME}}\"\n"
+	if err := os.WriteFile(templatePath, []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	promptPath, err := Generate(context.Background(), repoDir, repos, t.TempDir(), Options{TemplatePath: templatePath}, logger.New(false))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(promptPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "TEAM-MARKER-7f3a for "+filepath.Base(repoDir)) {
+		t.Errorf("expected custom template marker in output, got:\n%s", data)
+	}
+	if strings.Contains(string(data), "phase1_codebase_analysis") {
+		t.Error("default template content should not appear when a custom template is given")
+	}
+}
+
+func TestGenerate_InvalidCustomTemplate(t *testing.T) {
+	repoDir := t.TempDir()
+	repos := []scanner.Repository{{Path: repoDir, Name: "app", RelativePath: "."}}
+
+	badYAML := filepath.Join(t.TempDir(), "bad.yaml")
+	if err := os.WriteFile(badYAML, []byte("prompt: [unterminated"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{"missing file", filepath.Join(t.TempDir(), "missing.yaml"), "failed to read custom template"},
+		{"invalid YAML", badYAML, "failed to parse template YAML"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(context.Background(), repoDir, repos, t.TempDir(), Options{TemplatePath: tt.path}, logger.New(false))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Generate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFindUnresolvedPlaceholders(t *testing.T) {
+	tests := []struct {
+		name     string
+		rendered string
+		want     []string
+	}{
+		{"none", "all resolved", nil},
+		{"single", "value: {{UNKNOWN_VAR}}", []string{"{{UNKNOWN_VAR}}"}},
+		{"deduplicated and sorted", "{{B}} {{A}} {{B}}", []string{"{{A}}", "{{B}}"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findUnresolvedPlaceholders(tt.rendered)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("findUnresolvedPlaceholders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_UnresolvedPlaceholders(t *testing.T) {
+	repoDir := t.TempDir()
+	repos := []scanner.Repository{{Path: repoDir, Name: "app", RelativePath: "."}}
+
+	templatePath := filepath.Join(t.TempDir(), "unresolved.yaml")
+	custom := "prompt:\n  context: \"{{CODEBASE_NAME}} owned by {{TEAM_OWNER}}\"\n"
+	if err := os.WriteFile(templatePath, []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("fails by default", func(t *testing.T) {
+		_, err := Generate(context.Background(), repoDir, repos, t.TempDir(), Options{TemplatePath: templatePath}, logger.New(false))
+		if err == nil {
+			t.Fatal("Generate() should fail on unresolved placeholders")
+		}
+		if !strings.Contains(err.Error(), "{{TEAM_OWNER}}") {
+			t.Errorf("error should list the unresolved placeholder, got %v", err)
+		}
+	})
+
+	t.Run("allowed with escape hatch", func(t *testing.T) {
+		opts := Options{TemplatePath: templatePath, AllowUnresolved: true}
+		promptPath, err := Generate(context.Background(), repoDir, repos, t.TempDir(), opts, logger.New(false))
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		data, err := os.ReadFile(promptPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "{{TEAM_OWNER}}") {
+			t.Error("placeholder should be left in output when allowed")
+		}
+	})
+}
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		rendered string
+		want     int
+	}{
+		{"empty", "", 0},
+		{"exact multiple", "abcdefgh", 2},
+		{"rounds up", "abcde", 2},
+		{"counts runes not bytes", "ééééé", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateTokens(tt.rendered); got != tt.want {
+				t.Errorf("EstimateTokens(%q) = %d, want %d", tt.rendered, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWarnIfOversized(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokens    int
+		maxTokens int
+		wantWarn  bool
+	}{
+		{"below limit", 50, 100, false},
+		{"at limit", 100, 100, false},
+		{"above limit", 101, 100, true},
+		{"default limit not exceeded", DefaultMaxTokens, 0, false},
+		{"default limit exceeded", DefaultMaxTokens + 1, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			warnIfOversized(tt.tokens, tt.maxTokens, logger.NewWithWriter(&buf, false))
+
+			gotWarn := strings.Contains(buf.String(), "[WARN]")
+			if gotWarn != tt.wantWarn {
+				t.Errorf("warned = %v, want %v (output %q)", gotWarn, tt.wantWarn, buf.String())
+			}
+		})
+	}
+}
+
+func TestGenerate_WarnsOnOversizedPrompt(t *testing.T) {
+	repoDir := t.TempDir()
+	repos := []scanner.Repository{{Path: repoDir, Name: "app", RelativePath: "."}}
+
+	var buf bytes.Buffer
+	_, err := Generate(context.Background(), repoDir, repos, t.TempDir(), Options{MaxTokens: 10}, logger.NewWithWriter(&buf, false))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "exceeding the 10 token limit") {
+		t.Errorf("expected oversized prompt warning, got:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "tokens)") {
+		t.Errorf("expected token estimate in completion message, got:\n%s", buf.String())
+	}
+}
+
+func TestGenerateResult_Tokens(t *testing.T) {
+	repoDir := t.TempDir()
+	repos := []scanner.Repository{{Path: repoDir, Name: "app", RelativePath: "."}}
+
+	res, err := GenerateResult(context.Background(), repoDir, repos, t.TempDir(), Options{}, logger.New(false))
+	if err != nil {
+		t.Fatalf("GenerateResult() error = %v", err)
+	}
+	data, err := os.ReadFile(res.PromptPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := EstimateTokens(string(data)); res.Tokens != want {
+		t.Errorf("Result.Tokens = %d, want %d for the written prompt", res.Tokens, want)
+	}
+}
+
+func TestGenerate_LanguagePercentages(t *testing.T) {
+	analyses := []*scanner.RepositoryAnalysis{
+		{
+			Repository: scanner.Repository{Name: "app", RelativePath: "."},
+			Languages:  map[string]int{"Go": 120, "Python": 50, "Shell": 24},
+			TotalFiles: 194,
+		},
+	}
+
+	prompt := renderedPrompt(t, analyses)
+
+	if !strings.Contains(prompt, "Go: 120 files (62%)") {
+		t.Errorf("expected Go percentage, got:\n%s", prompt)
+	}
+
+	sum := 0
+	for _, m := range regexp.MustCompile(`\((\d+)%\)`).FindAllStringSubmatch(prompt, -1) {
+		pct, err := strconv.Atoi(m[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum += pct
+	}
+	if sum < 99 || sum > 101 {
+		t.Errorf("percentages sum to %d, want ~100", sum)
+	}
+}
+
+func TestGenerate_ZeroFilesPercentage(t *testing.T) {
+	analyses := []*scanner.RepositoryAnalysis{
+		{
+			Repository: scanner.Repository{Name: "empty", RelativePath: "."},
+			Languages:  map[string]int{"Go": 0},
+			TotalFiles: 0,
+		},
+	}
+
+	prompt := renderedPrompt(t, analyses)
+
+	if !strings.Contains(prompt, "Go: 0 files (0%)") {
+		t.Errorf("expected zero percentage, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "NaN") {
+		t.Errorf("zero files should not render NaN, got:\n%s", prompt)
+	}
+}
+
+func TestGenerate_BinaryRatio(t *testing.T) {
+	analyses := []*scanner.RepositoryAnalysis{
+		{
+			Repository:  scanner.Repository{Name: "assets", RelativePath: "."},
+			Languages:   map[string]int{},
+			TotalFiles:  4,
+			TextFiles:   1,
+			BinaryFiles: 3,
+		},
+	}
+
+	prompt := renderedPrompt(t, analyses)
+
+	if !strings.Contains(prompt, "1 text, 3 binary (75% binary)") {
+		t.Errorf("expected binary ratio in prompt, got:\n%s", prompt)
+	}
+}
+
+func TestGenerate_GitMetadata(t *testing.T) {
+	tests := []struct {
+		name  string
+		repo  scanner.Repository
+		wants []string
+	}{
+		{
+			name:  "branch and remote",
+			repo:  scanner.Repository{Name: "app", DefaultBranch: "main", RemoteURL: "git@github.com:org/app.git"},
+			wants: []string{"- Default Branch: main", "- Remote: git@github.com:org/app.git"},
+		},
+		{
+			name:  "detached without remote",
+			repo:  scanner.Repository{Name: "app"},
+			wants: []string{"- Default Branch: unknown", "- Remote: none"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt := renderedPrompt(t, []*scanner.RepositoryAnalysis{{Repository: tt.repo, Languages: map[string]int{}}})
+			for _, want := range tt.wants {
+				if !strings.Contains(prompt, want) {
+					t.Errorf("prompt missing %q, got:\n%s", want, prompt)
+				}
+			}
+		})
+	}
+}
+
+func TestLastCommitDetail(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		repo scanner.Repository
+		want string
+	}{
+		{"unknown", scanner.Repository{}, "- Last Commit: unknown\n"},
+		{
+			"recent",
+			scanner.Repository{LastCommitHash: "abcdef0123456789", LastCommitDate: time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)},
+			"- Last Commit: 2024-05-20 (abcdef0)\n",
+		},
+		{
+			"stale",
+			scanner.Repository{LastCommitHash: "abcdef0123456789", LastCommitDate: time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)},
+			"- Last Commit: 2022-01-02 (abcdef0) - STALE: no commits in over 12 months\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lastCommitDetail(tt.repo, now); got != tt.want {
+				t.Errorf("lastCommitDetail() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_StaleRepository(t *testing.T) {
+	analyses := []*scanner.RepositoryAnalysis{{
+		Repository: scanner.Repository{
+			Name:           "legacy",
+			LastCommitHash: "abcdef0123456789",
+			LastCommitDate: time.Now().AddDate(-2, 0, 0),
+		},
+		Languages: map[string]int{},
+	}}
+
+	prompt := renderedPrompt(t, analyses)
+
+	want := "(abcdef0) - STALE: no commits in over 12 months"
+	if !strings.Contains(prompt, want) {
+		t.Errorf("prompt missing %q, got:\n%s", want, prompt)
+	}
+}
@@ -0,0 +1,22 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+)
+
+// goModulesDetail renders the "- Go Modules:" line, naming each module
+// with its directory and Go version so module boundaries are explicit.
+func goModulesDetail(modules []scanner.GoModule) string {
+	parts := make([]string, len(modules))
+	for i, m := range modules {
+		where := m.Dir
+		if m.GoVersion != "" {
+			where += ", go " + m.GoVersion
+		}
+		parts[i] = fmt.Sprintf("%s (%s)", valueOr(m.Path, "unnamed"), where)
+	}
+	return fmt.Sprintf("- Go Modules (%d): %s\n", len(modules), strings.Join(parts, ", "))
+}
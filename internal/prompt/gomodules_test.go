@@ -0,0 +1,28 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+)
+
+func TestGenerate_GoModules(t *testing.T) {
+	analysis := &scanner.RepositoryAnalysis{
+		Repository: scanner.Repository{Name: "app"},
+		Languages:  map[string]int{"Go": 3},
+		GoModules: []scanner.GoModule{
+			{Path: "example.com/app", Dir: ".", GoVersion: "1.21"},
+			{Path: "example.com/app/tools", Dir: "tools"},
+		},
+	}
+	want := "- Go Modules (2): example.com/app (., go 1.21), example.com/app/tools (tools)\n"
+	if prompt := renderedPrompt(t, []*scanner.RepositoryAnalysis{analysis}); !strings.Contains(prompt, want) {
+		t.Errorf("expected %q in prompt, got:\n%s", want, prompt)
+	}
+
+	analysis.GoModules = nil
+	if prompt := renderedPrompt(t, []*scanner.RepositoryAnalysis{analysis}); strings.Contains(prompt, "Go Modules") {
+		t.Errorf("repository without go.mod should not list modules, got:\n%s", prompt)
+	}
+}
@@ -0,0 +1,91 @@
+package prompt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// includeTag marks a template node, such as
+//
+//	guidance: !include shared/guidance.yaml
+//
+// that is replaced by the contents of the named YAML file before
+// substitution. The path is relative to the including file.
+const includeTag = "!include"
+
+// ParseTemplate parses template YAML read from path, inlining each
+// !include node. Included paths are resolved relative to the including
+// file, and an include that leads back to a file already being included is
+// reported as a cycle. Empty data yields a nil map.
+func ParseTemplate(data []byte, path string) (map[string]interface{}, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 {
+		return nil, nil
+	}
+
+	var chain []string
+	if abs, err := filepath.Abs(path); err == nil {
+		chain = append(chain, abs)
+	}
+	if err := resolveIncludes(&doc, filepath.Dir(path), chain); err != nil {
+		return nil, err
+	}
+
+	var tmpl map[string]interface{}
+	if err := doc.Decode(&tmpl); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// resolveIncludes replaces each !include node under node, in place, with
+// the document it names. chain lists the absolute paths of the files
+// being included, outermost first.
+func resolveIncludes(node *yaml.Node, dir string, chain []string) error {
+	if node.Tag != includeTag {
+		for _, child := range node.Content {
+			if err := resolveIncludes(child, dir, chain); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if node.Kind != yaml.ScalarNode || node.Value == "" {
+		return fmt.Errorf("line %d: %s needs a file path", node.Line, includeTag)
+	}
+	path, err := filepath.Abs(filepath.Join(dir, node.Value))
+	if err != nil {
+		return fmt.Errorf("line %d: %s %s: %w", node.Line, includeTag, node.Value, err)
+	}
+	for _, seen := range chain {
+		if seen == path {
+			return fmt.Errorf("%s cycle: %s", includeTag, strings.Join(append(chain, path), " -> "))
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("line %d: %s %s: %w", node.Line, includeTag, node.Value, err)
+	}
+	var included yaml.Node
+	if err := yaml.Unmarshal(data, &included); err != nil {
+		return fmt.Errorf("%s %s: %w", includeTag, path, err)
+	}
+	if len(included.Content) == 0 {
+		return fmt.Errorf("%s %s: file is empty", includeTag, path)
+	}
+	content := included.Content[0]
+	if err := resolveIncludes(content, filepath.Dir(path), append(chain, path)); err != nil {
+		return err
+	}
+	*node = *content
+	return nil
+}
@@ -0,0 +1,113 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// writeTemplateFiles writes files, keyed by slash-separated path, under dir.
+func writeTemplateFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadPromptTemplate_Include(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplateFiles(t, dir, map[string]string{
+		"templates/project.yaml": "prompt:\n  context: \"Review {{TARGET_PATH}}\"\n  guidance: !include ../shared/guidance.yaml\n",
+		"shared/guidance.yaml":   "- Map findings to CWE IDs\n- Never quote proprietary code\n",
+	})
+
+	tmpl, err := loadPromptTemplate(filepath.Join(dir, "templates", "project.yaml"), logger.New(false))
+	if err != nil {
+		t.Fatalf("loadPromptTemplate() error = %v", err)
+	}
+
+	promptSection, ok := tmpl["prompt"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("prompt section missing: %v", tmpl)
+	}
+	guidance, ok := promptSection["guidance"].([]interface{})
+	if !ok || len(guidance) != 2 || guidance[0] != "Map findings to CWE IDs" {
+		t.Errorf("guidance = %#v, want the included list", promptSection["guidance"])
+	}
+
+	rendered, err := renderTemplateFor(genericProvider{}, tmpl, map[string]string{"TARGET_PATH": "/work"})
+	if err != nil {
+		t.Fatalf("renderTemplateFor() error = %v", err)
+	}
+	if !strings.Contains(rendered, "Never quote proprietary code") || !strings.Contains(rendered, "Review /work") {
+		t.Errorf("rendered prompt should contain the included block and substitutions:\n%s", rendered)
+	}
+}
+
+func TestParseTemplate_IncludeCycle(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{
+			name:  "self include",
+			files: map[string]string{"main.yaml": "prompt: !include main.yaml\n"},
+		},
+		{
+			name: "indirect",
+			files: map[string]string{
+				"main.yaml": "prompt: !include a.yaml\n",
+				"a.yaml":    "nested: !include b.yaml\n",
+				"b.yaml":    "back: !include a.yaml\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTemplateFiles(t, dir, tt.files)
+			path := filepath.Join(dir, "main.yaml")
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := ParseTemplate(data, path); err == nil || !strings.Contains(err.Error(), "!include cycle") {
+				t.Errorf("ParseTemplate() error = %v, want an include cycle", err)
+			}
+		})
+	}
+}
+
+func TestParseTemplate_IncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplateFiles(t, dir, map[string]string{"empty.yaml": ""})
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"missing file", "prompt: !include nope.yaml\n", "nope.yaml"},
+		{"empty file", "prompt: !include empty.yaml\n", "file is empty"},
+		{"not a path", "prompt: !include [a, b]\n", "needs a file path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTemplate([]byte(tt.data), filepath.Join(dir, "main.yaml"))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseTemplate() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
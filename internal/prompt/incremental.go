@@ -0,0 +1,73 @@
+package prompt
+
+import (
+	"context"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// previousAnalyses returns the analyses in outputDir's cache, keyed by
+// repository path, whatever fingerprint they were recorded under. It is
+// empty when there is no usable cache, including one recorded with
+// analysis options other than opts.
+func previousAnalyses(outputDir string, opts scanner.ScanOptions) map[string]*scanner.RepositoryAnalysis {
+	byPath := make(map[string]*scanner.RepositoryAnalysis)
+	cached, ok := scanner.LoadStaleAnalysisCache(outputDir, opts)
+	if !ok {
+		return byPath
+	}
+	for _, analysis := range cached {
+		if analysis != nil {
+			byPath[analysis.Repository.Path] = analysis
+		}
+	}
+	return byPath
+}
+
+// updateIncrementally re-analyzes only the files git reports as changed in
+// repo, plus those whose size or modification time no longer match the
+// previous analysis, and merges them into it. It reports false when the
+// previous analysis cannot be reused: there is none, it predates per-file
+// data, it was recorded at a different commit (files committed since would
+// be missed), or git is unavailable. The caller then falls back to a full
+// analysis.
+func updateIncrementally(ctx context.Context, repo scanner.Repository, previous *scanner.RepositoryAnalysis, opts scanner.ScanOptions, log *logger.Logger) (*scanner.RepositoryAnalysis, bool) {
+	if previous == nil || previous.Files == nil {
+		log.Debug("No cached analysis for %s; running a full scan", repo.Name)
+		return nil, false
+	}
+	if previous.Repository.LastCommitHash != repo.LastCommitHash {
+		log.Debug("%s has new commits since its cached analysis; running a full scan", repo.Name)
+		return nil, false
+	}
+	changed, err := scanner.ChangedFiles(repo)
+	if err != nil {
+		log.Debug("Cannot list changed files in %s; running a full scan: %v", repo.Name, err)
+		return nil, false
+	}
+
+	previous.Repository = repo
+	changed = mergePaths(changed, scanner.ModifiedFiles(previous))
+	if err := scanner.UpdateAnalysis(ctx, previous, changed, opts); err != nil {
+		log.Debug("Cannot update cached analysis of %s; running a full scan: %v", repo.Name, err)
+		return nil, false
+	}
+	log.Info("Updated %s incrementally (%d changed file(s))", repo.Name, len(changed))
+	return previous, true
+}
+
+// mergePaths returns the paths in a followed by those in b that a lacks.
+func mergePaths(a, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	for _, path := range a {
+		seen[path] = true
+	}
+	for _, path := range b {
+		if !seen[path] {
+			seen[path] = true
+			a = append(a, path)
+		}
+	}
+	return a
+}
@@ -0,0 +1,202 @@
+package prompt
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// initGitRepo commits files to a new repository in a temporary directory.
+func initGitRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{{"init", "-q"}, {"add", "."}, {"commit", "-qm", "initial"}} {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+// inflateCache adds extra to the cached TotalFiles so that a merged update
+// is distinguishable from a fresh walk.
+func inflateCache(t *testing.T, outputDir string, extra int) {
+	t.Helper()
+	cached, ok := scanner.LoadStaleAnalysisCache(outputDir, scanner.ScanOptions{})
+	if !ok {
+		t.Fatal("no analysis cache written")
+	}
+	cached[0].TotalFiles += extra
+	if err := scanner.SaveAnalysisCache(outputDir, "sha256:stale", scanner.ScanOptions{}, cached); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAnalyzeRepositories_Incremental(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      Options
+		commit    string
+		wantFiles int
+	}{
+		{name: "merges changed files into cache", opts: Options{Incremental: true}, wantFiles: 102},
+		{name: "full scan without incremental", wantFiles: 2},
+		{name: "full scan after a new commit", opts: Options{Incremental: true}, commit: "ffff", wantFiles: 2},
+		{name: "no-cache forces full scan", opts: Options{Incremental: true, NoCache: true}, wantFiles: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := initGitRepo(t, map[string]string{"main.go": "package main\n"})
+			outputDir := t.TempDir()
+			repos := []scanner.Repository{{Path: repoDir, Name: "repo"}}
+			log := logger.New(false)
+
+			if _, _, err := analyzeRepositories(context.Background(), repos, outputDir, Options{}, log); err != nil {
+				t.Fatal(err)
+			}
+			inflateCache(t, outputDir, 100)
+
+			if err := os.WriteFile(filepath.Join(repoDir, "util.go"), []byte("package main\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			repos[0].LastCommitHash += tt.commit
+
+			analyses, _, err := analyzeRepositories(context.Background(), repos, outputDir, tt.opts, log)
+			if err != nil {
+				t.Fatalf("analyzeRepositories() error = %v", err)
+			}
+			if len(analyses) != 1 {
+				t.Fatalf("got %d analyses, want 1", len(analyses))
+			}
+			if analyses[0].TotalFiles != tt.wantFiles {
+				t.Errorf("TotalFiles = %d, want %d", analyses[0].TotalFiles, tt.wantFiles)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepositories_IncrementalWithoutGit(t *testing.T) {
+	repoDir := t.TempDir()
+	outputDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repos := []scanner.Repository{{Path: repoDir, Name: "repo"}}
+	log := logger.New(false)
+
+	// No cache yet: a full scan.
+	analyses, _, err := analyzeRepositories(context.Background(), repos, outputDir, Options{Incremental: true}, log)
+	if err != nil || len(analyses) != 1 || analyses[0].TotalFiles != 1 {
+		t.Fatalf("analyzeRepositories() = %v, %v, want one analysis of 1 file", analyses, err)
+	}
+
+	// A stale cache outside a git work tree also falls back to a full scan.
+	inflateCache(t, outputDir, 100)
+	if err := os.WriteFile(filepath.Join(repoDir, "util.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	analyses, _, err = analyzeRepositories(context.Background(), repos, outputDir, Options{Incremental: true}, log)
+	if err != nil {
+		t.Fatalf("analyzeRepositories() error = %v", err)
+	}
+	if got := analyses[0].TotalFiles; got != 2 {
+		t.Errorf("TotalFiles = %d, want 2 (full scan)", got)
+	}
+}
+
+func TestAnalyzeRepositories_IncrementalRechecksCachedFiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		before    map[string]string
+		after     func(t *testing.T, repoDir string)
+		wantFiles int
+		wantLines int
+	}{
+		{
+			name:   "deleted untracked file",
+			before: map[string]string{"notes.py": "x = 1\n"},
+			after: func(t *testing.T, repoDir string) {
+				if err := os.Remove(filepath.Join(repoDir, "notes.py")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantFiles: 1,
+			wantLines: 1,
+		},
+		{
+			name:   "reverted edit",
+			before: map[string]string{"main.go": "package main\n\nfunc main() {}\n"},
+			after: func(t *testing.T, repoDir string) {
+				if err := os.WriteFile(filepath.Join(repoDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantFiles: 1,
+			wantLines: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := initGitRepo(t, map[string]string{"main.go": "package main\n"})
+			writeFiles(t, repoDir, tt.before)
+			outputDir := t.TempDir()
+			repos := []scanner.Repository{{Path: repoDir, Name: "repo"}}
+			opts := Options{Incremental: true}
+			log := logger.New(false)
+
+			if _, _, err := analyzeRepositories(context.Background(), repos, outputDir, opts, log); err != nil {
+				t.Fatal(err)
+			}
+			tt.after(t, repoDir)
+
+			analyses, _, err := analyzeRepositories(context.Background(), repos, outputDir, opts, log)
+			if err != nil {
+				t.Fatalf("analyzeRepositories() error = %v", err)
+			}
+			if got := analyses[0]; got.TotalFiles != tt.wantFiles || got.TotalLines != tt.wantLines {
+				t.Errorf("TotalFiles, TotalLines = %d, %d, want %d, %d", got.TotalFiles, got.TotalLines, tt.wantFiles, tt.wantLines)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepositories_IncrementalRequiresSameSettings(t *testing.T) {
+	repoDir := initGitRepo(t, map[string]string{"main.go": "package main\n"})
+	outputDir := t.TempDir()
+	repos := []scanner.Repository{{Path: repoDir, Name: "repo"}}
+	log := logger.New(false)
+
+	if _, _, err := analyzeRepositories(context.Background(), repos, outputDir, Options{}, log); err != nil {
+		t.Fatal(err)
+	}
+	inflateCache(t, outputDir, 100)
+
+	opts := Options{Incremental: true}
+	opts.Scan.OnlyLanguages = []string{"Python"}
+	analyses, _, err := analyzeRepositories(context.Background(), repos, outputDir, opts, log)
+	if err != nil {
+		t.Fatalf("analyzeRepositories() error = %v", err)
+	}
+	if got := analyses[0].TotalFiles; got != 1 {
+		t.Errorf("TotalFiles = %d, want 1 (full scan, not the cache recorded with other settings)", got)
+	}
+	if _, ok := analyses[0].Languages["Go"]; ok {
+		t.Errorf("Languages = %v, want Go counted under the other-language bucket", analyses[0].Languages)
+	}
+}
@@ -0,0 +1,49 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+)
+
+// maxListedLargeFiles caps how many files largeFilesDetail names.
+const maxListedLargeFiles = 3
+
+// largeFilesDetail renders the "- Large Files:" warning line, counting the
+// files above the size threshold and naming the largest, or "" when there
+// are none.
+func largeFilesDetail(files []scanner.LargeFile) string {
+	if len(files) == 0 {
+		return ""
+	}
+	listed := files
+	var more string
+	if len(listed) > maxListedLargeFiles {
+		listed = listed[:maxListedLargeFiles]
+		more = fmt.Sprintf(", +%d more", len(files)-maxListedLargeFiles)
+	}
+	parts := make([]string, len(listed))
+	for i, f := range listed {
+		parts[i] = fmt.Sprintf("%s %s", f.Path, formatSize(f.SizeBytes))
+	}
+	return fmt.Sprintf("- Large Files: %d warning(s), possibly committed artifacts (%s%s)\n",
+		len(files), strings.Join(parts, ", "), more)
+}
+
+// formatSize renders a byte count in the largest binary unit it reaches,
+// such as "512 B", "1.5 KB", or "12.0 MB".
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value := float64(n) / unit
+	suffixes := []string{"KB", "MB", "GB", "TB"}
+	i := 0
+	for value >= unit && i < len(suffixes)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", value, suffixes[i])
+}
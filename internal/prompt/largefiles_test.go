@@ -0,0 +1,68 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+)
+
+func TestLargeFilesDetail(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []scanner.LargeFile
+		want  string
+	}{
+		{"none", nil, ""},
+		{"one", []scanner.LargeFile{{Path: "assets/app.tar", SizeBytes: 12 << 20}},
+			"- Large Files: 1 warning(s), possibly committed artifacts (assets/app.tar 12.0 MB)\n"},
+		{"capped", []scanner.LargeFile{
+			{Path: "a", SizeBytes: 4 << 30}, {Path: "b", SizeBytes: 6 << 20}, {Path: "c", SizeBytes: 6 << 20}, {Path: "d", SizeBytes: 1536},
+		}, "- Large Files: 4 warning(s), possibly committed artifacts (a 4.0 GB, b 6.0 MB, c 6.0 MB, +1 more)\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := largeFilesDetail(tt.files); got != tt.want {
+				t.Errorf("largeFilesDetail() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KB"},
+		{5 << 20, "5.0 MB"},
+		{3 << 40, "3.0 TB"},
+		{2048 << 40, "2048.0 TB"},
+	}
+	for _, tt := range tests {
+		if got := formatSize(tt.n); got != tt.want {
+			t.Errorf("formatSize(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestGenerate_LargeFileWarnings(t *testing.T) {
+	analyses := []*scanner.RepositoryAnalysis{{
+		Repository: scanner.Repository{Name: "app"},
+		LargeFiles: []scanner.LargeFile{{Path: "dump.sql", SizeBytes: 8 << 20}},
+	}}
+	prompt := renderedPrompt(t, analyses)
+	if !strings.Contains(prompt, "- Large File Warnings: 1\n") {
+		t.Errorf("summary is missing the large file count:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "- Large Files: 1 warning(s), possibly committed artifacts (dump.sql 8.0 MB)\n") {
+		t.Errorf("repository detail is missing its large files:\n%s", prompt)
+	}
+
+	quiet := []*scanner.RepositoryAnalysis{{Repository: scanner.Repository{Name: "app"}}}
+	if prompt := renderedPrompt(t, quiet); strings.Contains(prompt, "Large File") {
+		t.Errorf("expected no large file lines without large files, got:\n%s", prompt)
+	}
+}
@@ -0,0 +1,246 @@
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// markdownTemplate lays out the YAML prompt as Markdown for RenderMarkdown.
+const markdownTemplate = `# Codebase Analysis Prompt
+
+## Metadata
+- Version: {{.metadata.version}}
+- Type: {{.metadata.template_type}}
+- Security Level: {{.metadata.security_level}}
+
+## Context
+{{.prompt.context}}
+
+## Scan Parameters
+- Target Path: {{.prompt.scan_parameters.target_path}}
+- Scan Mode: {{.prompt.scan_parameters.scan_mode}}
+- Verbose: {{.prompt.scan_parameters.verbose}}
+
+## Nested Repositories
+{{.prompt.scan_parameters.nested_repos_detail}}
+
+## Tasks
+{{range .prompt.tasks}}
+### {{.name}} ({{.task_id}})
+{{.description}}
+{{end}}
+
+## Output Requirements
+- Primary Output: {{.prompt.output_requirements.primary_output}}
+- Phase 2 Tools: {{.prompt.output_requirements.phase2_tools}}
+- Reference Materials: {{.prompt.output_requirements.reference_materials}}
+
+## Success Criteria
+{{range .prompt.success_criteria}}
+- {{.}}
+{{end}}
+
+## Guidance Specification
+
+### Code Quality
+{{range .guidance_spec.code_quality}}
+- {{.}}
+{{end}}
+
+### Performance
+{{range .guidance_spec.performance}}
+- {{.}}
+{{end}}
+
+### Error Handling
+{{range .guidance_spec.error_handling}}
+- {{.}}
+{{end}}
+
+### Security
+{{range .guidance_spec.security}}
+- {{.}}
+{{end}}
+`
+
+// markdownFuncs are the helpers available to the Markdown template:
+//
+//	default DEF VALUE  VALUE, or DEF when VALUE is missing or empty
+//	upper S            S in upper case
+//	join SEP LIST      the items of LIST separated by SEP
+//	trim S             S without leading and trailing white space
+var markdownFuncs = template.FuncMap{
+	"default": defaultValue,
+	"upper":   strings.ToUpper,
+	"join":    joinItems,
+	"trim":    strings.TrimSpace,
+}
+
+// markdownFields lists every field markdownTemplate reads, with the value
+// used when it is absent. A list holding one map describes the fields of
+// each list item.
+var markdownFields = map[string]interface{}{
+	"metadata": map[string]interface{}{
+		"version":        "",
+		"template_type":  "",
+		"security_level": "",
+	},
+	"prompt": map[string]interface{}{
+		"context": "",
+		"scan_parameters": map[string]interface{}{
+			"target_path":         "",
+			"scan_mode":           "",
+			"verbose":             "",
+			"nested_repos_detail": "",
+		},
+		"tasks": []interface{}{
+			map[string]interface{}{"name": "", "task_id": "", "description": ""},
+		},
+		"output_requirements": map[string]interface{}{
+			"primary_output":      "",
+			"phase2_tools":        "",
+			"reference_materials": "",
+		},
+		"success_criteria": []interface{}{},
+	},
+	"guidance_spec": map[string]interface{}{
+		"code_quality":   []interface{}{},
+		"performance":    []interface{}{},
+		"error_handling": []interface{}{},
+		"security":       []interface{}{},
+	},
+}
+
+// RenderMarkdown converts the YAML prompt to a readable markdown format.
+//
+// Every field is optional. Absent metadata, prompt, and guidance_spec
+// sections, their scalar fields, and the fields of each prompt.tasks entry
+// render as empty text; absent lists (prompt.tasks, prompt.success_criteria,
+// and the guidance_spec lists) render no items.
+func RenderMarkdown(yamlData map[string]interface{}) (string, error) {
+	return RenderMarkdownWithFuncs(yamlData, nil)
+}
+
+// RenderMarkdownWithFuncs is RenderMarkdown with additional template
+// functions. Entries in funcs replace built-in helpers of the same name.
+func RenderMarkdownWithFuncs(yamlData map[string]interface{}, funcs template.FuncMap) (string, error) {
+	return executeMarkdown(markdownTemplate, normalizeFields(yamlData, markdownFields), funcs)
+}
+
+// normalizeFields returns a copy of data in which every field in schema is
+// present, filling absent or nil fields from schema and normalizing nested
+// maps and list items recursively. data itself is not modified.
+func normalizeFields(data, schema map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(data)+len(schema))
+	for k, v := range data {
+		out[k] = v
+	}
+	for key, def := range schema {
+		out[key] = normalizeValue(out[key], def)
+	}
+	return out
+}
+
+// normalizeValue fills value from the schema entry def.
+func normalizeValue(value, def interface{}) interface{} {
+	switch d := def.(type) {
+	case map[string]interface{}:
+		if m, ok := value.(map[string]interface{}); ok {
+			return normalizeFields(m, d)
+		}
+		if value == nil {
+			return normalizeFields(nil, d)
+		}
+	case []interface{}:
+		if value == nil {
+			return []interface{}{}
+		}
+		if len(d) == 1 {
+			if item, ok := d[0].(map[string]interface{}); ok {
+				return normalizeItems(value, item)
+			}
+		}
+	default:
+		if value == nil {
+			return def
+		}
+	}
+	return value
+}
+
+// normalizeItems fills the fields of each map in the list value.
+func normalizeItems(value interface{}, item map[string]interface{}) interface{} {
+	switch list := value.(type) {
+	case []interface{}:
+		out := make([]interface{}, len(list))
+		for i, v := range list {
+			out[i] = normalizeValue(v, item)
+		}
+		return out
+	case []map[string]interface{}:
+		out := make([]map[string]interface{}, len(list))
+		for i, v := range list {
+			out[i] = normalizeFields(v, item)
+		}
+		return out
+	}
+	return value
+}
+
+// executeMarkdown parses text with the built-in helpers plus funcs and
+// executes it against data.
+func executeMarkdown(text string, data map[string]interface{}, funcs template.FuncMap) (string, error) {
+	t, err := template.New("prompt").Funcs(markdownFuncs).Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// defaultValue returns value unless it is nil or an empty string, slice, or
+// map, in which case it returns def.
+func defaultValue(def, value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return def
+	case string:
+		if v == "" {
+			return def
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return def
+		}
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return def
+		}
+	}
+	return value
+}
+
+// joinItems formats each item of list and joins them with sep. A nil list
+// joins to "".
+func joinItems(sep string, list interface{}) (string, error) {
+	switch items := list.(type) {
+	case nil:
+		return "", nil
+	case []string:
+		return strings.Join(items, sep), nil
+	case []interface{}:
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, sep), nil
+	}
+	return "", fmt.Errorf("join: cannot join %T", list)
+}
@@ -0,0 +1,125 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestMarkdownFuncs(t *testing.T) {
+	data := map[string]interface{}{
+		"metadata": map[string]interface{}{"version": "2.0", "owner": ""},
+		"tags":     []interface{}{"go", "cli", 3},
+		"title":    "  padded  ",
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"default on missing field", `{{default "unversioned" .metadata.missing}}`, "unversioned"},
+		{"default on missing section", `{{default "n/a" .absent}}`, "n/a"},
+		{"default on empty string", `{{default "nobody" .metadata.owner}}`, "nobody"},
+		{"default keeps present value", `{{default "unversioned" .metadata.version}}`, "2.0"},
+		{"upper", `{{upper "security"}}`, "SECURITY"},
+		{"join", `{{join ", " .tags}}`, "go, cli, 3"},
+		{"join missing list", `[{{join ", " .missing}}]`, "[]"},
+		{"trim", `[{{trim .title}}]`, "[padded]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := executeMarkdown(tt.text, data, nil)
+			if err != nil {
+				t.Fatalf("executeMarkdown() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("executeMarkdown(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownWithFuncs(t *testing.T) {
+	data := map[string]interface{}{
+		"metadata": map[string]interface{}{"version": "1.0", "template_type": "phase1", "security_level": "internal"},
+		"prompt":   map[string]interface{}{"context": "Analyze the repo"},
+	}
+
+	plain, err := RenderMarkdown(data)
+	if err != nil {
+		t.Fatalf("RenderMarkdown() error = %v", err)
+	}
+	withNil, err := RenderMarkdownWithFuncs(data, nil)
+	if err != nil {
+		t.Fatalf("RenderMarkdownWithFuncs() error = %v", err)
+	}
+	if plain != withNil {
+		t.Error("RenderMarkdownWithFuncs with no funcs should match RenderMarkdown")
+	}
+
+	custom := template.FuncMap{"shout": func(s string) string { return strings.ToUpper(s) + "!" }}
+	got, err := executeMarkdown(`{{shout .prompt.context}}`, data, custom)
+	if err != nil {
+		t.Fatalf("executeMarkdown() with custom funcs error = %v", err)
+	}
+	if got != "ANALYZE THE REPO!" {
+		t.Errorf("custom func output = %q, want %q", got, "ANALYZE THE REPO!")
+	}
+}
+
+func TestRenderMarkdown_PartialData(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     map[string]interface{}
+		contains []string
+	}{
+		{"nil map", nil, []string{"# Codebase Analysis Prompt", "## Tasks"}},
+		{"empty map", map[string]interface{}{}, []string{"# Codebase Analysis Prompt", "- Version: \n"}},
+		{
+			name: "missing prompt",
+			data: map[string]interface{}{
+				"metadata":      map[string]interface{}{"version": "2.0"},
+				"guidance_spec": map[string]interface{}{"security": []interface{}{"No secrets"}},
+			},
+			contains: []string{"- Version: 2.0\n", "- Type: \n", "- Target Path: \n", "- No secrets"},
+		},
+		{
+			name: "task missing fields",
+			data: map[string]interface{}{
+				"prompt": map[string]interface{}{
+					"tasks": []interface{}{map[string]interface{}{"name": "Scan"}},
+				},
+			},
+			contains: []string{"### Scan ()"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderMarkdown(tt.data)
+			if err != nil {
+				t.Fatalf("RenderMarkdown() error = %v", err)
+			}
+			if strings.Contains(got, "<no value>") {
+				t.Errorf("RenderMarkdown() rendered <no value>:\n%s", got)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("RenderMarkdown() missing %q in:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderMarkdown_DoesNotModifyInput(t *testing.T) {
+	data := map[string]interface{}{"metadata": map[string]interface{}{"version": "1.0"}}
+	if _, err := RenderMarkdown(data); err != nil {
+		t.Fatalf("RenderMarkdown() error = %v", err)
+	}
+	if len(data) != 1 || len(data["metadata"].(map[string]interface{})) != 1 {
+		t.Errorf("RenderMarkdown() modified its input: %v", data)
+	}
+}
@@ -0,0 +1,179 @@
+package prompt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Provider formats the substituted YAML prompt into the structure preferred
+// by a particular LLM.
+type Provider interface {
+	// Name returns the provider's CLI identifier.
+	Name() string
+	// FileName returns the file name of the rendered prompt.
+	FileName() string
+	// Format wraps yamlStr in provider-specific scaffolding. outputDir is
+	// the location the assistant must write its outputs to.
+	Format(yamlStr, outputDir string) (string, error)
+}
+
+// Supported provider names.
+const (
+	ProviderGeneric = "generic"
+	ProviderClaude  = "claude"
+	ProviderOpenAI  = "openai"
+)
+
+// ParseProvider returns the Provider registered under name. An empty name
+// selects the generic provider.
+func ParseProvider(name string) (Provider, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", ProviderGeneric:
+		return genericProvider{}, nil
+	case ProviderClaude:
+		return claudeProvider{}, nil
+	case ProviderOpenAI:
+		return openAIProvider{}, nil
+	}
+	return nil, fmt.Errorf("unknown provider %q (supported: generic, claude, openai)", name)
+}
+
+// analysisSteps are the numbered instructions given to the assistant by
+// every provider.
+var analysisSteps = []string{
+	"Perform a deep scan of the codebase",
+	"Design reference materials strategy",
+	"Design Phase 2 tools",
+	"Implement Phase 2 tools in Go",
+	"Generate initial reference materials",
+	"Validate security compliance",
+}
+
+// writeSteps writes analysisSteps as a numbered list.
+func writeSteps(w io.Writer) {
+	for i, step := range analysisSteps {
+		fmt.Fprintf(w, "%d. %s\n", i+1, step)
+	}
+}
+
+// streamingProvider is implemented by providers that can write their
+// scaffolding around the YAML prompt as it is produced, so the rendered
+// prompt never has to be held in memory. formatTo writes exactly what
+// Format returns; writeYAML writes the substituted YAML to its argument.
+// Write errors on w are not reported; callers writing to a file use a
+// bufio.Writer and check its Flush.
+type streamingProvider interface {
+	formatTo(w io.Writer, writeYAML func(io.Writer) error, outputDir string) error
+}
+
+// yamlString adapts an already rendered YAML string to formatTo.
+func yamlString(yamlStr string) func(io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := io.WriteString(w, yamlStr)
+		return err
+	}
+}
+
+// genericProvider renders the prompt as Markdown with a fenced YAML block.
+type genericProvider struct{}
+
+func (genericProvider) Name() string     { return ProviderGeneric }
+func (genericProvider) FileName() string { return formatFiles[FormatMarkdown] }
+
+func (p genericProvider) Format(yamlStr, outputDir string) (string, error) {
+	var b strings.Builder
+	err := p.formatTo(&b, yamlString(yamlStr), outputDir)
+	return b.String(), err
+}
+
+func (genericProvider) formatTo(w io.Writer, writeYAML func(io.Writer) error, outputDir string) error {
+	io.WriteString(w, "# Phase 1 LLM Prompt - Codebase Analysis\n\n")
+	io.WriteString(w, "**SECURITY NOTICE:** This prompt contains references to proprietary code.\n")
+	fmt.Fprintf(w, "All outputs must be written to %s or .gitignore'd locations.\n\n", outputDir)
+	io.WriteString(w, "---\n\n")
+	io.WriteString(w, "```yaml\n")
+	if err := writeYAML(w); err != nil {
+		return err
+	}
+	io.WriteString(w, "\n```\n\n")
+	io.WriteString(w, "---\n\n")
+	io.WriteString(w, "## Instructions for AI Assistant\n\n")
+	io.WriteString(w, "Please process the above YAML prompt and:\n\n")
+	writeSteps(w)
+	io.WriteString(w, "\n")
+	io.WriteString(w, "All outputs must go to:\n")
+	fmt.Fprintf(w, "- %s\n\n", outputDir)
+	return nil
+}
+
+// claudeProvider wraps the prompt sections in XML tags, which Claude models
+// use to distinguish context from instructions.
+type claudeProvider struct{}
+
+func (claudeProvider) Name() string     { return ProviderClaude }
+func (claudeProvider) FileName() string { return formatFiles[FormatMarkdown] }
+
+func (p claudeProvider) Format(yamlStr, outputDir string) (string, error) {
+	var b strings.Builder
+	err := p.formatTo(&b, yamlString(yamlStr), outputDir)
+	return b.String(), err
+}
+
+func (claudeProvider) formatTo(w io.Writer, writeYAML func(io.Writer) error, outputDir string) error {
+	io.WriteString(w, "<security_notice>\n")
+	io.WriteString(w, "This prompt contains references to proprietary code.\n")
+	fmt.Fprintf(w, "All outputs must be written to %s or .gitignore'd locations.\n", outputDir)
+	io.WriteString(w, "</security_notice>\n\n")
+	io.WriteString(w, "<context>\n")
+	if err := writeYAML(w); err != nil {
+		return err
+	}
+	io.WriteString(w, "</context>\n\n")
+	io.WriteString(w, "<instructions>\n")
+	io.WriteString(w, "Process the YAML prompt in the <context> tags and:\n\n")
+	writeSteps(w)
+	io.WriteString(w, "\n")
+	fmt.Fprintf(w, "All outputs must go to: %s\n", outputDir)
+	io.WriteString(w, "</instructions>\n")
+	return nil
+}
+
+// openAIProvider emits a chat-completions style message list with the
+// instructions in a system message and the YAML prompt as the user message.
+type openAIProvider struct{}
+
+// chatMessage is a single entry in an OpenAI chat-completions request.
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+func (openAIProvider) Name() string     { return ProviderOpenAI }
+func (openAIProvider) FileName() string { return "phase1-llm-prompt.chat.json" }
+
+func (openAIProvider) Format(yamlStr, outputDir string) (string, error) {
+	var system bytes.Buffer
+	system.WriteString("You are an expert software architect and code analyst. ")
+	system.WriteString(fmt.Sprintf("The prompt references proprietary code; all outputs must be written to %s or .gitignore'd locations.\n\n", outputDir))
+	system.WriteString("Process the user's YAML prompt and:\n\n")
+	writeSteps(&system)
+	system.WriteString(fmt.Sprintf("\nAll outputs must go to: %s\n", outputDir))
+
+	payload := struct {
+		Messages []chatMessage `json:"messages"`
+	}{
+		Messages: []chatMessage{
+			{Role: "system", Content: system.String()},
+			{Role: "user", Content: yamlStr},
+		},
+	}
+
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal chat messages: %w", err)
+	}
+	return string(data) + "\n", nil
+}
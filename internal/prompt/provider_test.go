@@ -0,0 +1,138 @@
+package prompt
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", ProviderGeneric, false},
+		{"generic", ProviderGeneric, false},
+		{"Claude", ProviderClaude, false},
+		{"openai", ProviderOpenAI, false},
+		{"gemini", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParseProvider(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseProvider(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if !tt.wantErr && p.Name() != tt.want {
+				t.Errorf("ParseProvider(%q).Name() = %q, want %q", tt.name, p.Name(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGenericProviderMatchesDefaultLayout(t *testing.T) {
+	template := map[string]interface{}{"test": "{{VALUE}}"}
+	vars := map[string]string{"VALUE": "hello", "OUTPUT_DIR": "/tmp/out"}
+
+	got, err := renderTemplateFor(genericProvider{}, template, vars)
+	if err != nil {
+		t.Fatalf("renderTemplateFor() error = %v", err)
+	}
+
+	want := "# Phase 1 LLM Prompt - Codebase Analysis\n\n" +
+		"**SECURITY NOTICE:** This prompt contains references to proprietary code.\n" +
+		"All outputs must be written to /tmp/out or .gitignore'd locations.\n\n" +
+		"---\n\n" +
+		"```yaml\n" +
+		"test: 'hello'\n" +
+		"\n```\n\n" +
+		"---\n\n" +
+		"## Instructions for AI Assistant\n\n" +
+		"Please process the above YAML prompt and:\n\n" +
+		"1. Perform a deep scan of the codebase\n" +
+		"2. Design reference materials strategy\n" +
+		"3. Design Phase 2 tools\n" +
+		"4. Implement Phase 2 tools in Go\n" +
+		"5. Generate initial reference materials\n" +
+		"6. Validate security compliance\n\n" +
+		"All outputs must go to:\n" +
+		"- /tmp/out\n\n"
+	if got != want {
+		t.Errorf("generic output changed:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	rendered, err := renderTemplateFor(genericProvider{}, template, vars)
+	if err != nil {
+		t.Fatalf("renderTemplateFor() error = %v", err)
+	}
+	if rendered != got {
+		t.Error("renderTemplateFor() should match the generic provider's Format")
+	}
+}
+
+func TestClaudeProviderWrapsContext(t *testing.T) {
+	got, err := claudeProvider{}.Format("key: value\n", "/tmp/out")
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	if !strings.Contains(got, "<context>\nkey: value\n</context>") {
+		t.Errorf("expected YAML wrapped in <context> tags, got:\n%s", got)
+	}
+	if !strings.Contains(got, "<instructions>") || !strings.Contains(got, "</instructions>") {
+		t.Errorf("expected <instructions> section, got:\n%s", got)
+	}
+	if !strings.Contains(got, "/tmp/out") {
+		t.Errorf("expected output directory, got:\n%s", got)
+	}
+}
+
+func TestOpenAIProviderEmitsChatMessages(t *testing.T) {
+	got, err := openAIProvider{}.Format("key: value\n", "/tmp/out")
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var payload struct {
+		Messages []chatMessage `json:"messages"`
+	}
+	if err := json.Unmarshal([]byte(got), &payload); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, got)
+	}
+	if len(payload.Messages) != 2 {
+		t.Fatalf("got %d messages, want 2", len(payload.Messages))
+	}
+	if payload.Messages[0].Role != "system" || !strings.Contains(payload.Messages[0].Content, "/tmp/out") {
+		t.Errorf("unexpected system message: %+v", payload.Messages[0])
+	}
+	if payload.Messages[1].Role != "user" || payload.Messages[1].Content != "key: value\n" {
+		t.Errorf("unexpected user message: %+v", payload.Messages[1])
+	}
+}
+
+func TestProvidersNameOutputDirInSecurityNotice(t *testing.T) {
+	template := map[string]interface{}{"test": "{{VALUE}}"}
+	outputDir := `C:\Users\dev\AppData\Local\Temp\codebase-reviewer\app-1a2b3c4d`
+	vars := map[string]string{"VALUE": "hello", "OUTPUT_DIR": outputDir}
+
+	for _, p := range []Provider{genericProvider{}, claudeProvider{}, openAIProvider{}} {
+		t.Run(p.Name(), func(t *testing.T) {
+			got, err := renderTemplateFor(p, template, vars)
+			if err != nil {
+				t.Fatalf("renderTemplateFor() error = %v", err)
+			}
+			if strings.Contains(got, "/tmp") {
+				t.Errorf("security notice should not name /tmp:\n%s", got)
+			}
+			notice := "written to " + outputDir + " or .gitignore'd locations"
+			if p.Name() == ProviderOpenAI {
+				notice = strings.ReplaceAll(notice, `\`, `\\`)
+			}
+			if !strings.Contains(got, notice) {
+				t.Errorf("security notice should name %s:\n%s", outputDir, got)
+			}
+		})
+	}
+}
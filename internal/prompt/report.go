@@ -0,0 +1,38 @@
+package prompt
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// ReportJSON selects the scan-report.json machine-readable report.
+const ReportJSON = "json"
+
+// ParseReport validates a --report value. Empty means no report.
+func ParseReport(name string) (string, error) {
+	switch name {
+	case "", ReportJSON:
+		return name, nil
+	default:
+		return "", fmt.Errorf("unknown report format %q (supported: %s)", name, ReportJSON)
+	}
+}
+
+// writeScanReport writes report as JSON to outputDir.
+func writeScanReport(report *scanner.ScanReport, outputDir string, dryRun bool, log *logger.Logger) error {
+	data, err := scanner.MarshalScanReport(report)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(outputDir, scanner.ScanReportFileName)
+	if err := writeOutput(path, data, dryRun, log); err != nil {
+		return fmt.Errorf("failed to write scan report: %w", err)
+	}
+	if !dryRun {
+		log.Info("Scan report written: %s", path)
+	}
+	return nil
+}
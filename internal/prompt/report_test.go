@@ -0,0 +1,79 @@
+package prompt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestParseReport(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"json", ReportJSON, false},
+		{"xml", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseReport(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseReport(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseReport(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_WritesScanReport(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repos := []scanner.Repository{{Path: repoDir, Name: "app", RelativePath: "."}}
+	outputDir := t.TempDir()
+
+	if _, err := Generate(context.Background(), repoDir, repos, outputDir, Options{Report: ReportJSON}, logger.New(false)); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	report, err := scanner.LoadScanReport(filepath.Join(outputDir, scanner.ScanReportFileName))
+	if err != nil {
+		t.Fatalf("LoadScanReport() error = %v", err)
+	}
+	if report.Fingerprint == "" {
+		t.Error("report should include the codebase fingerprint")
+	}
+	if report.TotalFiles != 1 {
+		t.Errorf("TotalFiles = %d, want 1", report.TotalFiles)
+	}
+	if got := report.Languages["Go"]; got.Files != 1 || got.Lines != 3 {
+		t.Errorf("Languages[Go] = %+v, want 1 file, 3 lines", got)
+	}
+}
+
+func TestGenerate_NoScanReportByDefault(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	repoDir := t.TempDir()
+	repos := []scanner.Repository{{Path: repoDir, Name: "app", RelativePath: "."}}
+	outputDir := t.TempDir()
+
+	if _, err := Generate(context.Background(), repoDir, repos, outputDir, Options{}, logger.New(false)); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, scanner.ScanReportFileName)); !os.IsNotExist(err) {
+		t.Errorf("scan report written without --report (stat err = %v)", err)
+	}
+}
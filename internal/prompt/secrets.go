@@ -0,0 +1,18 @@
+package prompt
+
+import (
+	"fmt"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+)
+
+// secretsDetail renders the "- Potential Secrets:" warning line, counting
+// the findings by kind, or "" when there are none. It never names the
+// files or the matches, so the prompt does not point at live credentials.
+func secretsDetail(findings []scanner.SecretFinding) string {
+	if len(findings) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("- Potential Secrets: %d (%s); values withheld, rotate any that are real\n",
+		len(findings), countsSummary(scanner.CountSecretsByKind(findings)))
+}
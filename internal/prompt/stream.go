@@ -0,0 +1,261 @@
+package prompt
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// marshalTemplate converts a parsed template back to YAML, placeholders
+// intact.
+func marshalTemplate(templateData map[string]interface{}) (string, error) {
+	yamlBytes, err := yaml.Marshal(templateData)
+	if err != nil {
+		return "", err
+	}
+	return string(yamlBytes), nil
+}
+
+// varReplacer returns a Replacer that substitutes every {{KEY}} in vars in
+// a single pass. Placeholders inside substituted values are left as they
+// are.
+func varReplacer(vars map[string]string) *strings.Replacer {
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, "{{"+key+"}}", vars[key])
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// promptRenderer returns a function that writes the prompt for
+// templateYAML and vars, formatted by p, to a writer. Providers that
+// implement streamingProvider receive the substituted YAML as it is
+// produced; others format a fully substituted copy.
+func promptRenderer(p Provider, templateYAML string, vars map[string]string) func(io.Writer) error {
+	replacer := varReplacer(vars)
+	outputDir := vars["OUTPUT_DIR"]
+	writeYAML := func(w io.Writer) error {
+		return substitute(w, templateYAML, replacer)
+	}
+	if sp, ok := p.(streamingProvider); ok {
+		return func(w io.Writer) error { return sp.formatTo(w, writeYAML, outputDir) }
+	}
+	return func(w io.Writer) error {
+		var yamlStr strings.Builder
+		if err := writeYAML(&yamlStr); err != nil {
+			return err
+		}
+		out, err := p.Format(yamlStr.String(), outputDir)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, out)
+		return err
+	}
+}
+
+// substitute writes templateYAML to w with replacer applied line by line.
+// Every line of a multi-line value after the first is indented like the
+// line holding its placeholder, so a value such as NESTED_REPOS_DETAIL
+// stays inside the YAML block scalar it is substituted into.
+func substitute(w io.Writer, templateYAML string, replacer *strings.Replacer) error {
+	for _, line := range strings.SplitAfter(templateYAML, "\n") {
+		body := strings.TrimSuffix(line, "\n")
+		iw := &indentWriter{w: w, indent: body[:len(body)-len(strings.TrimLeft(body, " "))]}
+		if _, err := replacer.WriteString(iw, body); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, line[len(body):]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indentWriter passes writes through to w, writing indent before each
+// line that follows a newline. Empty lines are left unindented.
+type indentWriter struct {
+	w       io.Writer
+	indent  string
+	pending bool
+}
+
+func (iw *indentWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if iw.pending && p[0] != '\n' {
+			if _, err := io.WriteString(iw.w, iw.indent); err != nil {
+				return written, err
+			}
+			iw.pending = false
+		}
+		end := bytes.IndexByte(p, '\n') + 1
+		if end == 0 {
+			end = len(p)
+		}
+		n, err := iw.w.Write(p[:end])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		iw.pending = p[end-1] == '\n'
+		p = p[end:]
+	}
+	return written, nil
+}
+
+// unresolvedPlaceholders returns the distinct {{...}} tokens that rendering
+// templateYAML with vars would leave in the prompt, sorted, without
+// rendering it: placeholders with no variable, and any that appear inside
+// a variable's value.
+func unresolvedPlaceholders(templateYAML string, vars map[string]string) []string {
+	seen := make(map[string]bool)
+	for _, token := range findUnresolvedPlaceholders(templateYAML) {
+		if _, ok := vars[strings.TrimSuffix(strings.TrimPrefix(token, "{{"), "}}")]; !ok {
+			seen[token] = true
+		}
+	}
+	for _, value := range vars {
+		for _, token := range findUnresolvedPlaceholders(value) {
+			seen[token] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+	unique := make([]string, 0, len(seen))
+	for token := range seen {
+		unique = append(unique, token)
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// runeCounter passes writes through to w while counting the bytes and
+// UTF-8 characters written, as utf8.RuneCount would over the whole
+// output, even when a character is split across writes.
+type runeCounter struct {
+	w       io.Writer
+	bytes   int
+	runes   int
+	pending []byte
+}
+
+func (c *runeCounter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.bytes += n
+	c.count(p[:n])
+	return n, err
+}
+
+// count adds the characters in p, holding back a trailing incomplete
+// sequence until the next write completes it.
+func (c *runeCounter) count(p []byte) {
+	for len(c.pending) > 0 && len(p) > 0 && !utf8.FullRune(c.pending) {
+		c.pending = append(c.pending, p[0])
+		p = p[1:]
+	}
+	if len(c.pending) > 0 {
+		if !utf8.FullRune(c.pending) {
+			return
+		}
+		c.runes += utf8.RuneCount(c.pending)
+		c.pending = c.pending[:0]
+	}
+
+	cut := len(p)
+	for i := len(p) - 1; i >= 0 && i > len(p)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(p[i]) {
+			if !utf8.FullRune(p[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	c.runes += utf8.RuneCount(p[:cut])
+	c.pending = append(c.pending, p[cut:]...)
+}
+
+// total returns the characters counted, including any incomplete
+// sequence at the end of the output.
+func (c *runeCounter) total() int {
+	return c.runes + utf8.RuneCount(c.pending)
+}
+
+// writePrompt streams the prompt produced by render to path through a
+// buffered writer and returns its estimated token count. The prompt is
+// written to a temporary file in the same directory and renamed over path
+// only once complete, so a failed render leaves any previous prompt in
+// place. In dry-run mode the prompt is only measured and the planned write
+// is logged.
+func writePrompt(path string, render func(io.Writer) error, dryRun bool, log *logger.Logger) (int, error) {
+	if dryRun {
+		counter := &runeCounter{w: io.Discard}
+		if err := render(counter); err != nil {
+			return 0, err
+		}
+		log.Info("Dry run: would write %s (%d bytes)", path, counter.bytes)
+		return tokensForChars(counter.total()), nil
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	tokens, err := writePromptFile(f, render)
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return 0, err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return 0, err
+	}
+	return tokens, nil
+}
+
+// writePromptFile renders the prompt into f, with the permissions of the
+// other outputs, and returns its estimated token count.
+func writePromptFile(f *os.File, render func(io.Writer) error) (int, error) {
+	if err := f.Chmod(0644); err != nil {
+		return 0, err
+	}
+	bw := bufio.NewWriter(f)
+	counter := &runeCounter{w: bw}
+	if err := render(counter); err != nil {
+		return 0, err
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", f.Name(), err)
+	}
+	return tokensForChars(counter.total()), nil
+}
+
+// measurePrompt returns the estimated token count of the prompt produced
+// by render without keeping it.
+func measurePrompt(render func(io.Writer) error) (int, error) {
+	counter := &runeCounter{w: io.Discard}
+	if err := render(counter); err != nil {
+		return 0, err
+	}
+	return tokensForChars(counter.total()), nil
+}
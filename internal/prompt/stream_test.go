@@ -0,0 +1,193 @@
+package prompt
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// streamTemplate and streamVars are a fixed input whose rendering by the
+// previous, fully buffered renderTemplateFor is recorded in
+// bufferedPrompts.
+var (
+	streamTemplate = map[string]interface{}{
+		"prompt": map[string]interface{}{
+			"context": "Analyze {{CODEBASE_NAME}} — “quoted” ünïcode at {{TARGET_PATH}}",
+			"repos":   "{{NESTED_REPOS}}",
+		},
+	}
+	streamVars = map[string]string{
+		"CODEBASE_NAME": "app",
+		"TARGET_PATH":   "/src/app",
+		"NESTED_REPOS":  `[{"Name":"日本"}]`,
+		"OUTPUT_DIR":    "/tmp/out",
+	}
+	bufferedPrompts = map[string]string{
+		ProviderGeneric: "# Phase 1 LLM Prompt - Codebase Analysis\n\n**SECURITY NOTICE:** This prompt contains references to proprietary code.\nAll outputs must be written to /tmp/out or .gitignore'd locations.\n\n---\n\n```yaml\nprompt:\n    context: Analyze app — “quoted” ünïcode at /src/app\n    repos: '[{\"Name\":\"日本\"}]'\n\n```\n\n---\n\n## Instructions for AI Assistant\n\nPlease process the above YAML prompt and:\n\n1. Perform a deep scan of the codebase\n2. Design reference materials strategy\n3. Design Phase 2 tools\n4. Implement Phase 2 tools in Go\n5. Generate initial reference materials\n6. Validate security compliance\n\nAll outputs must go to:\n- /tmp/out\n\n",
+		ProviderClaude:  "<security_notice>\nThis prompt contains references to proprietary code.\nAll outputs must be written to /tmp/out or .gitignore'd locations.\n</security_notice>\n\n<context>\nprompt:\n    context: Analyze app — “quoted” ünïcode at /src/app\n    repos: '[{\"Name\":\"日本\"}]'\n</context>\n\n<instructions>\nProcess the YAML prompt in the <context> tags and:\n\n1. Perform a deep scan of the codebase\n2. Design reference materials strategy\n3. Design Phase 2 tools\n4. Implement Phase 2 tools in Go\n5. Generate initial reference materials\n6. Validate security compliance\n\nAll outputs must go to: /tmp/out\n</instructions>\n",
+		ProviderOpenAI:  "{\n  \"messages\": [\n    {\n      \"role\": \"system\",\n      \"content\": \"You are an expert software architect and code analyst. The prompt references proprietary code; all outputs must be written to /tmp/out or .gitignore'd locations.\\n\\nProcess the user's YAML prompt and:\\n\\n1. Perform a deep scan of the codebase\\n2. Design reference materials strategy\\n3. Design Phase 2 tools\\n4. Implement Phase 2 tools in Go\\n5. Generate initial reference materials\\n6. Validate security compliance\\n\\nAll outputs must go to: /tmp/out\\n\"\n    },\n    {\n      \"role\": \"user\",\n      \"content\": \"prompt:\\n    context: Analyze app — “quoted” ünïcode at /src/app\\n    repos: '[{\\\"Name\\\":\\\"日本\\\"}]'\\n\"\n    }\n  ]\n}\n",
+	}
+)
+
+func TestWritePrompt_MatchesBufferedOutput(t *testing.T) {
+	templateYAML, err := marshalTemplate(streamTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range bufferedPrompts {
+		t.Run(name, func(t *testing.T) {
+			p, err := ParseProvider(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), p.FileName())
+			tokens, err := writePrompt(path, promptRenderer(p, templateYAML, streamVars), false, logger.New(false))
+			if err != nil {
+				t.Fatalf("writePrompt() error = %v", err)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != want {
+				t.Errorf("streamed prompt differs from buffered output:\ngot  %q\nwant %q", got, want)
+			}
+			if wantTokens := EstimateTokens(want); tokens != wantTokens {
+				t.Errorf("writePrompt() tokens = %d, want %d", tokens, wantTokens)
+			}
+
+			rendered, err := renderTemplateFor(p, streamTemplate, streamVars)
+			if err != nil || rendered != want {
+				t.Errorf("renderTemplateFor() = %q, %v; want the streamed output", rendered, err)
+			}
+		})
+	}
+}
+
+func TestWritePrompt_DryRun(t *testing.T) {
+	templateYAML, err := marshalTemplate(streamTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "prompt.md")
+	tokens, err := writePrompt(path, promptRenderer(genericProvider{}, templateYAML, streamVars), true, logger.New(false))
+	if err != nil {
+		t.Fatalf("writePrompt() error = %v", err)
+	}
+	if want := EstimateTokens(bufferedPrompts[ProviderGeneric]); tokens != want {
+		t.Errorf("writePrompt() tokens = %d, want %d", tokens, want)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("dry run wrote the prompt")
+	}
+}
+
+func TestWritePrompt_FailedRenderKeepsPreviousPrompt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "prompt.md")
+	if err := os.WriteFile(path, []byte("last good prompt"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	renderErr := errors.New("render failed")
+	render := func(w io.Writer) error {
+		io.WriteString(w, "partial")
+		return renderErr
+	}
+
+	if _, err := writePrompt(path, render, false, logger.New(false)); !errors.Is(err, renderErr) {
+		t.Fatalf("writePrompt() error = %v, want %v", err, renderErr)
+	}
+	if got, err := os.ReadFile(path); err != nil || string(got) != "last good prompt" {
+		t.Errorf("prompt after failed render = %q, %v; want the previous prompt", got, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("output dir holds %d entries after a failed render, want only the prompt", len(entries))
+	}
+}
+
+func TestWritePrompt_Permissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompt.md")
+	render := func(w io.Writer) error {
+		_, err := io.WriteString(w, "prompt")
+		return err
+	}
+	if _, err := writePrompt(path, render, false, logger.New(false)); err != nil {
+		t.Fatalf("writePrompt() error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0644 {
+		t.Errorf("prompt mode = %v, want 0644", info.Mode().Perm())
+	}
+}
+
+func TestRuneCounter_SplitCharacters(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"ascii", "plain text"},
+		{"multi-byte", "ünïcode — 日本 🚀"},
+		{"invalid bytes", "ok\xff\xfe\xe2\x28 done"},
+		{"truncated at end", "end \xe6\x97"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			c := &runeCounter{w: &b}
+			for i := 0; i < len(tt.text); i++ {
+				c.Write([]byte{tt.text[i]})
+			}
+			if want := utf8.RuneCountInString(tt.text); c.total() != want {
+				t.Errorf("total() = %d, want %d", c.total(), want)
+			}
+			if c.bytes != len(tt.text) || b.String() != tt.text {
+				t.Errorf("passed through %q (%d bytes), want %q", b.String(), c.bytes, tt.text)
+			}
+		})
+	}
+}
+
+func TestUnresolvedPlaceholders(t *testing.T) {
+	templateYAML := "a: {{KNOWN}}\nb: {{MISSING}}\nc: {{MISSING}} {{OTHER}}\n"
+	vars := map[string]string{"KNOWN": "value with {{INNER}}"}
+	want := []string{"{{INNER}}", "{{MISSING}}", "{{OTHER}}"}
+	if got := unresolvedPlaceholders(templateYAML, vars); !reflect.DeepEqual(got, want) {
+		t.Errorf("unresolvedPlaceholders() = %v, want %v", got, want)
+	}
+	if got := unresolvedPlaceholders("a: {{KNOWN}}\n", map[string]string{"KNOWN": "x"}); got != nil {
+		t.Errorf("unresolvedPlaceholders() = %v, want nil", got)
+	}
+}
+
+func TestSubstitute_IndentsMultilineValues(t *testing.T) {
+	templateYAML := "a:\n    detail: |\n        {{DETAIL}}\n    name: {{NAME}}\n"
+	vars := map[string]string{"DETAIL": "first\n\nsecond\n- third\n", "NAME": "app"}
+
+	var b strings.Builder
+	if err := substitute(&b, templateYAML, varReplacer(vars)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "a:\n    detail: |\n        first\n\n        second\n        - third\n\n    name: app\n"
+	if b.String() != want {
+		t.Errorf("substitute() = %q, want %q", b.String(), want)
+	}
+}
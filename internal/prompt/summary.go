@@ -0,0 +1,24 @@
+package prompt
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+)
+
+// WriteSummaryTable writes one aligned row per analysis to w: repository
+// name, primary language, total files, and test-to-code ratio.
+func WriteSummaryTable(w io.Writer, analyses []*scanner.RepositoryAnalysis) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REPOSITORY\tLANGUAGE\tFILES\tTEST RATIO")
+	for _, a := range analyses {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%.2f\n",
+			a.Repository.Name, valueOr(a.PrimaryLanguage(), "-"), a.TotalFiles, a.TestToCodeRatio())
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package prompt
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestWriteSummaryTable(t *testing.T) {
+	analyses := []*scanner.RepositoryAnalysis{
+		{
+			Repository: scanner.Repository{Name: "api"},
+			Languages:  map[string]int{"Go": 12},
+			TotalFiles: 12,
+			TestFiles:  4,
+			CodeFiles:  8,
+		},
+		{Repository: scanner.Repository{Name: "empty-docs-repo"}, TotalFiles: 1},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSummaryTable(&buf, analyses); err != nil {
+		t.Fatalf("WriteSummaryTable() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want header + 2 rows:\n%s", len(lines), buf.String())
+	}
+	if fields := strings.Fields(lines[0]); strings.Join(fields, " ") != "REPOSITORY LANGUAGE FILES TEST RATIO" {
+		t.Errorf("header = %q", lines[0])
+	}
+	if fields := strings.Fields(lines[1]); strings.Join(fields, " ") != "api Go 12 0.50" {
+		t.Errorf("api row = %q", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "empty-docs-repo - 1 0.00" {
+		t.Errorf("empty-docs-repo row = %q", lines[2])
+	}
+	// Columns are aligned: every row's language starts where the header's does.
+	col := strings.Index(lines[0], "LANGUAGE")
+	if strings.Index(lines[1], "Go") != col || strings.Index(lines[2], " - ")+1 != col {
+		t.Errorf("columns not aligned:\n%s", buf.String())
+	}
+}
+
+func TestGenerate_Summary(t *testing.T) {
+	chdir(t, t.TempDir())
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repos := []scanner.Repository{{Path: repoDir, Name: "app"}}
+
+	var buf bytes.Buffer
+	if _, err := Generate(context.Background(), repoDir, repos, t.TempDir(), Options{Summary: &buf}, logger.New(false)); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "REPOSITORY") || !strings.Contains(buf.String(), "app") {
+		t.Errorf("summary not written:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,102 @@
+package prompt
+
+import "fmt"
+
+// ValidateTemplate checks that a parsed prompt template has the sections
+// Generate and RenderMarkdown rely on: a metadata map with a version, and a
+// prompt map holding a non-empty tasks list (each task a map with a task_id
+// and name) and an output_requirements map with a primary_output. It
+// returns one error per problem, or nil when the template is valid.
+func ValidateTemplate(data map[string]interface{}) []error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if metadata, ok := requireMap(data, "metadata", "metadata", fail); ok {
+		requireString(metadata, "version", "metadata.version", fail)
+	}
+
+	prompt, ok := requireMap(data, "prompt", "prompt", fail)
+	if !ok {
+		return errs
+	}
+
+	switch tasks := prompt["tasks"].(type) {
+	case nil:
+		fail("prompt.tasks: missing")
+	case []interface{}:
+		if len(tasks) == 0 {
+			fail("prompt.tasks: must list at least one task")
+		}
+		for i, item := range tasks {
+			path := fmt.Sprintf("prompt.tasks[%d]", i)
+			task, isMap := item.(map[string]interface{})
+			if !isMap {
+				fail("%s: must be a map, got %s", path, typeName(item))
+				continue
+			}
+			requireString(task, "task_id", path+".task_id", fail)
+			requireString(task, "name", path+".name", fail)
+		}
+	default:
+		fail("prompt.tasks: must be a list, got %s", typeName(tasks))
+	}
+
+	if output, ok := requireMap(prompt, "output_requirements", "prompt.output_requirements", fail); ok {
+		requireString(output, "primary_output", "prompt.output_requirements.primary_output", fail)
+	}
+
+	return errs
+}
+
+// requireMap returns parent[key] if it is a map, otherwise reporting it as
+// missing or mistyped under path.
+func requireMap(parent map[string]interface{}, key, path string, fail func(string, ...interface{})) (map[string]interface{}, bool) {
+	value, present := parent[key]
+	if !present || value == nil {
+		fail("%s: missing", path)
+		return nil, false
+	}
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		fail("%s: must be a map, got %s", path, typeName(value))
+		return nil, false
+	}
+	return m, true
+}
+
+// requireString reports parent[key] under path unless it is a non-empty
+// string.
+func requireString(parent map[string]interface{}, key, path string, fail func(string, ...interface{})) {
+	value, present := parent[key]
+	if !present || value == nil {
+		fail("%s: missing", path)
+		return
+	}
+	s, ok := value.(string)
+	if !ok {
+		fail("%s: must be a string, got %s", path, typeName(value))
+		return
+	}
+	if s == "" {
+		fail("%s: must not be empty", path)
+	}
+}
+
+// typeName describes a decoded YAML value's type for error messages.
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "map"
+	case []interface{}:
+		return "list"
+	case string:
+		return "string"
+	case int, int64, float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", v)
+}
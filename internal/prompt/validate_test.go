@@ -0,0 +1,86 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/prompts"
+	"gopkg.in/yaml.v3"
+)
+
+func validTemplate() map[string]interface{} {
+	return map[string]interface{}{
+		"metadata": map[string]interface{}{"version": "2.0"},
+		"prompt": map[string]interface{}{
+			"tasks": []interface{}{
+				map[string]interface{}{"task_id": "T1", "name": "Analyze"},
+			},
+			"output_requirements": map[string]interface{}{"primary_output": "analysis.md"},
+		},
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(map[string]interface{})
+		want   []string
+	}{
+		{"valid", func(map[string]interface{}) {}, nil},
+		{
+			name:   "missing tasks",
+			mutate: func(d map[string]interface{}) { delete(d["prompt"].(map[string]interface{}), "tasks") },
+			want:   []string{"prompt.tasks: missing"},
+		},
+		{
+			name:   "empty tasks",
+			mutate: func(d map[string]interface{}) { d["prompt"].(map[string]interface{})["tasks"] = []interface{}{} },
+			want:   []string{"prompt.tasks: must list at least one task"},
+		},
+		{
+			name: "task without id",
+			mutate: func(d map[string]interface{}) {
+				d["prompt"].(map[string]interface{})["tasks"] = []interface{}{map[string]interface{}{"name": "Analyze"}, "oops"}
+			},
+			want: []string{"prompt.tasks[0].task_id: missing", "prompt.tasks[1]: must be a map, got string"},
+		},
+		{
+			name: "missing metadata and output requirements",
+			mutate: func(d map[string]interface{}) {
+				delete(d, "metadata")
+				delete(d["prompt"].(map[string]interface{}), "output_requirements")
+			},
+			want: []string{"metadata: missing", "prompt.output_requirements: missing"},
+		},
+		{
+			name:   "prompt of wrong type",
+			mutate: func(d map[string]interface{}) { d["prompt"] = []interface{}{} },
+			want:   []string{"prompt: must be a map, got list"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := validTemplate()
+			tt.mutate(data)
+
+			var got []string
+			for _, err := range ValidateTemplate(data) {
+				got = append(got, err.Error())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("ValidateTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTemplate_EmbeddedDefault(t *testing.T) {
+	var data map[string]interface{}
+	if err := yaml.Unmarshal(prompts.Phase1Template, &data); err != nil {
+		t.Fatal(err)
+	}
+	if errs := ValidateTemplate(data); len(errs) != 0 {
+		t.Errorf("embedded template should be valid, got %v", errs)
+	}
+}
@@ -0,0 +1,354 @@
+package prompt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+)
+
+// layoutDescriptions explains each scanner.Layout in the prompt.
+var layoutDescriptions = map[scanner.Layout]string{
+	scanner.SingleRepo: "a single repository",
+	scanner.Monorepo:   "a monorepo: the root repository contains nested repositories or submodules",
+	scanner.MultiRepo:  "a multi-repo workspace: independent repositories under a common directory",
+}
+
+func buildTemplateVars(targetPath string, repos []scanner.Repository, analyses []*scanner.RepositoryAnalysis, outputDir string, verbose, scorch bool) map[string]string {
+	codebaseName := filepath.Base(targetPath)
+	now := time.Now()
+
+	summary := scanner.Aggregate(analyses)
+
+	// Build nested repos detail, opening with the codebase-wide totals
+	var reposDetail strings.Builder
+	reposDetail.WriteString(summaryDetail(summary))
+	for i, analysis := range analyses {
+		reposDetail.WriteString(repoDetail(i+1, analysis, now))
+	}
+
+	// Build repos JSON, leaving < and > unescaped so anonymization
+	// tokens such as <repo1> stay readable.
+	var reposJSON bytes.Buffer
+	enc := json.NewEncoder(&reposJSON)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(nestedRepos(repos, analyses))
+
+	scanMode := "deep_scan"
+	if scorch {
+		scanMode = "scorch"
+	}
+
+	layout := scanner.ClassifyLayout(repos, targetPath)
+
+	return map[string]string{
+		"TARGET_PATH":         targetPath,
+		"CODEBASE_NAME":       codebaseName,
+		"CODEBASE_LAYOUT":     fmt.Sprintf("%s (%s)", layout, layoutDescriptions[layout]),
+		"SCAN_MODE":           scanMode,
+		"VERBOSE":             fmt.Sprintf("%v", verbose),
+		"CODEBASE_SUMMARY":    summaryLine(summary),
+		"NESTED_REPOS":        strings.TrimSuffix(reposJSON.String(), "\n"),
+		"NESTED_REPOS_DETAIL": reposDetail.String(),
+		"OUTPUT_DIR":          outputDir,
+		"FRAMEWORKS":          frameworksSentence(analyses),
+		"SERVICES":            servicesSentence(analyses),
+		"SKIPPED_REPOS":       skippedSentence(repos, analyses),
+		"RISK_AREAS":          riskAreasLine(analyses),
+		"BUILD_COMMANDS":      buildCommandsLine(analyses),
+	}
+}
+
+// frameworksSentence renders the FRAMEWORKS variable: a sentence listing
+// the frameworks detected across all repositories, with a leading space so
+// the template can append it to a preceding sentence, or "" when none were
+// found.
+func frameworksSentence(analyses []*scanner.RepositoryAnalysis) string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, analysis := range analyses {
+		for _, name := range analysis.Frameworks {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return fmt.Sprintf(" Detected frameworks: %s.", strings.Join(names, ", "))
+}
+
+// skippedSentence renders the SKIPPED_REPOS variable like
+// frameworksSentence: a note naming the repositories that have no
+// analysis because it failed, or "" when every repository was analyzed.
+func skippedSentence(repos []scanner.Repository, analyses []*scanner.RepositoryAnalysis) string {
+	analyzed := make(map[string]bool, len(analyses))
+	for _, a := range analyses {
+		analyzed[a.Repository.Path] = true
+	}
+	var names []string
+	for _, repo := range repos {
+		if !analyzed[repo.Path] {
+			names = append(names, repo.Name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" Note: %d of %d repositories (%s) could not be analyzed and are missing from the analysis data.",
+		len(names), len(repos), strings.Join(names, ", "))
+}
+
+// servicesSentence renders the SERVICES variable like frameworksSentence,
+// listing each service with its kind and path. Service names are prefixed
+// with their repository when more than one repository was analyzed.
+func servicesSentence(analyses []*scanner.RepositoryAnalysis) string {
+	var parts []string
+	for _, analysis := range analyses {
+		for _, svc := range analysis.Services {
+			name := svc.Name
+			if len(analyses) > 1 {
+				name = analysis.Repository.Name + "/" + name
+			}
+			where := svc.Kind
+			if svc.Path != "" {
+				where += " at " + svc.Path
+			}
+			parts = append(parts, fmt.Sprintf("%s (%s)", name, where))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" Detected services: %s.", strings.Join(parts, ", "))
+}
+
+// summaryDetail renders the codebase-wide totals that open
+// NESTED_REPOS_DETAIL, ahead of the per-repository sections.
+func summaryDetail(s scanner.CodebaseSummary) string {
+	var b strings.Builder
+	b.WriteString("\n### Codebase Summary\n")
+	b.WriteString(fmt.Sprintf("- Repositories: %d\n", s.Repositories))
+	b.WriteString(fmt.Sprintf("- Total Files: %d\n", s.TotalFiles))
+	b.WriteString(fmt.Sprintf("- Total Lines: %d\n", s.TotalLines))
+	b.WriteString(fmt.Sprintf("- Primary Language: %s\n", valueOr(s.PrimaryLanguage, "none")))
+	b.WriteString(fmt.Sprintf("- Tests: %d test files, %d code files (test-to-code ratio %.2f)\n",
+		s.TestFiles, s.CodeFiles, s.TestToCodeRatio()))
+	b.WriteString(fmt.Sprintf("- Languages: %s\n", countsSummary(s.Languages)))
+	if s.LargeFiles > 0 {
+		b.WriteString(fmt.Sprintf("- Large File Warnings: %d\n", s.LargeFiles))
+	}
+	if s.Secrets > 0 {
+		b.WriteString(fmt.Sprintf("- Potential Secrets: %d\n", s.Secrets))
+	}
+	return b.String()
+}
+
+// summaryLine renders the CODEBASE_SUMMARY variable, a one-line form of
+// summaryDetail that fits a quoted YAML value.
+func summaryLine(s scanner.CodebaseSummary) string {
+	line := fmt.Sprintf("%d repositories, %d files, %d lines; primary language %s; %d test files",
+		s.Repositories, s.TotalFiles, s.TotalLines, valueOr(s.PrimaryLanguage, "none"), s.TestFiles)
+	if s.Secrets > 0 {
+		line += fmt.Sprintf("; %d potential secret(s)", s.Secrets)
+	}
+	return line
+}
+
+// maxRiskAreas caps how many risk areas RISK_AREAS lists.
+const maxRiskAreas = 10
+
+// riskAreasLine renders the RISK_AREAS variable: the most severe risk
+// areas across all repositories on one line, such as
+// "[high] api: low test ratio (0 test files for 12 code files); ...".
+func riskAreasLine(analyses []*scanner.RepositoryAnalysis) string {
+	risks := scanner.RankRisks(analyses)
+	if len(risks) == 0 {
+		return "none detected"
+	}
+	var more string
+	if len(risks) > maxRiskAreas {
+		more = fmt.Sprintf(" (+%d lower-ranked)", len(risks)-maxRiskAreas)
+		risks = risks[:maxRiskAreas]
+	}
+	parts := make([]string, len(risks))
+	for i, r := range risks {
+		parts[i] = fmt.Sprintf("[%s] %s: %s (%s)", r.Severity, r.Repository, r.Signal, r.Detail)
+	}
+	return strings.Join(parts, "; ") + more
+}
+
+// nestedRepo is a NESTED_REPOS entry: the repository's discovery metadata,
+// under the same keys as before, plus a summary of its analysis.
+type nestedRepo struct {
+	scanner.Repository
+	PrimaryLanguage string         `json:",omitempty"`
+	TotalFiles      int            `json:",omitempty"`
+	Languages       map[string]int `json:",omitempty"`
+}
+
+// nestedRepos pairs each repository with the analysis of the same path.
+// Repositories without an analysis carry only their metadata.
+func nestedRepos(repos []scanner.Repository, analyses []*scanner.RepositoryAnalysis) []nestedRepo {
+	byPath := make(map[string]*scanner.RepositoryAnalysis, len(analyses))
+	for _, a := range analyses {
+		byPath[a.Repository.Path] = a
+	}
+	nested := make([]nestedRepo, len(repos))
+	for i, repo := range repos {
+		nested[i] = nestedRepo{Repository: repo}
+		if a, ok := byPath[repo.Path]; ok {
+			nested[i].PrimaryLanguage = a.PrimaryLanguage()
+			nested[i].TotalFiles = a.TotalFiles
+			nested[i].Languages = a.Languages
+		}
+	}
+	return nested
+}
+
+// repoDetail renders the NESTED_REPOS_DETAIL section for the n-th analysis.
+func repoDetail(n int, analysis *scanner.RepositoryAnalysis, now time.Time) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("\n### Repository %d: %s\n", n, analysis.Repository.Name))
+	b.WriteString(fmt.Sprintf("- Path: %s\n", analysis.Repository.RelativePath))
+	b.WriteString(fmt.Sprintf("- Default Branch: %s\n", valueOr(analysis.Repository.DefaultBranch, "unknown")))
+	b.WriteString(fmt.Sprintf("- Remote: %s\n", valueOr(analysis.Repository.RemoteURL, "none")))
+	b.WriteString(lastCommitDetail(analysis.Repository, now))
+	b.WriteString(fmt.Sprintf("- License: %s\n", valueOr(analysis.Repository.License, "none found")))
+	b.WriteString(fmt.Sprintf("- Primary Language: %s\n", analysis.PrimaryLanguage()))
+	b.WriteString(fmt.Sprintf("- Total Files: %d\n", analysis.TotalFiles))
+	b.WriteString(fmt.Sprintf("- Text/Binary Files: %d text, %d binary (%.0f%% binary)\n",
+		analysis.TextFiles, analysis.BinaryFiles, analysis.BinaryRatio()*100))
+	if n := len(analysis.SkippedPaths); n > 0 {
+		b.WriteString(fmt.Sprintf("- Skipped Paths: %d unreadable (coverage incomplete; counts are partial)\n", n))
+	}
+	if analysis.GeneratedFiles > 0 {
+		b.WriteString(fmt.Sprintf("- Generated Files: %d (primary language excluding them: %s)\n",
+			analysis.GeneratedFiles, valueOr(analysis.PrimaryLanguageExcludingGenerated(), "none")))
+	}
+	b.WriteString(fmt.Sprintf("- Tests: %d test files, %d code files (test-to-code ratio %.2f)\n",
+		analysis.TestFiles, analysis.CodeFiles, analysis.TestToCodeRatio()))
+	b.WriteString(fmt.Sprintf("- TODO/FIXME Markers: %d\n", analysis.Markers))
+	b.WriteString(largeFilesDetail(analysis.LargeFiles))
+	b.WriteString(secretsDetail(analysis.Secrets))
+	if analysis.Languages["Go"] > 0 {
+		b.WriteString(fmt.Sprintf("- Go API Surface: %d exported functions, %d exported types\n",
+			analysis.ExportedFuncs, analysis.ExportedTypes))
+	}
+	if len(analysis.GoModules) > 0 {
+		b.WriteString(goModulesDetail(analysis.GoModules))
+	}
+	b.WriteString(fmt.Sprintf("- Build: %s\n", listOr(analysis.Tooling.Build, "none detected")))
+	b.WriteString(fmt.Sprintf("- CI: %s\n", listOr(analysis.Tooling.CI, "none detected")))
+	b.WriteString(fmt.Sprintf("- Containers: %s\n", listOr(analysis.Tooling.Container, "none detected")))
+	b.WriteString(docsDetail(analysis.Docs))
+	b.WriteString(fmt.Sprintf("- Language Families: %s\n", countsSummary(scanner.GroupLanguages(analysis.Languages))))
+	b.WriteString(unclassifiedDetail(analysis.UnclassifiedTypes))
+	b.WriteString("- Languages:\n")
+	for _, lang := range analysis.SortedLanguages() {
+		b.WriteString(fmt.Sprintf("  - %s: %d files (%.0f%%)\n", lang.Name, lang.Count, percentOf(lang.Count, analysis.TotalFiles)))
+	}
+	return b.String()
+}
+
+// countsSummary renders file counts, largest first, as
+// "Systems 12, Web frontend 4", or "none" when there are no counts.
+func countsSummary(counts map[string]int) string {
+	sorted := scanner.SortCounts(counts)
+	if len(sorted) == 0 {
+		return "none"
+	}
+	parts := make([]string, len(sorted))
+	for i, f := range sorted {
+		parts[i] = fmt.Sprintf("%s %d", f.Name, f.Count)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// maxListedDocs caps how many paths of each kind docsDetail lists.
+const maxListedDocs = 10
+
+// docsDetail renders the "- Existing Docs:" lines so the LLM can build on
+// documentation that already exists instead of duplicating it.
+func docsDetail(inv scanner.DocInventory) string {
+	if inv.Total() == 0 {
+		return "- Existing Docs: none found\n"
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("- Existing Docs: %d files\n", inv.Total()))
+	for _, kind := range []struct {
+		label string
+		paths []string
+	}{
+		{"READMEs", inv.READMEs},
+		{"docs/", inv.Docs},
+		{"ADRs", inv.ADRs},
+		{"CHANGELOG", inv.Changelogs},
+		{"CONTRIBUTING", inv.Contributing},
+	} {
+		if len(kind.paths) == 0 {
+			continue
+		}
+		listed := kind.paths
+		var more string
+		if len(listed) > maxListedDocs {
+			listed = listed[:maxListedDocs]
+			more = fmt.Sprintf(" (+%d more)", len(kind.paths)-maxListedDocs)
+		}
+		b.WriteString(fmt.Sprintf("  - %s (%d): %s%s\n", kind.label, len(kind.paths), strings.Join(listed, ", "), more))
+	}
+	return b.String()
+}
+
+// lastCommitDetail renders the "- Last Commit:" detail line for repo,
+// flagging repositories with no commits in over a year.
+func lastCommitDetail(repo scanner.Repository, now time.Time) string {
+	if repo.LastCommitDate.IsZero() {
+		return "- Last Commit: unknown\n"
+	}
+	line := fmt.Sprintf("- Last Commit: %s (%s)", repo.LastCommitDate.Format("2006-01-02"), shortHash(repo.LastCommitHash))
+	if repo.IsStale(now) {
+		line += " - STALE: no commits in over 12 months"
+	}
+	return line + "\n"
+}
+
+// shortHash abbreviates a commit hash to the conventional 7 characters.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// valueOr returns value, or fallback when value is empty.
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// listOr joins values with commas, or returns fallback when there are none.
+func listOr(values []string, fallback string) string {
+	if len(values) == 0 {
+		return fallback
+	}
+	return strings.Join(values, ", ")
+}
+
+// percentOf returns count as a percentage of total, or 0 when total is zero.
+func percentOf(count, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(count) * 100 / float64(total)
+}
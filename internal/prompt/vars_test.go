@@ -0,0 +1,386 @@
+package prompt
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestBuildTemplateVars_Layout(t *testing.T) {
+	root := "/work/codebase"
+	tests := []struct {
+		name  string
+		repos []scanner.Repository
+		want  string
+	}{
+		{"single", []scanner.Repository{{Path: root}}, "single-repo ("},
+		{"monorepo", []scanner.Repository{{Path: root}, {Path: root + "/lib"}}, "monorepo ("},
+		{"multi-repo", []scanner.Repository{{Path: root + "/a"}, {Path: root + "/b"}}, "multi-repo ("},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildTemplateVars(root, tt.repos, nil, "/tmp", false, false)["CODEBASE_LAYOUT"]
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("CODEBASE_LAYOUT = %q, want prefix %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_TestRatio(t *testing.T) {
+	analysis := &scanner.RepositoryAnalysis{
+		Repository: scanner.Repository{Name: "app"},
+		Languages:  map[string]int{"Go": 6},
+		TotalFiles: 6,
+		TestFiles:  2,
+		CodeFiles:  4,
+	}
+
+	prompt := renderedPrompt(t, []*scanner.RepositoryAnalysis{analysis})
+	if !strings.Contains(prompt, "- Tests: 2 test files, 4 code files (test-to-code ratio 0.50)") {
+		t.Errorf("expected test ratio in prompt, got:\n%s", prompt)
+	}
+}
+
+func TestRepoDetail_Tooling(t *testing.T) {
+	analysis := &scanner.RepositoryAnalysis{
+		Repository: scanner.Repository{Name: "app"},
+		Languages:  map[string]int{},
+		Tooling: scanner.Tooling{
+			Build: []string{"Make (Makefile)", "Go modules (go.mod)"},
+			CI:    []string{"GitHub Actions (ci.yml)"},
+		},
+	}
+
+	detail := repoDetail(1, analysis, time.Now())
+	for _, want := range []string{
+		"- Build: Make (Makefile), Go modules (go.mod)",
+		"- CI: GitHub Actions (ci.yml)",
+		"- Containers: none detected",
+	} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("detail missing %q, got:\n%s", want, detail)
+		}
+	}
+}
+
+func TestRepoDetail_License(t *testing.T) {
+	tests := []struct {
+		license string
+		want    string
+	}{
+		{"MIT", "- License: MIT"},
+		{scanner.LicenseUnknown, "- License: unknown"},
+		{"", "- License: none found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			analysis := &scanner.RepositoryAnalysis{
+				Repository: scanner.Repository{Name: "app", License: tt.license},
+				Languages:  map[string]int{},
+			}
+			if detail := repoDetail(1, analysis, time.Now()); !strings.Contains(detail, tt.want) {
+				t.Errorf("detail missing %q, got:\n%s", tt.want, detail)
+			}
+		})
+	}
+}
+
+func TestGenerate_GeneratedFiles(t *testing.T) {
+	analysis := &scanner.RepositoryAnalysis{
+		Repository:          scanner.Repository{Name: "api"},
+		Languages:           map[string]int{"Go": 10, "Python": 3},
+		GeneratedFiles:      8,
+		GeneratedByLanguage: map[string]int{"Go": 8},
+		TotalFiles:          13,
+	}
+
+	prompt := renderedPrompt(t, []*scanner.RepositoryAnalysis{analysis})
+	if !strings.Contains(prompt, "- Generated Files: 8 (primary language excluding them: Python)") {
+		t.Errorf("expected generated file line in prompt, got:\n%s", prompt)
+	}
+
+	analysis.GeneratedFiles = 0
+	if prompt := renderedPrompt(t, []*scanner.RepositoryAnalysis{analysis}); strings.Contains(prompt, "Generated Files") {
+		t.Errorf("generated file line should be omitted when there are none, got:\n%s", prompt)
+	}
+}
+
+func TestGenerate_SkippedPaths(t *testing.T) {
+	analysis := &scanner.RepositoryAnalysis{
+		Repository:   scanner.Repository{Name: "shared"},
+		Languages:    map[string]int{"Go": 1},
+		TotalFiles:   1,
+		SkippedPaths: []string{"locked", "private/keys"},
+	}
+
+	prompt := renderedPrompt(t, []*scanner.RepositoryAnalysis{analysis})
+	if !strings.Contains(prompt, "- Skipped Paths: 2 unreadable (coverage incomplete") {
+		t.Errorf("expected skipped path count in prompt, got:\n%s", prompt)
+	}
+}
+
+func TestRepoDetail_LanguagesSorted(t *testing.T) {
+	analysis := &scanner.RepositoryAnalysis{
+		Repository: scanner.Repository{Name: "app"},
+		Languages:  map[string]int{"Shell": 2, "Go": 5, "Python": 5, "C": 1, "Rust": 2, "YAML": 3},
+		TotalFiles: 18,
+	}
+	now := time.Now()
+
+	first := repoDetail(1, analysis, now)
+	for i := 0; i < 20; i++ {
+		if got := repoDetail(1, analysis, now); got != first {
+			t.Fatalf("repoDetail() differs between calls:\n%s\nvs\n%s", first, got)
+		}
+	}
+
+	want := "  - Go: 5 files (28%)\n  - Python: 5 files (28%)\n  - YAML: 3 files (17%)\n  - Rust: 2 files (11%)\n  - Shell: 2 files (11%)\n  - C: 1 files (6%)\n"
+	if !strings.HasSuffix(first, want) {
+		t.Errorf("languages not sorted by count then name:\n%s", first)
+	}
+}
+
+func TestGenerate_Docs(t *testing.T) {
+	tests := []struct {
+		name string
+		docs scanner.DocInventory
+		want []string
+	}{
+		{"none", scanner.DocInventory{}, []string{"- Existing Docs: none found\n"}},
+		{
+			"readme and adrs",
+			scanner.DocInventory{READMEs: []string{"README.md"}, ADRs: []string{"docs/adr/1.md", "docs/adr/2.md"}},
+			[]string{"- Existing Docs: 3 files\n", "  - READMEs (1): README.md\n", "  - ADRs (2): docs/adr/1.md, docs/adr/2.md\n"},
+		},
+		{
+			"long lists are capped",
+			scanner.DocInventory{Docs: []string{"d/1", "d/2", "d/3", "d/4", "d/5", "d/6", "d/7", "d/8", "d/9", "d/10", "d/11", "d/12"}},
+			[]string{"  - docs/ (12): d/1, ", "d/10 (+2 more)\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt := renderedPrompt(t, []*scanner.RepositoryAnalysis{{Docs: tt.docs}})
+			for _, want := range tt.want {
+				if !strings.Contains(prompt, want) {
+					t.Errorf("prompt missing %q:\n%s", want, prompt)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_LanguageFamilies(t *testing.T) {
+	tests := []struct {
+		name      string
+		languages map[string]int
+		want      string
+	}{
+		{"rolled up largest first", map[string]int{"JavaScript": 2, "TypeScript": 3, "CSS": 1, "Go": 4}, "- Language Families: Web frontend 6, Systems 4\n"},
+		{"no languages", nil, "- Language Families: none\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt := renderedPrompt(t, []*scanner.RepositoryAnalysis{{Languages: tt.languages}})
+			if !strings.Contains(prompt, tt.want) {
+				t.Errorf("prompt missing %q:\n%s", tt.want, prompt)
+			}
+		})
+	}
+}
+
+func TestBuildTemplateVars_FrameworksAndServices(t *testing.T) {
+	analyses := []*scanner.RepositoryAnalysis{
+		{
+			Repository: scanner.Repository{Name: "api"},
+			Frameworks: []string{"Gin", "Cobra"},
+			Services:   []scanner.Service{{Name: "server", Path: "cmd/server", Kind: scanner.ServiceGoBinary}},
+		},
+		{
+			Repository: scanner.Repository{Name: "web"},
+			Frameworks: []string{"React", "Gin"},
+		},
+	}
+
+	vars := buildTemplateVars("/work", nil, analyses, "/tmp", false, false)
+
+	if got, want := vars["FRAMEWORKS"], " Detected frameworks: Cobra, Gin, React."; got != want {
+		t.Errorf("FRAMEWORKS = %q, want %q", got, want)
+	}
+	if got, want := vars["SERVICES"], " Detected services: api/server (go-binary at cmd/server)."; got != want {
+		t.Errorf("SERVICES = %q, want %q", got, want)
+	}
+}
+
+func TestRenderTemplate_FrameworksAndServices(t *testing.T) {
+	chdir(t, t.TempDir())
+	tmpl, err := loadPromptTemplate("", logger.New(false))
+	if err != nil {
+		t.Fatalf("loadPromptTemplate() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		analysis *scanner.RepositoryAnalysis
+		want     string
+	}{
+		{
+			name: "detected",
+			analysis: &scanner.RepositoryAnalysis{
+				Frameworks: []string{"Django"},
+				Services:   []scanner.Service{{Name: "db", Kind: scanner.ServiceCompose}},
+			},
+			want: "located at /work. Detected frameworks: Django. Detected services: db (docker-compose).\n",
+		},
+		{
+			name:     "none",
+			analysis: &scanner.RepositoryAnalysis{},
+			want:     "located at /work.\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := buildTemplateVars("/work", nil, []*scanner.RepositoryAnalysis{tt.analysis}, "/tmp", false, false)
+			rendered, err := renderTemplateFor(genericProvider{}, tmpl, vars)
+			if err != nil {
+				t.Fatalf("renderTemplateFor() error = %v", err)
+			}
+			if !strings.Contains(rendered, tt.want) {
+				t.Errorf("rendered prompt missing %q", tt.want)
+			}
+			if tt.name == "none" && strings.Contains(rendered, "Detected") {
+				t.Error("rendered prompt should not mention detected frameworks or services when there are none")
+			}
+		})
+	}
+}
+
+func TestBuildTemplateVars_NestedReposIncludesAnalysis(t *testing.T) {
+	repos := []scanner.Repository{
+		{Path: "/work/api", Name: "api", RelativePath: "api"},
+		{Path: "/work/docs", Name: "docs", RelativePath: "docs"},
+	}
+	analyses := []*scanner.RepositoryAnalysis{
+		{Repository: repos[0], Languages: map[string]int{"Go": 3, "YAML": 1}, TotalFiles: 4},
+	}
+
+	raw := buildTemplateVars("/work", repos, analyses, "/tmp", false, false)["NESTED_REPOS"]
+
+	var got []map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &got); err != nil {
+		t.Fatalf("NESTED_REPOS is not a JSON array: %v\n%s", err, raw)
+	}
+	if len(got) != 2 {
+		t.Fatalf("NESTED_REPOS has %d entries, want 2: %s", len(got), raw)
+	}
+	if got[0]["Name"] != "api" || got[0]["RelativePath"] != "api" {
+		t.Errorf("repository metadata should keep its keys, got %v", got[0])
+	}
+	langs, ok := got[0]["Languages"].(map[string]interface{})
+	if !ok || langs["Go"] != float64(3) || langs["YAML"] != float64(1) {
+		t.Errorf("Languages = %v, want the analysis language map", got[0]["Languages"])
+	}
+	if got[0]["PrimaryLanguage"] != "Go" || got[0]["TotalFiles"] != float64(4) {
+		t.Errorf("analysis summary = %v, want primary language Go and 4 files", got[0])
+	}
+	if _, ok := got[1]["Languages"]; ok {
+		t.Errorf("repository without an analysis should carry only metadata, got %v", got[1])
+	}
+}
+
+func TestGenerate_CodebaseSummary(t *testing.T) {
+	analyses := []*scanner.RepositoryAnalysis{
+		{
+			Repository: scanner.Repository{Name: "api"},
+			Languages:  map[string]int{"Go": 4, "YAML": 1},
+			TotalFiles: 5, TotalLines: 300, TestFiles: 2, CodeFiles: 3,
+		},
+		{
+			Repository: scanner.Repository{Name: "web"},
+			Languages:  map[string]int{"TypeScript": 6, "YAML": 1},
+			TotalFiles: 7, TotalLines: 500, TestFiles: 1, CodeFiles: 5,
+		},
+	}
+
+	prompt := renderedPrompt(t, analyses)
+
+	for _, want := range []string{
+		"- Repositories: 2",
+		"- Total Files: 12",
+		"- Total Lines: 800",
+		"- Primary Language: TypeScript",
+		"- Tests: 3 test files, 8 code files",
+		"- Languages: TypeScript 6, Go 4, YAML 2",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q, got:\n%s", want, prompt)
+		}
+	}
+	summaryAt := strings.Index(prompt, "### Codebase Summary")
+	if summaryAt < 0 || summaryAt > strings.Index(prompt, "### Repository 1: api") {
+		t.Errorf("codebase summary should precede the per-repository details, got:\n%s", prompt)
+	}
+
+	want := "codebase_summary: '2 repositories, 12 files, 800 lines; primary language TypeScript; 3 test files'"
+	if !strings.Contains(prompt, want) {
+		t.Errorf("prompt missing %q, got:\n%s", want, prompt)
+	}
+}
+
+func TestGenerate_GoAPISurface(t *testing.T) {
+	goRepo := &scanner.RepositoryAnalysis{
+		Repository:    scanner.Repository{Name: "lib"},
+		Languages:     map[string]int{"Go": 4},
+		ExportedFuncs: 12,
+		ExportedTypes: 5,
+	}
+	if prompt := renderedPrompt(t, []*scanner.RepositoryAnalysis{goRepo}); !strings.Contains(prompt, "- Go API Surface: 12 exported functions, 5 exported types") {
+		t.Errorf("expected Go API surface in prompt, got:\n%s", prompt)
+	}
+
+	pyRepo := &scanner.RepositoryAnalysis{Repository: scanner.Repository{Name: "app"}, Languages: map[string]int{"Python": 3}}
+	if prompt := renderedPrompt(t, []*scanner.RepositoryAnalysis{pyRepo}); strings.Contains(prompt, "Go API Surface") {
+		t.Errorf("non-Go repository should not report a Go API surface, got:\n%s", prompt)
+	}
+}
+
+func TestRiskAreasLine(t *testing.T) {
+	healthy := &scanner.RepositoryAnalysis{Repository: scanner.Repository{Name: "ok"}, CodeFiles: 10, TestFiles: 10}
+	untested := &scanner.RepositoryAnalysis{Repository: scanner.Repository{Name: "api"}, CodeFiles: 12, Markers: 25}
+
+	tests := []struct {
+		name     string
+		analyses []*scanner.RepositoryAnalysis
+		want     string
+	}{
+		{"none", []*scanner.RepositoryAnalysis{healthy}, "none detected"},
+		{"most severe first", []*scanner.RepositoryAnalysis{healthy, untested},
+			"[high] api: low test ratio (0 test files for 12 code files); [medium] api: TODO/FIXME markers (25 found)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := riskAreasLine(tt.analyses); got != tt.want {
+				t.Errorf("riskAreasLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRiskAreasLine_Capped(t *testing.T) {
+	var analyses []*scanner.RepositoryAnalysis
+	for i := 0; i < maxRiskAreas+2; i++ {
+		analyses = append(analyses, &scanner.RepositoryAnalysis{CodeFiles: 10})
+	}
+	if got := riskAreasLine(analyses); !strings.HasSuffix(got, " (+2 lower-ranked)") || strings.Count(got, "[high]") != maxRiskAreas {
+		t.Errorf("riskAreasLine() = %q, want %d areas and a count of the rest", got, maxRiskAreas)
+	}
+}
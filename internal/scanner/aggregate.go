@@ -0,0 +1,61 @@
+package scanner
+
+// CodebaseSummary totals the analyses of every repository in a codebase.
+type CodebaseSummary struct {
+	Repositories int
+	TotalFiles   int
+	TotalLines   int
+	TestFiles    int
+	CodeFiles    int
+	// LargeFiles counts the files listed in each RepositoryAnalysis's
+	// LargeFiles.
+	LargeFiles int
+	// Secrets counts the potential secrets listed in each
+	// RepositoryAnalysis's Secrets.
+	Secrets int
+	// Languages and LinesByLanguage sum the per-repository maps.
+	Languages       map[string]int
+	LinesByLanguage map[string]int
+	// PrimaryLanguage is the language with the most files overall, ties
+	// broken by name and OtherLanguage aside, or "" when no language was
+	// recognized.
+	PrimaryLanguage string
+}
+
+// Aggregate sums analyses into a CodebaseSummary. Nil analyses are
+// skipped.
+func Aggregate(analyses []*RepositoryAnalysis) CodebaseSummary {
+	s := CodebaseSummary{
+		Languages:       make(map[string]int),
+		LinesByLanguage: make(map[string]int),
+	}
+	for _, a := range analyses {
+		if a == nil {
+			continue
+		}
+		s.Repositories++
+		s.TotalFiles += a.TotalFiles
+		s.TotalLines += a.TotalLines
+		s.TestFiles += a.TestFiles
+		s.CodeFiles += a.CodeFiles
+		s.LargeFiles += len(a.LargeFiles)
+		s.Secrets += len(a.Secrets)
+		for lang, count := range a.Languages {
+			s.Languages[lang] += count
+		}
+		for lang, lines := range a.LinesByLanguage {
+			s.LinesByLanguage[lang] += lines
+		}
+	}
+	s.PrimaryLanguage = primaryLanguage(SortCounts(s.Languages))
+	return s
+}
+
+// TestToCodeRatio returns the number of test files per non-test code file
+// across the codebase, or 0 when there is no code.
+func (s CodebaseSummary) TestToCodeRatio() float64 {
+	if s.CodeFiles == 0 {
+		return 0
+	}
+	return float64(s.TestFiles) / float64(s.CodeFiles)
+}
@@ -0,0 +1,59 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	analyses := []*RepositoryAnalysis{
+		{
+			TotalFiles:      5,
+			TotalLines:      300,
+			TestFiles:       2,
+			CodeFiles:       3,
+			LargeFiles:      []LargeFile{{Path: "dist/app.tar", SizeBytes: 6 << 20}},
+			Languages:       map[string]int{"Go": 4, "YAML": 1},
+			LinesByLanguage: map[string]int{"Go": 280, "YAML": 20},
+		},
+		nil,
+		{
+			TotalFiles:      7,
+			TotalLines:      500,
+			TestFiles:       1,
+			CodeFiles:       5,
+			Languages:       map[string]int{"TypeScript": 6, "YAML": 1},
+			LinesByLanguage: map[string]int{"TypeScript": 490, "YAML": 10},
+		},
+	}
+
+	got := Aggregate(analyses)
+
+	want := CodebaseSummary{
+		Repositories:    2,
+		TotalFiles:      12,
+		TotalLines:      800,
+		TestFiles:       3,
+		CodeFiles:       8,
+		LargeFiles:      1,
+		Languages:       map[string]int{"Go": 4, "TypeScript": 6, "YAML": 2},
+		LinesByLanguage: map[string]int{"Go": 280, "TypeScript": 490, "YAML": 30},
+		PrimaryLanguage: "TypeScript",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Aggregate() = %+v, want %+v", got, want)
+	}
+	if ratio := got.TestToCodeRatio(); ratio != 3.0/8 {
+		t.Errorf("TestToCodeRatio() = %v, want %v", ratio, 3.0/8)
+	}
+}
+
+func TestAggregate_Empty(t *testing.T) {
+	got := Aggregate(nil)
+	if got.Repositories != 0 || got.TotalFiles != 0 || got.PrimaryLanguage != "" {
+		t.Errorf("Aggregate(nil) = %+v, want zero totals", got)
+	}
+	if got.TestToCodeRatio() != 0 {
+		t.Errorf("TestToCodeRatio() = %v, want 0 without code", got.TestToCodeRatio())
+	}
+}
@@ -0,0 +1,75 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// analysisWalker is the walk function of AnalyzeRepositoryWithOptions. Its
+// visit method may be called concurrently (see walkParallel): files are
+// classified without holding mu, and only the counts are updated under it.
+type analysisWalker struct {
+	ctx      context.Context
+	repo     Repository
+	opts     ScanOptions
+	log      *logger.Logger
+	ignore   *ignoreMatcher
+	progress *progressCounter
+
+	mu       sync.Mutex
+	analysis *RepositoryAnalysis
+}
+
+// visit accounts for one path of the repository walk.
+func (w *analysisWalker) visit(path string, info os.FileInfo, err error) error {
+	if ctxErr := w.ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if err != nil {
+		if path == w.repo.Path {
+			return rootError(w.repo.Path, err)
+		}
+		// Keep walking, but account for what could not be read.
+		w.log.Trace("Skipping unreadable path %s: %v", path, err)
+		w.mu.Lock()
+		w.analysis.recordSkipped(w.repo.Path, path)
+		w.mu.Unlock()
+		return nil
+	}
+
+	// Skip hidden directories and common ignore patterns
+	if info.IsDir() && w.opts.SkipsDir(info.Name()) {
+		w.log.Trace("Skipping directory %s", path)
+		return filepath.SkipDir
+	}
+	if info.Name() == gitDir {
+		// A gitlink file in a submodule or worktree checkout.
+		return nil
+	}
+	if path != w.repo.Path && w.ignore.matches(relativeTo(w.repo.Path, path), info.IsDir()) {
+		w.log.Trace("Ignoring %s per %s", path, IgnoreFileName)
+		w.mu.Lock()
+		w.analysis.IgnoredFiles++
+		w.mu.Unlock()
+		if info.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	}
+
+	if !info.IsDir() {
+		stat := statFile(path, info, w.opts)
+		w.log.Trace("File %s (language %q)", path, stat.Language)
+		w.mu.Lock()
+		w.progress.tick()
+		w.analysis.addFile(stat, 1)
+		w.analysis.Files[relativeTo(w.repo.Path, path)] = stat
+		w.mu.Unlock()
+	}
+
+	return nil
+}
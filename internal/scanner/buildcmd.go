@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"path/filepath"
+)
+
+// buildCommandMarkers maps root-level build files to the command that
+// usually builds a repository containing them. A Makefile comes first
+// because it typically wraps the language tool.
+var buildCommandMarkers = []struct{ file, command string }{
+	{"Makefile", "make"},
+	{"go.mod", "go build ./..."},
+	{"Cargo.toml", "cargo build"},
+	{"pom.xml", "mvn package"},
+	{"build.gradle", "gradle build"},
+	{"build.gradle.kts", "gradle build"},
+	{"pyproject.toml", "python -m build"},
+	{"setup.py", "python -m build"},
+	{"CMakeLists.txt", "cmake -B build && cmake --build build"},
+}
+
+// BuildCommands returns the likely commands to build the repository at
+// repoPath, from the build files at its root: "go build ./..." for a Go
+// module, "npm run build" for a package.json with a build script, "make"
+// for a Makefile, and so on. Gradle and npm commands follow the wrapper or
+// package manager the repository uses. More than one command means the
+// build is ambiguous; none means no build system was recognized.
+func BuildCommands(repoPath string) []string {
+	var cmds []string
+	add := func(cmd string) {
+		for _, c := range cmds {
+			if c == cmd {
+				return
+			}
+		}
+		cmds = append(cmds, cmd)
+	}
+
+	for _, m := range buildCommandMarkers {
+		if !fileExists(filepath.Join(repoPath, m.file)) {
+			continue
+		}
+		if m.command == "gradle build" && fileExists(filepath.Join(repoPath, "gradlew")) {
+			add("./gradlew build")
+			continue
+		}
+		add(m.command)
+	}
+	if scripts, ok := npmScripts(filepath.Join(repoPath, "package.json")); ok && scripts["build"] != "" {
+		add(npmBuildCommand(repoPath))
+	}
+	return cmds
+}
+
+// npmBuildCommand runs the build script with the package manager whose
+// lockfile is present, defaulting to npm.
+func npmBuildCommand(repoPath string) string {
+	switch {
+	case fileExists(filepath.Join(repoPath, "pnpm-lock.yaml")):
+		return "pnpm run build"
+	case fileExists(filepath.Join(repoPath, "yarn.lock")):
+		return "yarn build"
+	}
+	return "npm run build"
+}
@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildCommands(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name:  "go module",
+			files: map[string]string{"go.mod": "module example.com/app\n", "main.go": "package main\n"},
+			want:  []string{"go build ./..."},
+		},
+		{
+			name: "makefile and package.json are ambiguous",
+			files: map[string]string{
+				"Makefile":     "all:\n\tnpm run build\n",
+				"package.json": `{"scripts": {"build": "vite build", "test": "vitest"}}`,
+			},
+			want: []string{"make", "npm run build"},
+		},
+		{
+			name:  "package.json without a build script",
+			files: map[string]string{"package.json": `{"scripts": {"test": "jest"}}`},
+			want:  nil,
+		},
+		{
+			name:  "yarn lockfile",
+			files: map[string]string{"package.json": `{"scripts": {"build": "tsc"}}`, "yarn.lock": ""},
+			want:  []string{"yarn build"},
+		},
+		{
+			name:  "cargo",
+			files: map[string]string{"Cargo.toml": "[package]\n"},
+			want:  []string{"cargo build"},
+		},
+		{
+			name:  "maven",
+			files: map[string]string{"pom.xml": "<project/>\n"},
+			want:  []string{"mvn package"},
+		},
+		{
+			name:  "gradle wrapper listed once",
+			files: map[string]string{"build.gradle": "", "build.gradle.kts": "", "gradlew": "#!/bin/sh\n"},
+			want:  []string{"./gradlew build"},
+		},
+		{
+			name:  "nothing recognized",
+			files: map[string]string{"README.md": "# app\n"},
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTree(t, dir, tt.files)
+			if got := BuildCommands(dir); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildCommands() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
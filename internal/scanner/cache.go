@@ -0,0 +1,115 @@
+package scanner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// CacheFileName is the analysis cache file written to the output directory.
+const CacheFileName = "analysis-cache.json"
+
+// analysisCache is the on-disk form of cached repository analyses.
+// Settings digests the analysis options, so a stale cache recorded with
+// different options is not brought up to date by UpdateAnalysis.
+type analysisCache struct {
+	Fingerprint string                `json:"fingerprint"`
+	Settings    string                `json:"settings"`
+	Analyses    []*RepositoryAnalysis `json:"analyses"`
+}
+
+// Fingerprint returns a digest of the files that AnalyzeRepositoryWithOptions
+// would visit in repos (their relative paths, sizes, and modification times)
+// together with the options that affect the analysis. It only stats files, so
+// it is much cheaper than a full analysis. It stops with ctx.Err() when ctx
+// is cancelled.
+func Fingerprint(ctx context.Context, repos []Repository, opts ScanOptions) (string, error) {
+	h := sha256.New()
+	opts.writeAnalysisSettings(h)
+	for _, repo := range repos {
+		fmt.Fprintf(h, "repo %s\n", repo.Path)
+		if err := fingerprintTree(ctx, h, repo.Path, opts); err != nil {
+			return "", fmt.Errorf("failed to fingerprint %s: %w", repo.Path, err)
+		}
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func fingerprintTree(ctx context.Context, w io.Writer, root string, opts ScanOptions) error {
+	return walkTree(root, opts, func(path string, info os.FileInfo, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if opts.SkipsDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Name() == gitDir {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
+		}
+		fmt.Fprintf(w, "%s %d %d\n", filepath.ToSlash(rel), info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+}
+
+// LoadAnalysisCache returns the analyses cached in dir when they were
+// recorded for fingerprint. A missing, unreadable, or stale cache is a miss.
+func LoadAnalysisCache(dir, fingerprint string) ([]*RepositoryAnalysis, bool) {
+	cache, ok := readAnalysisCache(dir)
+	if !ok || cache.Fingerprint != fingerprint {
+		return nil, false
+	}
+	return cache.Analyses, true
+}
+
+// LoadStaleAnalysisCache returns the analyses cached in dir whatever
+// fingerprint they were recorded under, as a starting point for
+// UpdateAnalysis, when they were recorded with the same analysis options
+// as opts. A missing, unreadable, or mismatched cache is a miss.
+func LoadStaleAnalysisCache(dir string, opts ScanOptions) ([]*RepositoryAnalysis, bool) {
+	cache, ok := readAnalysisCache(dir)
+	if !ok || cache.Settings != settingsDigest(opts) {
+		return nil, false
+	}
+	return cache.Analyses, true
+}
+
+func readAnalysisCache(dir string) (analysisCache, bool) {
+	var cache analysisCache
+	data, err := os.ReadFile(filepath.Join(dir, CacheFileName))
+	if err != nil {
+		return cache, false
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return cache, false
+	}
+	return cache, true
+}
+
+// SaveAnalysisCache records analyses, run with opts, in dir under
+// fingerprint.
+func SaveAnalysisCache(dir, fingerprint string, opts ScanOptions, analyses []*RepositoryAnalysis) error {
+	cache := analysisCache{Fingerprint: fingerprint, Settings: settingsDigest(opts), Analyses: analyses}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal analysis cache: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, CacheFileName), data, 0644); err != nil {
+		return fmt.Errorf("failed to write analysis cache: %w", err)
+	}
+	return nil
+}
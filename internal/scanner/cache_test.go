@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(file, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repos := []Repository{{Path: dir, Name: "repo"}}
+
+	first, err := Fingerprint(context.Background(), repos, ScanOptions{})
+	if err != nil {
+		t.Fatalf("Fingerprint() error = %v", err)
+	}
+	again, _ := Fingerprint(context.Background(), repos, ScanOptions{})
+	if first != again {
+		t.Errorf("Fingerprint() not stable: %s vs %s", first, again)
+	}
+
+	// Changes under skipped directories do not affect the fingerprint.
+	if err := os.MkdirAll(filepath.Join(dir, "node_modules"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "node_modules", "dep.js"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := Fingerprint(context.Background(), repos, ScanOptions{}); got != first {
+		t.Error("Fingerprint() changed for a file in a skipped directory")
+	}
+
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := Fingerprint(context.Background(), repos, ScanOptions{}); got == first {
+		t.Error("Fingerprint() unchanged after modifying a file")
+	}
+}
+
+func TestAnalysisCache(t *testing.T) {
+	dir := t.TempDir()
+	analyses := []*RepositoryAnalysis{{
+		Repository: Repository{Name: "cached"},
+		Languages:  map[string]int{"Go": 3},
+		TotalFiles: 3,
+	}}
+
+	if _, ok := LoadAnalysisCache(dir, "sha256:abc"); ok {
+		t.Fatal("LoadAnalysisCache() hit with no cache file")
+	}
+	if err := SaveAnalysisCache(dir, "sha256:abc", ScanOptions{}, analyses); err != nil {
+		t.Fatalf("SaveAnalysisCache() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		fingerprint string
+		wantHit     bool
+	}{
+		{"matching fingerprint", "sha256:abc", true},
+		{"changed fingerprint", "sha256:def", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := LoadAnalysisCache(dir, tt.fingerprint)
+			if ok != tt.wantHit {
+				t.Fatalf("LoadAnalysisCache() hit = %v, want %v", ok, tt.wantHit)
+			}
+			if ok && (len(got) != 1 || got[0].Repository.Name != "cached" || got[0].Languages["Go"] != 3) {
+				t.Errorf("LoadAnalysisCache() = %+v, want the saved analyses", got)
+			}
+		})
+	}
+}
+
+func TestLoadStaleAnalysisCache(t *testing.T) {
+	dir := t.TempDir()
+	if _, ok := LoadStaleAnalysisCache(dir, ScanOptions{}); ok {
+		t.Fatal("LoadStaleAnalysisCache() hit with no cache file")
+	}
+	analyses := []*RepositoryAnalysis{{Repository: Repository{Name: "cached"}, TotalFiles: 1}}
+	if err := SaveAnalysisCache(dir, "sha256:old", ScanOptions{}, analyses); err != nil {
+		t.Fatalf("SaveAnalysisCache() error = %v", err)
+	}
+	got, ok := LoadStaleAnalysisCache(dir, ScanOptions{})
+	if !ok || len(got) != 1 || got[0].Repository.Name != "cached" {
+		t.Errorf("LoadStaleAnalysisCache() = %+v, %v, want the saved analyses", got, ok)
+	}
+
+	for _, opts := range []ScanOptions{
+		{IncludeHidden: true},
+		{OnlyLanguages: []string{"Go"}},
+		{ScanSecrets: true},
+		{LangMap: map[string]string{".tpl": "Go"}},
+		{SkipDirs: []string{"vendor"}},
+	} {
+		if _, ok := LoadStaleAnalysisCache(dir, opts); ok {
+			t.Errorf("LoadStaleAnalysisCache() hit for a cache recorded with other options %+v", opts)
+		}
+	}
+}
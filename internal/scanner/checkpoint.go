@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// CheckpointFileName is the file in the output directory recording the
+// repositories analyzed so far, so an interrupted scan can be resumed.
+const CheckpointFileName = "scan-checkpoint.jsonl"
+
+// checkpointHeader is the first line of a checkpoint file. Settings
+// digests the analysis options, so a checkpoint recorded with different
+// options is not resumed.
+type checkpointHeader struct {
+	Settings string `json:"settings"`
+}
+
+// Checkpoint appends each completed repository analysis to
+// CheckpointFileName as one JSON line, so an interruption loses at most
+// the analysis in progress.
+type Checkpoint struct {
+	f   *os.File
+	enc *json.Encoder
+}
+
+// CreateCheckpoint starts a checkpoint in dir for analyses run with opts,
+// replacing any earlier one, and records done, the analyses already
+// completed (such as those carried over from a resumed checkpoint).
+func CreateCheckpoint(dir string, opts ScanOptions, done []*RepositoryAnalysis) (*Checkpoint, error) {
+	f, err := os.Create(filepath.Join(dir, CheckpointFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint: %w", err)
+	}
+	c := &Checkpoint{f: f, enc: json.NewEncoder(f)}
+	if err := c.enc.Encode(checkpointHeader{Settings: settingsDigest(opts)}); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	for _, analysis := range done {
+		if err := c.Add(analysis); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Add records a completed analysis.
+func (c *Checkpoint) Add(analysis *RepositoryAnalysis) error {
+	if err := c.enc.Encode(analysis); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Close closes the checkpoint file, leaving it in place.
+func (c *Checkpoint) Close() error {
+	return c.f.Close()
+}
+
+// LoadCheckpoint returns the analyses recorded in dir's checkpoint, keyed
+// by repository path, when it was recorded with the same analysis options
+// as opts. A truncated last line, as left by an interruption mid-write, is
+// ignored. A missing, unreadable, or mismatched checkpoint is a miss.
+func LoadCheckpoint(dir string, opts ScanOptions) (map[string]*RepositoryAnalysis, bool) {
+	f, err := os.Open(filepath.Join(dir, CheckpointFileName))
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	var header checkpointHeader
+	if err := dec.Decode(&header); err != nil || header.Settings != settingsDigest(opts) {
+		return nil, false
+	}
+	done := make(map[string]*RepositoryAnalysis)
+	for {
+		var analysis RepositoryAnalysis
+		if err := dec.Decode(&analysis); err != nil {
+			break
+		}
+		done[analysis.Repository.Path] = &analysis
+	}
+	return done, true
+}
+
+// RemoveCheckpoint deletes dir's checkpoint, if any.
+func RemoveCheckpoint(dir string) error {
+	err := os.Remove(filepath.Join(dir, CheckpointFileName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+	return nil
+}
+
+// settingsDigest returns a digest of the options that change analysis
+// results.
+func settingsDigest(opts ScanOptions) string {
+	h := sha256.New()
+	opts.writeAnalysisSettings(h)
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,56 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	if _, ok := LoadCheckpoint(dir, ScanOptions{}); ok {
+		t.Fatal("LoadCheckpoint() hit with no checkpoint file")
+	}
+
+	first := &RepositoryAnalysis{Repository: Repository{Path: "/src/a", Name: "a"}, TotalFiles: 3}
+	c, err := CreateCheckpoint(dir, ScanOptions{}, []*RepositoryAnalysis{first})
+	if err != nil {
+		t.Fatalf("CreateCheckpoint() error = %v", err)
+	}
+	if err := c.Add(&RepositoryAnalysis{Repository: Repository{Path: "/src/b", Name: "b"}, TotalFiles: 5}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	done, ok := LoadCheckpoint(dir, ScanOptions{})
+	if !ok || len(done) != 2 || done["/src/a"].TotalFiles != 3 || done["/src/b"].TotalFiles != 5 {
+		t.Fatalf("LoadCheckpoint() = %v, %v, want a and b", done, ok)
+	}
+	if _, ok := LoadCheckpoint(dir, ScanOptions{IncludeHidden: true}); ok {
+		t.Error("LoadCheckpoint() hit for different analysis options")
+	}
+
+	// A record cut short by an interruption is dropped.
+	path := filepath.Join(dir, CheckpointFileName)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"Repository":{"Path":"/src/c"`)
+	f.Close()
+	if done, ok := LoadCheckpoint(dir, ScanOptions{}); !ok || len(done) != 2 {
+		t.Errorf("LoadCheckpoint() with a truncated record = %d analyses, %v, want 2", len(done), ok)
+	}
+
+	if err := RemoveCheckpoint(dir); err != nil {
+		t.Fatalf("RemoveCheckpoint() error = %v", err)
+	}
+	if err := RemoveCheckpoint(dir); err != nil {
+		t.Errorf("RemoveCheckpoint() with no checkpoint error = %v", err)
+	}
+	if _, ok := LoadCheckpoint(dir, ScanOptions{}); ok {
+		t.Error("LoadCheckpoint() hit after RemoveCheckpoint()")
+	}
+}
@@ -0,0 +1,75 @@
+package scanner
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+// sniffLen is the number of leading bytes inspected to classify a file.
+const sniffLen = 512
+
+// isBinaryFile reports whether the file at path looks binary. Files that
+// cannot be read are treated as text so they are not misreported.
+func isBinaryFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	buf := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false
+	}
+	return looksBinary(buf[:n])
+}
+
+// looksBinary reports whether data contains a NUL byte or is not valid
+// UTF-8. A multi-byte sequence cut off at the end of data is not counted as
+// invalid, since data is usually a prefix of a larger file.
+func looksBinary(data []byte) bool {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return true
+	}
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		if r == utf8.RuneError && size <= 1 {
+			return !(len(data) < utf8.UTFMax && !utf8.FullRune(data))
+		}
+		data = data[size:]
+	}
+	return false
+}
+
+// countLines returns the number of lines in the file at path, counting a
+// final line without a trailing newline. Unreadable files count as zero.
+func countLines(path string) int {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	buf := make([]byte, 32*1024)
+	lines := 0
+	var last byte
+	read := false
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			lines += bytes.Count(buf[:n], []byte{'\n'})
+			last = buf[n-1]
+			read = true
+		}
+		if err != nil {
+			break
+		}
+	}
+	if read && last != '\n' {
+		lines++
+	}
+	return lines
+}
@@ -0,0 +1,88 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestLooksBinary(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{"empty", []byte{}, false},
+		{"ascii", []byte("package main\n"), false},
+		{"utf8", []byte("héllo wörld ✓"), false},
+		{"null byte", []byte("abc\x00def"), true},
+		{"invalid utf8", []byte{0xff, 0xfe, 'a', 'b'}, true},
+		{"truncated multibyte at end", append([]byte("abc"), 0xe2, 0x9c), false},
+		{"png header", []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0, 0, 0, 0x0d}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := looksBinary(tt.data); got != tt.want {
+				t.Errorf("looksBinary(%q) = %v, want %v", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepository_BinaryAndTextCounts(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Título\n\nUTF-8 text ✓\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	binary := []byte{0x7f, 'E', 'L', 'F', 2, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	if err := os.WriteFile(filepath.Join(dir, "app.bin"), binary, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := AnalyzeRepository(Repository{Path: dir, Name: "mixed"}, logger.New(false))
+	if err != nil {
+		t.Fatalf("AnalyzeRepository() error = %v", err)
+	}
+
+	if analysis.TextFiles != 1 {
+		t.Errorf("TextFiles = %d, want 1", analysis.TextFiles)
+	}
+	if analysis.BinaryFiles != 1 {
+		t.Errorf("BinaryFiles = %d, want 1", analysis.BinaryFiles)
+	}
+	if got := analysis.BinaryRatio(); got != 0.5 {
+		t.Errorf("BinaryRatio() = %v, want 0.5", got)
+	}
+}
+
+func TestCountLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{"empty", "", 0},
+		{"trailing newline", "a\nb\nc\n", 3},
+		{"no trailing newline", "a\nb", 2},
+		{"single line", "package main", 1},
+		{"blank lines", "\n\n", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "f.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if got := countLines(path); got != tt.want {
+				t.Errorf("countLines() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package scanner
+
+import "sort"
+
+// NamedCount is one entry of a per-language or per-extension file count.
+type NamedCount struct {
+	Name  string
+	Count int
+}
+
+// SortCounts returns the entries of counts ordered by count, highest
+// first, with ties broken by name so the order is stable across runs.
+func SortCounts(counts map[string]int) []NamedCount {
+	sorted := make([]NamedCount, 0, len(counts))
+	for name, count := range counts {
+		sorted = append(sorted, NamedCount{Name: name, Count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// SortedLanguages returns the analysis' languages ordered by SortCounts.
+func (a *RepositoryAnalysis) SortedLanguages() []NamedCount {
+	return SortCounts(a.Languages)
+}
@@ -0,0 +1,26 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortCounts(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts map[string]int
+		want   []NamedCount
+	}{
+		{"empty", nil, []NamedCount{}},
+		{"by count", map[string]int{"Go": 2, "Python": 9, "Shell": 4}, []NamedCount{{"Python", 9}, {"Shell", 4}, {"Go", 2}}},
+		{"ties by name", map[string]int{".ts": 3, ".go": 3, ".md": 1, ".c": 3}, []NamedCount{{".c", 3}, {".go", 3}, {".ts", 3}, {".md", 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SortCounts(tt.counts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortCounts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
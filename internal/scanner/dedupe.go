@@ -0,0 +1,51 @@
+package scanner
+
+import "path/filepath"
+
+// repoDeduper recognizes repositories already discovered under another
+// path: the same directory reached through a symlink, or a copy of a
+// repository with the same remote.
+type repoDeduper struct {
+	// seen maps a "path:" or "remote:" key to the path of the first
+	// repository that had it.
+	seen map[string]string
+}
+
+func newRepoDeduper() *repoDeduper {
+	return &repoDeduper{seen: make(map[string]string)}
+}
+
+// duplicate reports whether repo matches one seen before, returning the
+// path of the first occurrence and what matched. Otherwise repo is recorded
+// and ok is false.
+func (d *repoDeduper) duplicate(repo Repository) (firstPath, reason string, ok bool) {
+	keys := []struct{ key, reason string }{
+		{"path:" + resolvedPath(repo.Path), "resolved path"},
+	}
+	if repo.RemoteURL != "" {
+		keys = append(keys, struct{ key, reason string }{"remote:" + repo.RemoteURL, "remote URL"})
+	}
+
+	for _, k := range keys {
+		if first, found := d.seen[k.key]; found {
+			return first, k.reason, true
+		}
+	}
+	for _, k := range keys {
+		d.seen[k.key] = repo.Path
+	}
+	return "", "", false
+}
+
+// resolvedPath returns the absolute path with symlinks evaluated, falling
+// back to the cleaned absolute path when the link cannot be resolved.
+func resolvedPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = filepath.Clean(path)
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved
+	}
+	return abs
+}
@@ -0,0 +1,87 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+const originConfig = "[remote \"origin\"]\n\turl = https://github.com/example/app.git\n"
+
+func TestFindGitReposWithOptions_SkipsSymlinkedCopy(t *testing.T) {
+	root := t.TempDir()
+	repoDir := filepath.Join(root, "app")
+	writeGitFile(t, repoDir, "HEAD", "ref: refs/heads/main\n")
+	if err := os.Symlink(repoDir, filepath.Join(root, "app-link")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	for _, follow := range []bool{false, true} {
+		repos, err := FindGitReposWithOptions(context.Background(), root, ScanOptions{FollowSymlinks: follow}, logger.New(false))
+		if err != nil {
+			t.Fatalf("FindGitReposWithOptions(follow=%v) error = %v", follow, err)
+		}
+		if len(repos) != 1 {
+			t.Errorf("FindGitReposWithOptions(follow=%v) found %d repos, want 1: %+v", follow, len(repos), repos)
+		}
+	}
+}
+
+func TestFindGitRepos_SkipsCopyWithSameRemote(t *testing.T) {
+	root := t.TempDir()
+	writeGitFile(t, filepath.Join(root, "a-original"), "config", originConfig)
+	writeGitFile(t, filepath.Join(root, "b-copy"), "config", originConfig)
+	writeGitFile(t, filepath.Join(root, "c-other"), "config", "[remote \"origin\"]\n\turl = https://github.com/example/other.git\n")
+
+	repos, err := FindGitRepos(root, logger.New(false))
+	if err != nil {
+		t.Fatalf("FindGitRepos() error = %v", err)
+	}
+
+	var names []string
+	for _, r := range repos {
+		names = append(names, r.Name)
+	}
+	if len(names) != 2 || names[0] != "a-original" || names[1] != "c-other" {
+		t.Errorf("FindGitRepos() repos = %v, want [a-original c-other]", names)
+	}
+}
+
+func TestRepoDeduper(t *testing.T) {
+	root := t.TempDir()
+	realDir := filepath.Join(root, "real")
+	if err := os.Mkdir(realDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	linkDir := filepath.Join(root, "link")
+	if err := os.Symlink(realDir, linkDir); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		repo       Repository
+		wantDup    bool
+		wantReason string
+	}{
+		{"first occurrence", Repository{Path: realDir, RemoteURL: "https://x/app.git"}, false, ""},
+		{"same directory through symlink", Repository{Path: linkDir}, true, "resolved path"},
+		{"same remote elsewhere", Repository{Path: filepath.Join(root, "clone"), RemoteURL: "https://x/app.git"}, true, "remote URL"},
+		{"no remote, new path", Repository{Path: filepath.Join(root, "local")}, false, ""},
+		{"empty remotes never match", Repository{Path: filepath.Join(root, "local2")}, false, ""},
+	}
+
+	d := newRepoDeduper()
+	for _, tt := range tests {
+		first, reason, dup := d.duplicate(tt.repo)
+		if dup != tt.wantDup || reason != tt.wantReason {
+			t.Errorf("%s: duplicate() = (%q, %q, %v), want reason %q, dup %v", tt.name, first, reason, dup, tt.wantReason, tt.wantDup)
+		}
+		if dup && first != realDir {
+			t.Errorf("%s: first occurrence = %q, want %q", tt.name, first, realDir)
+		}
+	}
+}
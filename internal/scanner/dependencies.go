@@ -0,0 +1,58 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DependencyVersions returns the dependencies declared in the manifests at
+// the root of repo (go.mod, package.json, and requirements.txt) mapped to
+// their declared version or version constraint. Requirements without a
+// version map to "".
+func DependencyVersions(repo Repository) map[string]string {
+	deps := goModRequirements(filepath.Join(repo.Path, "go.mod"))
+	for name, version := range npmDependencyVersions(filepath.Join(repo.Path, "package.json")) {
+		deps[name] = version
+	}
+	for name, version := range pythonRequirementVersions(filepath.Join(repo.Path, "requirements.txt")) {
+		deps[name] = version
+	}
+	return deps
+}
+
+// goModRequirements returns the module requirements in the go.mod at path,
+// from both single-line and block require directives.
+func goModRequirements(path string) map[string]string {
+	deps := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return deps
+	}
+
+	inBlock := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inBlock && fields[0] == ")":
+			inBlock = false
+			continue
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inBlock = true
+			continue
+		case fields[0] == "require":
+			fields = fields[1:]
+		case !inBlock:
+			continue
+		}
+		if len(fields) >= 2 {
+			deps[fields[0]] = fields[1]
+		}
+	}
+	return deps
+}
@@ -0,0 +1,37 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDependencyVersions(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.21\n\nrequire github.com/spf13/cobra v1.8.0\n\n" +
+			"require (\n\tgithub.com/gin-gonic/gin v1.9.1\n\tgolang.org/x/sys v0.13.0 // indirect\n)\n",
+		"package.json":     `{"dependencies": {"react": "^18.2.0"}, "devDependencies": {"jest": "~29.7.0"}}`,
+		"requirements.txt": "# web\nDjango==4.2.7\nflask[async]>=2.0 ; python_version > '3.8'\nrequests\n-r dev.txt\n",
+	})
+
+	got := DependencyVersions(Repository{Path: dir})
+	want := map[string]string{
+		"github.com/spf13/cobra":   "v1.8.0",
+		"github.com/gin-gonic/gin": "v1.9.1",
+		"golang.org/x/sys":         "v0.13.0",
+		"react":                    "^18.2.0",
+		"jest":                     "~29.7.0",
+		"django":                   "==4.2.7",
+		"flask":                    ">=2.0",
+		"requests":                 "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DependencyVersions() = %v, want %v", got, want)
+	}
+}
+
+func TestDependencyVersions_NoManifests(t *testing.T) {
+	if got := DependencyVersions(Repository{Path: t.TempDir()}); len(got) != 0 {
+		t.Errorf("DependencyVersions() = %v, want empty", got)
+	}
+}
@@ -0,0 +1,95 @@
+package scanner
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DocInventory lists the documentation already present in a repository.
+// Paths are slash-separated and relative to the repository.
+type DocInventory struct {
+	// READMEs are README files at any level.
+	READMEs []string
+	// Docs are Markdown files under docs/, excluding ADRs.
+	Docs []string
+	// ADRs are architecture decision records, docs/adr/*.md.
+	ADRs []string
+	// Changelogs and Contributing are CHANGELOG and CONTRIBUTING files at
+	// the root (or, for CONTRIBUTING, under .github/).
+	Changelogs   []string
+	Contributing []string
+}
+
+// Total returns the number of documentation files in the inventory.
+func (d DocInventory) Total() int {
+	return len(d.READMEs) + len(d.Docs) + len(d.ADRs) + len(d.Changelogs) + len(d.Contributing)
+}
+
+// InventoryDocs lists repo's READMEs, Markdown under docs/, ADRs under
+// docs/adr/, and its root CHANGELOG and CONTRIBUTING files. Directories
+// skipped by analysis are not searched. Each list is sorted.
+func InventoryDocs(repo Repository) DocInventory {
+	var inv DocInventory
+	_ = filepath.WalkDir(repo.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != repo.Path && (ScanOptions{}).SkipsDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel := relativeTo(repo.Path, path)
+		switch {
+		case hasDocPrefix(d.Name(), "README"):
+			inv.READMEs = append(inv.READMEs, rel)
+		case isMarkdown(rel) && strings.HasPrefix(rel, "docs/adr/") && !strings.Contains(rel[len("docs/adr/"):], "/"):
+			inv.ADRs = append(inv.ADRs, rel)
+		case isMarkdown(rel) && strings.HasPrefix(rel, "docs/"):
+			inv.Docs = append(inv.Docs, rel)
+		}
+		return nil
+	})
+
+	inv.Changelogs = rootDocs(repo.Path, "", "CHANGELOG")
+	inv.Contributing = append(rootDocs(repo.Path, "", "CONTRIBUTING"), rootDocs(repo.Path, ".github", "CONTRIBUTING")...)
+
+	for _, list := range [][]string{inv.READMEs, inv.Docs, inv.ADRs} {
+		sort.Strings(list)
+	}
+	return inv
+}
+
+// rootDocs returns the files in root/dir whose names start with prefix,
+// ignoring case, as paths relative to root.
+func rootDocs(root, dir, prefix string) []string {
+	entries, err := os.ReadDir(filepath.Join(root, dir))
+	if err != nil {
+		return nil
+	}
+	var docs []string
+	for _, entry := range entries {
+		if !entry.IsDir() && hasDocPrefix(entry.Name(), prefix) {
+			docs = append(docs, filepath.ToSlash(filepath.Join(dir, entry.Name())))
+		}
+	}
+	return docs
+}
+
+// hasDocPrefix reports whether name is prefix, or prefix followed by an
+// extension, ignoring case: README, readme.md, and README.rst all match
+// "README", but READMEFIRST does not.
+func hasDocPrefix(name, prefix string) bool {
+	upper := strings.ToUpper(name)
+	return upper == prefix || strings.HasPrefix(upper, prefix+".")
+}
+
+func isMarkdown(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".md" || ext == ".markdown"
+}
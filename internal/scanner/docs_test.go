@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInventoryDocs(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  DocInventory
+		total int
+	}{
+		{
+			name: "README and docs",
+			files: map[string]string{
+				"README.md":                    "# App\n",
+				"cmd/tool/readme.txt":          "usage\n",
+				"READMEFIRST":                  "not a readme\n",
+				"docs/guide.md":                "# Guide\n",
+				"docs/api/reference.markdown":  "# API\n",
+				"docs/diagram.png":             "PNG",
+				"docs/adr/0001-use-go.md":      "# ADR 1\n",
+				"docs/adr/0002-use-sqlite.md":  "# ADR 2\n",
+				"CHANGELOG.md":                 "## 1.0\n",
+				".github/CONTRIBUTING.md":      "PRs welcome\n",
+				"node_modules/pkg/README.md":   "vendored\n",
+				"vendor/example.com/README.md": "vendored\n",
+			},
+			want: DocInventory{
+				READMEs:      []string{"README.md", "cmd/tool/readme.txt"},
+				Docs:         []string{"docs/api/reference.markdown", "docs/guide.md"},
+				ADRs:         []string{"docs/adr/0001-use-go.md", "docs/adr/0002-use-sqlite.md"},
+				Changelogs:   []string{"CHANGELOG.md"},
+				Contributing: []string{".github/CONTRIBUTING.md"},
+			},
+			total: 8,
+		},
+		{
+			name:  "no docs",
+			files: map[string]string{"main.go": "package main\n", "notes.md": "scratch\n"},
+			want:  DocInventory{},
+			total: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTree(t, dir, tt.files)
+			got := InventoryDocs(Repository{Path: dir})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InventoryDocs() = %+v, want %+v", got, tt.want)
+			}
+			if got.Total() != tt.total {
+				t.Errorf("Total() = %d, want %d", got.Total(), tt.total)
+			}
+		})
+	}
+}
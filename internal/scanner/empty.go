@@ -0,0 +1,20 @@
+package scanner
+
+import "os"
+
+// isEmptyRepo reports whether the directory at path holds a .git entry
+// and nothing else, as a freshly initialized repository does.
+func isEmptyRepo(path string) bool {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return false
+	}
+	hasGit := false
+	for _, entry := range entries {
+		if entry.Name() != gitDir {
+			return false
+		}
+		hasGit = true
+	}
+	return hasGit
+}
@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestAnalyzeRepository_IsEmpty(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  bool
+	}{
+		{"only .git", map[string]string{".git/HEAD": "ref: refs/heads/main\n"}, true},
+		{"with a file", map[string]string{".git/HEAD": "ref: refs/heads/main\n", "main.go": "package main\n"}, false},
+		{"only a hidden file", map[string]string{".git/HEAD": "ref: refs/heads/main\n", ".env.example": "A=1\n"}, false},
+		{"no .git", map[string]string{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTree(t, dir, tt.files)
+
+			analysis, err := AnalyzeRepository(Repository{Path: dir, Name: "repo"}, logger.New(false))
+			if err != nil {
+				t.Fatalf("AnalyzeRepository() error = %v", err)
+			}
+			if analysis.IsEmpty != tt.want {
+				t.Errorf("IsEmpty = %v, want %v", analysis.IsEmpty, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateAnalysis_IsEmpty(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{".git/HEAD": "ref: refs/heads/main\n"})
+	analysis, err := AnalyzeRepository(Repository{Path: dir, Name: "repo"}, logger.New(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateAnalysis(context.Background(), analysis, []string{"main.go"}, ScanOptions{}); err != nil {
+		t.Fatalf("UpdateAnalysis() error = %v", err)
+	}
+	if analysis.IsEmpty {
+		t.Error("IsEmpty should clear once the repository gains a file")
+	}
+}
@@ -0,0 +1,30 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// Sentinel errors wrapped by the scanner so that callers can tell failure
+// classes apart with errors.Is.
+var (
+	// ErrPathNotFound reports that a path to scan does not exist.
+	ErrPathNotFound = errors.New("path does not exist")
+	// ErrNotReadable reports that a path to scan exists but cannot be read.
+	ErrNotReadable = errors.New("path is not readable")
+	// ErrNotRepo reports that a path named as a repository is not one.
+	ErrNotRepo = errors.New("not a git repository")
+	// ErrNoRepos reports that no repository is left to analyze.
+	ErrNoRepos = errors.New("no repositories to analyze")
+)
+
+// rootError classifies err, reported by the walk for its root, as
+// ErrPathNotFound or ErrNotReadable. Errors below the root are logged and
+// skipped instead, so only a root failure aborts a scan.
+func rootError(root string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrPathNotFound, root)
+	}
+	return fmt.Errorf("%w: %s: %w", ErrNotReadable, root, err)
+}
@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestRootError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"missing", fs.ErrNotExist, ErrPathNotFound},
+		{"wrapped missing", &fs.PathError{Op: "lstat", Path: "/x", Err: fs.ErrNotExist}, ErrPathNotFound},
+		{"permission", &fs.PathError{Op: "open", Path: "/x", Err: fs.ErrPermission}, ErrNotReadable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := rootError("/x", tt.err); !errors.Is(err, tt.want) {
+				t.Errorf("rootError() = %v, want it to wrap %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindGitRepos_MissingRoot(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "nope")
+
+	if _, err := FindGitRepos(missing, logger.New(false)); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("FindGitRepos() error = %v, want ErrPathNotFound", err)
+	}
+	if _, err := AnalyzeRepository(Repository{Path: missing, Name: "nope"}, logger.New(false)); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("AnalyzeRepository() error = %v, want ErrPathNotFound", err)
+	}
+}
+
+func TestFindGitRepos_UnreadableRoot(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permissions are not enforced for root")
+	}
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(dir, 0755) })
+
+	if _, err := FindGitRepos(dir, logger.New(false)); !errors.Is(err, ErrNotReadable) {
+		t.Errorf("FindGitRepos() error = %v, want ErrNotReadable", err)
+	}
+}
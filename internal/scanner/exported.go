@@ -0,0 +1,83 @@
+package scanner
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// goLanguage is the Languages key under which Go files are counted.
+const goLanguage = "Go"
+
+// CountExportedSymbols parses the non-test .go files in repo and counts
+// its exported top-level functions (not methods) and exported types.
+// Directories skipped by analysis and testdata are not read. Files that
+// fail to parse are left out of the counts, and the error reports how
+// many there were; the counts of the remaining files are still returned.
+func CountExportedSymbols(repo Repository) (funcs, types int, err error) {
+	fset := token.NewFileSet()
+	var parseFailures int
+	var firstErr error
+
+	walkErr := filepath.WalkDir(repo.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != repo.Path && (skipAnalysisDir(d.Name(), false) || d.Name() == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, parseErr := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if parseErr != nil {
+			parseFailures++
+			if firstErr == nil {
+				firstErr = parseErr
+			}
+			return nil
+		}
+		f, t := countExported(file)
+		funcs += f
+		types += t
+		return nil
+	})
+	if walkErr != nil {
+		return funcs, types, fmt.Errorf("failed to walk %s for Go sources: %w", repo.Path, walkErr)
+	}
+	if parseFailures > 0 {
+		return funcs, types, fmt.Errorf("failed to parse %d Go file(s): %w", parseFailures, firstErr)
+	}
+	return funcs, types, nil
+}
+
+// countExported counts the exported top-level functions and types
+// declared in file.
+func countExported(file *ast.File) (funcs, types int) {
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil && d.Name.IsExported() {
+				funcs++
+			}
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.IsExported() {
+					types++
+				}
+			}
+		}
+	}
+	return funcs, types
+}
@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// exportedFixture has 3 exported functions (Parse, New, Run), 3 exported
+// types (Config, Mode, Handler), and unexported or test-only symbols that
+// must not be counted.
+var exportedFixture = map[string]string{
+	"go.mod": "module example.com/lib\n\ngo 1.21\n",
+	"lib.go": `package lib
+
+type Config struct{ Name string }
+
+type (
+	Mode    int
+	handler func()
+	Handler interface{ Serve() }
+)
+
+func Parse(s string) Config { return Config{Name: s} }
+
+func New() *Config { return &Config{} }
+
+func (c *Config) Validate() error { return nil }
+
+func helper() {}
+
+var Exported = 1
+`,
+	"cmd/tool/main.go":     "package main\n\nfunc Run() {}\n\nfunc main() { Run() }\n",
+	"lib_test.go":          "package lib\n\nfunc TestParse() {}\n\ntype Fixture struct{}\n",
+	"testdata/sample.go":   "package sample\n\nfunc Sample() {}\n",
+	"vendor/dep/dep.go":    "package dep\n\nfunc Dep() {}\n",
+	"internal/x/x_test.go": "package x\n\nfunc Helper() {}\n",
+}
+
+func TestCountExportedSymbols(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, exportedFixture)
+
+	funcs, types, err := CountExportedSymbols(Repository{Path: dir})
+	if err != nil {
+		t.Fatalf("CountExportedSymbols() error = %v", err)
+	}
+	if funcs != 3 || types != 3 {
+		t.Errorf("CountExportedSymbols() = %d funcs, %d types, want 3 and 3", funcs, types)
+	}
+}
+
+func TestCountExportedSymbols_ParseError(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"ok.go":     "package lib\n\nfunc Good() {}\n",
+		"broken.go": "package lib\n\nfunc Bad( {\n",
+	})
+
+	funcs, _, err := CountExportedSymbols(Repository{Path: dir})
+	if err == nil || !strings.Contains(err.Error(), "failed to parse 1 Go file") {
+		t.Errorf("CountExportedSymbols() error = %v, want a parse failure", err)
+	}
+	if funcs != 1 {
+		t.Errorf("funcs = %d, want 1 from the file that parsed", funcs)
+	}
+}
+
+func TestAnalyzeRepository_ExportedSymbols(t *testing.T) {
+	goDir := t.TempDir()
+	writeTree(t, goDir, exportedFixture)
+	pyDir := t.TempDir()
+	writeTree(t, pyDir, map[string]string{"app.py": "def Main():\n    pass\n"})
+
+	tests := []struct {
+		name         string
+		dir          string
+		funcs, types int
+	}{
+		{"go", goDir, 3, 3},
+		{"python", pyDir, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis, err := AnalyzeRepository(Repository{Path: tt.dir, Name: "repo"}, logger.New(false))
+			if err != nil {
+				t.Fatalf("AnalyzeRepository() error = %v", err)
+			}
+			if analysis.ExportedFuncs != tt.funcs || analysis.ExportedTypes != tt.types {
+				t.Errorf("exported = %d funcs, %d types, want %d and %d", analysis.ExportedFuncs, analysis.ExportedTypes, tt.funcs, tt.types)
+			}
+		})
+	}
+}
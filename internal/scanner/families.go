@@ -0,0 +1,58 @@
+package scanner
+
+// OtherFamily collects languages that have no family in the mapping.
+const OtherFamily = "Other"
+
+// DefaultLanguageFamilies returns the language-to-family mapping used by
+// GroupLanguages. The map is a fresh copy that callers may modify and pass
+// to GroupLanguagesWith.
+func DefaultLanguageFamilies() map[string]string {
+	return map[string]string{
+		"JavaScript": "Web frontend",
+		"TypeScript": "Web frontend",
+		"HTML":       "Web frontend",
+		"CSS":        "Web frontend",
+		"SCSS":       "Web frontend",
+		"LESS":       "Web frontend",
+		"Go":         "Systems",
+		"Rust":       "Systems",
+		"C":          "Systems",
+		"C++":        "Systems",
+		"Java":       "JVM",
+		"Kotlin":     "JVM",
+		"Scala":      "JVM",
+		"C#":         ".NET",
+		"Swift":      "Mobile",
+		"Python":     "Scripting",
+		"Ruby":       "Scripting",
+		"PHP":        "Scripting",
+		"Perl":       "Scripting",
+		"Shell":      "Scripting",
+		"SQL":        "Data and config",
+		"YAML":       "Data and config",
+		"JSON":       "Data and config",
+		"XML":        "Data and config",
+		"Markdown":   "Documentation",
+	}
+}
+
+// GroupLanguages rolls per-language file counts up into language families
+// using DefaultLanguageFamilies.
+func GroupLanguages(langs map[string]int) map[string]int {
+	return GroupLanguagesWith(langs, DefaultLanguageFamilies())
+}
+
+// GroupLanguagesWith rolls per-language file counts up into the families
+// named by families. Languages missing from families count toward
+// OtherFamily.
+func GroupLanguagesWith(langs map[string]int, families map[string]string) map[string]int {
+	grouped := make(map[string]int)
+	for lang, count := range langs {
+		family, ok := families[lang]
+		if !ok {
+			family = OtherFamily
+		}
+		grouped[family] += count
+	}
+	return grouped
+}
@@ -0,0 +1,53 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupLanguages(t *testing.T) {
+	tests := []struct {
+		name  string
+		langs map[string]int
+		want  map[string]int
+	}{
+		{
+			name:  "frontend roll-up",
+			langs: map[string]int{"JavaScript": 4, "TypeScript": 10, "CSS": 3, "Go": 7},
+			want:  map[string]int{"Web frontend": 17, "Systems": 7},
+		},
+		{
+			name:  "unknown languages go to other",
+			langs: map[string]int{"Elixir": 2, "Rust": 1, "C": 1},
+			want:  map[string]int{OtherFamily: 2, "Systems": 2},
+		},
+		{
+			name:  "empty",
+			langs: nil,
+			want:  map[string]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GroupLanguages(tt.langs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GroupLanguages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupLanguagesWith_Override(t *testing.T) {
+	families := DefaultLanguageFamilies()
+	families["TypeScript"] = "Node backend"
+	families["Elixir"] = "BEAM"
+
+	got := GroupLanguagesWith(map[string]int{"TypeScript": 5, "JavaScript": 1, "Elixir": 2}, families)
+	want := map[string]int{"Node backend": 5, "Web frontend": 1, "BEAM": 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupLanguagesWith() = %v, want %v", got, want)
+	}
+	if DefaultLanguageFamilies()["TypeScript"] != "Web frontend" {
+		t.Error("modifying the returned map changed the defaults")
+	}
+}
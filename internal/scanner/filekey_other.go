@@ -0,0 +1,25 @@
+//go:build !unix
+
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// fileKey identifies a directory by its fully resolved path on platforms
+// without inode numbers.
+type fileKey string
+
+// fileKeyOf returns the resolved path of the directory at path.
+func fileKeyOf(path string, _ os.FileInfo) (fileKey, bool) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", false
+	}
+	abs, err := filepath.Abs(resolved)
+	if err != nil {
+		return "", false
+	}
+	return fileKey(abs), true
+}
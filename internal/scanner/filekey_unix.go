@@ -0,0 +1,22 @@
+//go:build unix
+
+package scanner
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileKey identifies a directory by device and inode.
+type fileKey struct {
+	dev, ino uint64
+}
+
+// fileKeyOf returns the device and inode of info.
+func fileKeyOf(_ string, info os.FileInfo) (fileKey, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileKey{}, false
+	}
+	return fileKey{dev: uint64(st.Dev), ino: uint64(st.Ino)}, true
+}
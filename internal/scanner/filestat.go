@@ -0,0 +1,125 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// FileStat is one file's contribution to a RepositoryAnalysis. Keeping it
+// per file lets an analysis be updated incrementally (see UpdateAnalysis).
+type FileStat struct {
+	Ext       string `json:"ext,omitempty"`
+	Language  string `json:"lang,omitempty"`
+	Lines     int    `json:"lines,omitempty"`
+	Binary    bool   `json:"binary,omitempty"`
+	Test      bool   `json:"test,omitempty"`
+	Generated bool   `json:"generated,omitempty"`
+	Markers   int    `json:"markers,omitempty"`
+	Size      int64  `json:"size,omitempty"`
+	// ModTime is the file's modification time in Unix nanoseconds. With
+	// Size it lets ModifiedFiles notice changes git does not report.
+	ModTime int64 `json:"mtime,omitempty"`
+	// Secrets lists the potential secrets found when
+	// ScanOptions.ScanSecrets is set, with their matches redacted.
+	Secrets []SecretFinding `json:"secrets,omitempty"`
+}
+
+// statFile classifies the file at path: its language (by extension, or by
+// shebang for small extensionless text files), whether it is binary, a
+// test, or generated, its line count when it is text in a known language,
+// its TODO/FIXME markers when it is text, and its size. Text files are
+// also scanned for secrets when opts.ScanSecrets is set. Languages left out
+// by opts.OnlyLanguages are reported as OtherLanguage.
+func statFile(path string, info os.FileInfo, opts ScanOptions) FileStat {
+	ext := filepath.Ext(path)
+	stat := FileStat{
+		Ext:      ext,
+		Language: opts.language(ext),
+		Binary:   isBinaryFile(path),
+		Test:     isTestFile(info.Name()),
+		Size:     info.Size(),
+		ModTime:  info.ModTime().UnixNano(),
+	}
+	if stat.Binary {
+		opts.restrictLanguage(&stat)
+		return stat
+	}
+
+	firstLine := readFirstLine(path)
+	// Extensionless scripts are identified by their shebang.
+	if ext == "" && info.Size() <= shebangMaxSize {
+		stat.Language = shebangLanguage(firstLine)
+	}
+	stat.Generated = IsGenerated(path, firstLine)
+	stat.Markers = countMarkers(path)
+	if opts.ScanSecrets {
+		stat.Secrets = secretsInFile(path)
+	}
+	if stat.Language != "" {
+		stat.Lines = countLines(path)
+	}
+	opts.restrictLanguage(&stat)
+	return stat
+}
+
+// addFile adds a file's contribution to the counts when delta is 1, or
+// removes it when delta is -1. Counts that drop to zero are deleted from
+// the per-language and per-extension maps.
+func (a *RepositoryAnalysis) addFile(s FileStat, delta int) {
+	a.TotalFiles += delta
+	if s.Ext != "" {
+		addCount(&a.FileTypes, s.Ext, delta)
+		if s.Language == "" {
+			addCount(&a.UnclassifiedTypes, s.Ext, delta)
+		}
+	}
+	if s.Language != "" {
+		addCount(&a.Languages, s.Language, delta)
+	}
+
+	switch {
+	case s.Test:
+		a.TestFiles += delta
+	case isCodeLanguage(s.Language):
+		a.CodeFiles += delta
+	}
+
+	if s.Binary {
+		a.BinaryFiles += delta
+		return
+	}
+	a.TextFiles += delta
+	a.Markers += delta * s.Markers
+	if s.Generated {
+		a.GeneratedFiles += delta
+		if s.Language != "" {
+			addCount(&a.GeneratedByLanguage, s.Language, delta)
+		}
+	}
+	if s.Language != "" {
+		addCount(&a.LinesByLanguage, s.Language, delta*s.Lines)
+		a.TotalLines += delta * s.Lines
+	}
+}
+
+// addCount adds delta to (*m)[key], allocating the map if needed and
+// deleting the key when it reaches zero.
+func addCount(m *map[string]int, key string, delta int) {
+	if *m == nil {
+		*m = make(map[string]int)
+	}
+	(*m)[key] += delta
+	if (*m)[key] == 0 {
+		delete(*m, key)
+	}
+}
+
+// relativeTo returns path relative to root with forward slashes, or path
+// itself when it is not below root.
+func relativeTo(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,171 @@
+package scanner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// goFrameworks maps Go module paths to framework names.
+var goFrameworks = map[string]string{
+	"github.com/gin-gonic/gin": "Gin",
+	"github.com/labstack/echo": "Echo",
+	"github.com/gofiber/fiber": "Fiber",
+	"github.com/go-chi/chi":    "Chi",
+	"github.com/gorilla/mux":   "Gorilla Mux",
+	"github.com/spf13/cobra":   "Cobra",
+	"google.golang.org/grpc":   "gRPC",
+}
+
+// npmFrameworks maps npm package names to framework names.
+var npmFrameworks = map[string]string{
+	"react":         "React",
+	"vue":           "Vue",
+	"@angular/core": "Angular",
+	"next":          "Next.js",
+	"express":       "Express",
+	"@nestjs/core":  "NestJS",
+	"svelte":        "Svelte",
+}
+
+// pythonFrameworks maps Python distribution names to framework names.
+var pythonFrameworks = map[string]string{
+	"django":  "Django",
+	"flask":   "Flask",
+	"fastapi": "FastAPI",
+}
+
+// jvmBuildFiles are checked for Spring Boot.
+var jvmBuildFiles = []string{"pom.xml", "build.gradle", "build.gradle.kts"}
+
+// DetectFrameworks inspects the dependency manifests at the root of repo
+// (go.mod, package.json, requirements.txt, pyproject.toml, and JVM build
+// files) and returns the recognized frameworks, sorted.
+func DetectFrameworks(repo Repository) []string {
+	found := make(map[string]bool)
+
+	if data, err := os.ReadFile(filepath.Join(repo.Path, "go.mod")); err == nil {
+		content := string(data)
+		for module, name := range goFrameworks {
+			if strings.Contains(content, module) {
+				found[name] = true
+			}
+		}
+	}
+
+	for _, dep := range npmDependencies(filepath.Join(repo.Path, "package.json")) {
+		if name, ok := npmFrameworks[dep]; ok {
+			found[name] = true
+		}
+	}
+
+	for _, dep := range pythonRequirements(filepath.Join(repo.Path, "requirements.txt")) {
+		if name, ok := pythonFrameworks[dep]; ok {
+			found[name] = true
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(repo.Path, "pyproject.toml")); err == nil {
+		content := strings.ToLower(string(data))
+		for dist, name := range pythonFrameworks {
+			if strings.Contains(content, `"`+dist) {
+				found[name] = true
+			}
+		}
+	}
+
+	for _, file := range jvmBuildFiles {
+		if data, err := os.ReadFile(filepath.Join(repo.Path, file)); err == nil && strings.Contains(string(data), "spring-boot") {
+			found["Spring Boot"] = true
+		}
+	}
+
+	frameworks := make([]string, 0, len(found))
+	for name := range found {
+		frameworks = append(frameworks, name)
+	}
+	sort.Strings(frameworks)
+	return frameworks
+}
+
+// npmDependencies returns the runtime and development dependency names
+// declared in the package.json at path.
+func npmDependencies(path string) []string {
+	var deps []string
+	for name := range npmDependencyVersions(path) {
+		deps = append(deps, name)
+	}
+	return deps
+}
+
+// npmDependencyVersions returns the runtime and development dependencies
+// declared in the package.json at path with their version ranges.
+func npmDependencyVersions(path string) map[string]string {
+	deps := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return deps
+	}
+
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return deps
+	}
+
+	for name, version := range pkg.DevDependencies {
+		deps[name] = version
+	}
+	for name, version := range pkg.Dependencies {
+		deps[name] = version
+	}
+	return deps
+}
+
+// pythonRequirements returns the lowercased distribution names listed in a
+// requirements.txt file, ignoring comments, options, and version specifiers.
+func pythonRequirements(path string) []string {
+	var deps []string
+	for name := range pythonRequirementVersions(path) {
+		deps = append(deps, name)
+	}
+	return deps
+}
+
+// pythonRequirementVersions returns the lowercased distribution names listed
+// in a requirements.txt file mapped to their version specifier, such as
+// "==4.2.1" or ">=2.0", or "" when unpinned. Comments and options are
+// ignored.
+func pythonRequirementVersions(path string) map[string]string {
+	deps := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return deps
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		if i := strings.Index(line, ";"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		name, version := line, ""
+		if i := strings.IndexAny(line, "=<>~![ "); i >= 0 {
+			name, version = line[:i], line[i:]
+		}
+		// Drop extras such as [async] between the name and the version.
+		if strings.HasPrefix(version, "[") {
+			if end := strings.Index(version, "]"); end >= 0 {
+				version = version[end+1:]
+			}
+		}
+		version = strings.TrimSpace(version)
+		deps[strings.ToLower(name)] = version
+	}
+	return deps
+}
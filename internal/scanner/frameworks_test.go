@@ -0,0 +1,62 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectFrameworks(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name: "go module",
+			files: map[string]string{"go.mod": `module example.com/api
+
+require (
+	github.com/gin-gonic/gin v1.9.1
+	github.com/spf13/cobra v1.8.0
+)
+`},
+			want: []string{"Cobra", "Gin"},
+		},
+		{
+			name: "npm dependencies",
+			files: map[string]string{"package.json": `{
+  "dependencies": {"react": "^18.0.0", "lodash": "^4.0.0"},
+  "devDependencies": {"next": "14.0.0"}
+}`},
+			want: []string{"Next.js", "React"},
+		},
+		{
+			name: "python requirements and pyproject",
+			files: map[string]string{
+				"requirements.txt": "# web\nFlask==3.0.0\nrequests>=2\n",
+				"pyproject.toml":   "[project]\ndependencies = [\"fastapi>=0.100\"]\n",
+			},
+			want: []string{"FastAPI", "Flask"},
+		},
+		{
+			name:  "spring boot",
+			files: map[string]string{"pom.xml": "<artifactId>spring-boot-starter-web</artifactId>"},
+			want:  []string{"Spring Boot"},
+		},
+		{
+			name:  "none",
+			files: map[string]string{"main.go": "package main\n"},
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTree(t, dir, tt.files)
+			if got := DetectFrameworks(Repository{Path: dir}); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectFrameworks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// generatedSuffixes are file name endings used by code generators and
+// minifiers.
+var generatedSuffixes = []string{
+	".pb.go", ".pb.gw.go", "_gen.go", "_generated.go",
+	"_pb2.py", "_pb2_grpc.py",
+	".min.js", ".min.css",
+}
+
+// IsGenerated reports whether the file at path is generated rather than
+// hand-written, judging by its name or by a first line following the
+// "Code generated ... DO NOT EDIT." convention.
+func IsGenerated(path string, firstLine string) bool {
+	name := filepath.Base(path)
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(name, suffix) && len(name) > len(suffix) {
+			return true
+		}
+	}
+	return hasGeneratedHeader(firstLine)
+}
+
+// hasGeneratedHeader reports whether line is a generated-code marker such
+// as "// Code generated by protoc-gen-go. DO NOT EDIT.". Any comment leader
+// is accepted so the marker is recognized outside Go as well.
+func hasGeneratedHeader(line string) bool {
+	i := strings.Index(line, "Code generated ")
+	return i >= 0 && strings.Contains(line[i:], "DO NOT EDIT")
+}
+
+// readFirstLine returns the first line of the file at path, without the
+// line ending, or "" when it cannot be read.
+func readFirstLine(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	r := bufio.NewReaderSize(f, sniffLen)
+	line, _ := r.ReadSlice('\n')
+	return strings.TrimRight(string(line), "\r\n")
+}
+
+// PrimaryLanguageExcludingGenerated returns the most common language once
+// generated files are discounted, so that, say, a tree of protobuf stubs
+// does not outweigh the hand-written code. Ties go to the name that sorts
+// first.
+func (a *RepositoryAnalysis) PrimaryLanguageExcludingGenerated() string {
+	var maxLang string
+	var maxCount int
+
+	for lang, count := range a.Languages {
+		if lang == OtherLanguage {
+			continue
+		}
+		count -= a.GeneratedByLanguage[lang]
+		if count > maxCount || (count == maxCount && count > 0 && lang < maxLang) {
+			maxCount = count
+			maxLang = lang
+		}
+	}
+
+	return maxLang
+}
@@ -0,0 +1,92 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestIsGenerated(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		firstLine string
+		want      bool
+	}{
+		{"protobuf stub", "api/v1/service.pb.go", "", true},
+		{"grpc gateway", "api/service.pb.gw.go", "", true},
+		{"gen suffix", "internal/mocks_gen.go", "", true},
+		{"minified js", "static/app.min.js", "", true},
+		{"python protobuf", "proto/msg_pb2.py", "", true},
+		{"go header", "wire.go", "// Code generated by Wire. DO NOT EDIT.", true},
+		{"stringer header", "kind_string.go", "// Code generated by \"stringer -type=Kind\"; DO NOT EDIT.", true},
+		{"hash comment header", "schema.py", "# Code generated by tool. DO NOT EDIT.", true},
+		{"handwritten", "main.go", "package main", false},
+		{"header mention without marker", "doc.go", "// Code generated files are skipped", false},
+		{"bare suffix", ".pb.go", "", false},
+		{"pb in directory only", "pb.go/main.go", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsGenerated(tt.path, tt.firstLine); got != tt.want {
+				t.Errorf("IsGenerated(%q, %q) = %v, want %v", tt.path, tt.firstLine, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepository_GeneratedFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"main.py":              "print('hi')\n",
+		"api/a.pb.go":          "package api\n",
+		"api/b.pb.go":          "package api\n",
+		"api/wire_gen.go":      "package api\n",
+		"api/zz_deepcopy.go":   "// Code generated by controller-gen. DO NOT EDIT.\n\npackage api\n",
+		"api/handwritten.go":   "package api\n",
+		"static/bundle.min.js": "var a=1;\n",
+	})
+
+	analysis, err := AnalyzeRepositoryWithOptions(context.Background(), Repository{Path: dir, Name: "gen"}, ScanOptions{}, logger.New(false))
+	if err != nil {
+		t.Fatalf("AnalyzeRepositoryWithOptions() error = %v", err)
+	}
+	if analysis.GeneratedFiles != 5 {
+		t.Errorf("GeneratedFiles = %d, want 5", analysis.GeneratedFiles)
+	}
+	if got := analysis.GeneratedByLanguage["Go"]; got != 4 {
+		t.Errorf("GeneratedByLanguage[Go] = %d, want 4", got)
+	}
+	if got := analysis.PrimaryLanguage(); got != "Go" {
+		t.Errorf("PrimaryLanguage() = %q, want Go", got)
+	}
+	// One hand-written Go file ties one Python file; Go sorts first.
+	if got := analysis.PrimaryLanguageExcludingGenerated(); got != "Go" {
+		t.Errorf("PrimaryLanguageExcludingGenerated() = %q, want Go", got)
+	}
+}
+
+func TestPrimaryLanguageExcludingGenerated(t *testing.T) {
+	tests := []struct {
+		name      string
+		languages map[string]int
+		generated map[string]int
+		want      string
+	}{
+		{"no generated files", map[string]int{"Go": 3, "Python": 1}, nil, "Go"},
+		{"generated stubs outweigh code", map[string]int{"Go": 10, "Python": 4}, map[string]int{"Go": 8}, "Python"},
+		{"everything generated", map[string]int{"Go": 2}, map[string]int{"Go": 2}, ""},
+		{"empty", nil, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &RepositoryAnalysis{Languages: tt.languages, GeneratedByLanguage: tt.generated}
+			if got := a.PrimaryLanguageExcludingGenerated(); got != tt.want {
+				t.Errorf("PrimaryLanguageExcludingGenerated() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
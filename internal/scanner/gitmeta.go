@@ -0,0 +1,177 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// headRefPrefix introduces a symbolic ref in .git/HEAD.
+const headRefPrefix = "ref: refs/heads/"
+
+// readDefaultBranch returns the branch checked out in repoPath according to
+// .git/HEAD. A detached HEAD or an unreadable file yields "".
+func readDefaultBranch(repoPath string) string {
+	data, err := os.ReadFile(filepath.Join(repoPath, ".git", "HEAD"))
+	if err != nil {
+		return ""
+	}
+	head := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(head, headRefPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(head, headRefPrefix)
+}
+
+// readRemoteURL returns the URL of the "origin" remote in .git/config, or of
+// the first remote declared when there is no origin. Credentials embedded in
+// the URL are removed. A missing config or remote yields "".
+func readRemoteURL(repoPath string) string {
+	data, err := os.ReadFile(filepath.Join(repoPath, ".git", "config"))
+	if err != nil {
+		return ""
+	}
+
+	var first, origin, remote string
+	inRemote := false
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := stripConfigComment(strings.TrimSpace(sc.Text()))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			kind, name := parseConfigSection(line)
+			inRemote = kind == "remote"
+			remote = name
+			continue
+		}
+
+		if !inRemote {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.ToLower(strings.TrimSpace(key)) != "url" {
+			continue
+		}
+		u := unquoteConfigValue(value)
+		if first == "" {
+			first = u
+		}
+		if remote == "origin" && origin == "" {
+			origin = u
+		}
+	}
+
+	if origin != "" {
+		return redactURLCredentials(origin)
+	}
+	return redactURLCredentials(first)
+}
+
+// parseConfigSection splits a `[kind "name"]` git-config section header into
+// its lowercased kind and unquoted subsection name.
+func parseConfigSection(line string) (kind, name string) {
+	section := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(line, "["), "]"))
+	kind, rest, _ := strings.Cut(section, " ")
+	return strings.ToLower(kind), strings.Trim(strings.TrimSpace(rest), `"`)
+}
+
+// redactURLCredentials strips any user:password from a URL-style remote so
+// tokens never reach generated prompts. scp-style remotes are returned as-is.
+func redactURLCredentials(remote string) string {
+	if !strings.Contains(remote, "://") {
+		return remote
+	}
+	u, err := url.Parse(remote)
+	if err != nil || u.User == nil {
+		return remote
+	}
+	u.User = nil
+	return u.String()
+}
+
+// RemoteName derives a repository name such as "org/repo" from a remote
+// URL: the last two segments of its path, without a .git suffix. URL-style
+// (https://host/org/repo.git), scp-style (git@host:org/repo.git), and
+// local-path remotes are understood. A remote with a single path segment
+// yields that segment, and one with none yields "".
+func RemoteName(remote string) string {
+	p := strings.TrimSpace(remote)
+	if i := strings.Index(p, "://"); i >= 0 {
+		if u, err := url.Parse(p); err == nil {
+			p = u.Path
+		} else {
+			p = p[i+3:]
+		}
+	} else if i := strings.Index(p, ":"); i > 1 && !strings.ContainsAny(p[:i], `/\`) {
+		// scp-style host:path; a one-letter prefix is a Windows drive.
+		p = p[i+1:]
+	}
+
+	var segs []string
+	for _, seg := range strings.FieldsFunc(p, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if seg != "." && seg != ".." {
+			segs = append(segs, seg)
+		}
+	}
+	if len(segs) == 0 {
+		return ""
+	}
+	segs[len(segs)-1] = strings.TrimSuffix(segs[len(segs)-1], ".git")
+	if segs[len(segs)-1] == "" {
+		segs = segs[:len(segs)-1]
+	}
+	if len(segs) > 2 {
+		segs = segs[len(segs)-2:]
+	}
+	return strings.Join(segs, "/")
+}
+
+// StaleAfter is how long a repository can go without commits before it is
+// considered stale.
+const StaleAfter = 365 * 24 * time.Hour
+
+// readLastCommit returns the hash and time of the most recent entry in
+// .git/logs/HEAD. A missing or malformed log yields zero values.
+func readLastCommit(repoPath string) (string, time.Time) {
+	data, err := os.ReadFile(filepath.Join(repoPath, ".git", "logs", "HEAD"))
+	if err != nil {
+		return "", time.Time{}
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	return parseReflogLine(lines[len(lines)-1])
+}
+
+// parseReflogLine parses a reflog entry of the form
+// "<old> <new> Name <email> <unix-seconds> <tz>\t<message>".
+func parseReflogLine(line string) (string, time.Time) {
+	entry, _, _ := strings.Cut(line, "\t")
+	fields := strings.Fields(entry)
+	if len(fields) < 4 {
+		return "", time.Time{}
+	}
+
+	// The committer identity may contain spaces, so read the timestamp
+	// from the end of the entry.
+	secs, err := strconv.ParseInt(fields[len(fields)-2], 10, 64)
+	if err != nil {
+		return "", time.Time{}
+	}
+	return fields[1], time.Unix(secs, 0).UTC()
+}
+
+// IsStale reports whether the repository's last commit is older than
+// StaleAfter as of now. Repositories with no known commit are not stale.
+func (r Repository) IsStale(now time.Time) bool {
+	return !r.LastCommitDate.IsZero() && now.Sub(r.LastCommitDate) > StaleAfter
+}
@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GoModule is a Go module found within a repository.
+type GoModule struct {
+	// Path is the module path declared by the module directive.
+	Path string `json:"path"`
+	// Dir is the slash-separated directory holding go.mod, relative to
+	// the repository; "." for the repository root.
+	Dir string `json:"dir"`
+	// GoVersion is the version declared by the go directive, or "" when
+	// there is none.
+	GoVersion string `json:"go_version,omitempty"`
+}
+
+// FindGoModules returns every Go module in repo, one per go.mod, sorted by
+// directory. Directories skipped by analysis and testdata are not
+// searched, and go.mod files that cannot be read are left out.
+func FindGoModules(repo Repository) []GoModule {
+	var modules []GoModule
+	_ = filepath.WalkDir(repo.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != repo.Path && (skipAnalysisDir(d.Name(), false) || d.Name() == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != "go.mod" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		mod := parseGoMod(string(data))
+		mod.Dir = relativeTo(repo.Path, filepath.Dir(path))
+		modules = append(modules, mod)
+		return nil
+	})
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Dir < modules[j].Dir })
+	return modules
+}
+
+// parseGoMod reads the module and go directives from go.mod content.
+func parseGoMod(content string) GoModule {
+	var mod GoModule
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "module":
+			mod.Path = fields[1]
+			if unquoted, err := strconv.Unquote(fields[1]); err == nil {
+				mod.Path = unquoted
+			}
+		case "go":
+			mod.GoVersion = fields[1]
+		}
+	}
+	return mod
+}
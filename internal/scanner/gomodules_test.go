@@ -0,0 +1,54 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestFindGoModules(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"go.mod":                     "module example.com/app\n\ngo 1.21\n\nrequire example.com/lib v1.0.0\n",
+		"main.go":                    "package main\n",
+		"tools/lint/go.mod":          "// Linters, versioned separately.\nmodule \"example.com/app/tools/lint\"\ngo 1.22 // toolchain\n",
+		"tools/lint/testdata/go.mod": "module fixture\n",
+		"vendor/example.com/go.mod":  "module vendored\n",
+		"legacy/go.mod":              "module example.com/legacy\n",
+	})
+
+	want := []GoModule{
+		{Path: "example.com/app", Dir: ".", GoVersion: "1.21"},
+		{Path: "example.com/legacy", Dir: "legacy"},
+		{Path: "example.com/app/tools/lint", Dir: "tools/lint", GoVersion: "1.22"},
+	}
+	if got := FindGoModules(Repository{Path: dir}); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindGoModules() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindGoModules_None(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"main.py": "print()\n"})
+	if got := FindGoModules(Repository{Path: dir}); len(got) != 0 {
+		t.Errorf("FindGoModules() = %+v, want none", got)
+	}
+}
+
+func TestAnalyzeRepository_GoModules(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"go.mod":         "module example.com/app\ngo 1.21\n",
+		"main.go":        "package main\n",
+		"sub/go.mod":     "module example.com/sub\ngo 1.22\n",
+		"sub/lib/lib.go": "package lib\n",
+	})
+	analysis, err := AnalyzeRepository(Repository{Path: dir, Name: "app"}, logger.New(false))
+	if err != nil {
+		t.Fatalf("AnalyzeRepository() error = %v", err)
+	}
+	if len(analysis.GoModules) != 2 || analysis.GoModules[1].Dir != "sub" {
+		t.Errorf("GoModules = %+v, want the root and sub modules", analysis.GoModules)
+	}
+}
@@ -0,0 +1,140 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path"
+	"strings"
+)
+
+// IgnoreFileName is the per-repository file of gitignore-style patterns
+// naming paths to leave out of analysis without affecting git.
+const IgnoreFileName = ".codebasereviewerignore"
+
+// ignoreRule is one pattern line of an ignore file.
+type ignoreRule struct {
+	segments []string
+	negate   bool
+	dirOnly  bool
+	// anchored rules match from the repository root; others match the
+	// trailing segments of a path at any depth.
+	anchored bool
+}
+
+// ignoreMatcher applies ignore rules in order; the last matching rule
+// decides. A nil matcher ignores nothing.
+type ignoreMatcher struct {
+	rules []ignoreRule
+}
+
+// loadIgnoreFile reads the patterns in path. A missing file yields a nil
+// matcher and no error.
+func loadIgnoreFile(path string) (*ignoreMatcher, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseIgnorePatterns(data), nil
+}
+
+// parseIgnorePatterns parses gitignore syntax: blank lines and # comments
+// are skipped, ! negates, a trailing / matches only directories, a / at
+// the start or middle anchors the pattern to the root, and ** matches any
+// number of directories.
+func parseIgnorePatterns(data []byte) *ignoreMatcher {
+	m := &ignoreMatcher{}
+	lines := bufio.NewScanner(bytes.NewReader(data))
+	for lines.Scan() {
+		line := strings.TrimRight(lines.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		rule.anchored = strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		if line == "" {
+			continue
+		}
+		rule.segments = strings.Split(line, "/")
+		m.rules = append(m.rules, rule)
+	}
+	return m
+}
+
+// matches reports whether the slash-separated relative path rel is ignored.
+func (m *ignoreMatcher) matches(rel string, isDir bool) bool {
+	if m == nil {
+		return false
+	}
+	segments := strings.Split(rel, "/")
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.match(segments) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// matchesPathOrParent reports whether rel or any directory above it is
+// ignored.
+func (m *ignoreMatcher) matchesPathOrParent(rel string) bool {
+	if m == nil {
+		return false
+	}
+	segments := strings.Split(rel, "/")
+	for i := 1; i < len(segments); i++ {
+		if m.matches(strings.Join(segments[:i], "/"), true) {
+			return true
+		}
+	}
+	return m.matches(rel, false)
+}
+
+func (r ignoreRule) match(segments []string) bool {
+	if r.anchored {
+		return matchSegments(r.segments, segments)
+	}
+	for start := range segments {
+		if matchSegments(r.segments, segments[start:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches path segments against pattern segments, where a
+// "**" pattern segment matches zero or more path segments.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for skip := 0; skip <= len(segments); skip++ {
+			if matchSegments(pattern[1:], segments[skip:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	ok, err := path.Match(pattern[0], segments[0])
+	return err == nil && ok && matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	m := parseIgnorePatterns([]byte(`# fixtures and generated code
+*.snap
+/scratch
+testdata/
+docs/**/draft-*.md
+!keep.snap
+`))
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"a.snap", false, true},
+		{"pkg/deep/b.snap", false, true},
+		{"keep.snap", false, false},
+		{"scratch", true, true},
+		{"pkg/scratch", true, false},
+		{"testdata", true, true},
+		{"pkg/testdata", true, true},
+		{"testdata", false, false},
+		{"docs/draft-1.md", false, true},
+		{"docs/a/b/draft-2.md", false, true},
+		{"docs/final.md", false, false},
+		{"main.go", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := m.matches(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("matches(%q, dir=%v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+			}
+		})
+	}
+
+	if !m.matchesPathOrParent("pkg/testdata/golden.txt") {
+		t.Error("matchesPathOrParent() should match a file under an ignored directory")
+	}
+	var none *ignoreMatcher
+	if none.matches("a.snap", false) {
+		t.Error("nil matcher should ignore nothing")
+	}
+}
+
+func TestAnalyzeRepository_CustomIgnoreFile(t *testing.T) {
+	files := map[string]string{
+		"main.go":               "package main\n",
+		"fixtures/big.json":     "{}\n",
+		"fixtures/more.json":    "{}\n",
+		"ui/snapshot.snap":      "snap\n",
+		".gitignore":            "*.log\n",
+		"notes.log":             "not ignored: .gitignore is not consulted\n",
+		"internal/app/app.go":   "package app\n",
+		"internal/app/app.snap": "snap\n",
+	}
+
+	tests := []struct {
+		name        string
+		ignore      string
+		wantFiles   int
+		wantIgnored int
+	}{
+		{"no ignore file", "", 8, 0},
+		{"patterns only in the custom file", "fixtures/\n*.snap\n", 4, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTree(t, dir, files)
+			if tt.ignore != "" {
+				writeTree(t, dir, map[string]string{IgnoreFileName: tt.ignore})
+				tt.wantFiles++ // the ignore file itself
+			}
+
+			analysis, err := AnalyzeRepositoryWithOptions(context.Background(), Repository{Path: dir, Name: "app"}, ScanOptions{}, logger.New(false))
+			if err != nil {
+				t.Fatalf("AnalyzeRepositoryWithOptions() error = %v", err)
+			}
+			if analysis.TotalFiles != tt.wantFiles {
+				t.Errorf("TotalFiles = %d, want %d", analysis.TotalFiles, tt.wantFiles)
+			}
+			if analysis.IgnoredFiles != tt.wantIgnored {
+				t.Errorf("IgnoredFiles = %d, want %d", analysis.IgnoredFiles, tt.wantIgnored)
+			}
+			if _, ok := analysis.Files["fixtures/big.json"]; ok == (tt.ignore != "") {
+				t.Errorf("fixtures/big.json counted = %v with ignore %q", ok, tt.ignore)
+			}
+		})
+	}
+}
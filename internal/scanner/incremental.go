@@ -0,0 +1,137 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ChangedFiles lists the files in repo that differ from its last commit
+// according to `git status`: modified, added, deleted, renamed (both the
+// old and new path), and untracked files. Paths are slash-separated and
+// relative to the repository. It requires the git binary.
+func ChangedFiles(repo Repository) ([]string, error) {
+	cmd := exec.Command("git", "-C", repo.Path, "status", "--porcelain", "-z", "--untracked-files=all")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git status failed in %s: %w: %s", repo.Path, err, strings.TrimSpace(stderr.String()))
+	}
+	return parsePorcelain(out), nil
+}
+
+// parsePorcelain extracts the paths from `git status --porcelain -z`
+// output. Each entry is "XY path"; renames and copies are followed by a
+// second NUL-terminated entry holding the original path.
+func parsePorcelain(out []byte) []string {
+	var paths []string
+	entries := strings.Split(string(out), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		status, path := entry[:2], entry[3:]
+		paths = append(paths, path)
+		if strings.ContainsAny(status, "RC") && i+1 < len(entries) {
+			i++
+			if entries[i] != "" {
+				paths = append(paths, entries[i])
+			}
+		}
+	}
+	return paths
+}
+
+// UpdateAnalysis brings analysis, produced by AnalyzeRepositoryWithOptions,
+// up to date with the files listed in changed (slash-separated paths
+// relative to the repository, as returned by ChangedFiles). Each listed
+// file's previous contribution is removed and, when the file still exists,
+// it is re-analyzed unless IgnoreFileName now excludes it. Files under
+// directories that opts skips are ignored. IsEmpty, large files, exported
+// Go symbol counts, tooling, frameworks, services, docs, and dependencies
+// are re-detected. It stops with ctx.Err() when ctx is cancelled.
+func UpdateAnalysis(ctx context.Context, analysis *RepositoryAnalysis, changed []string, opts ScanOptions) error {
+	if analysis.Files == nil {
+		return errors.New("analysis has no per-file data to update")
+	}
+	root := analysis.Repository.Path
+	ignore, _ := loadIgnoreFile(filepath.Join(root, IgnoreFileName))
+	for _, rel := range changed {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if skipsPath(rel, opts) {
+			continue
+		}
+		if old, ok := analysis.Files[rel]; ok {
+			analysis.addFile(old, -1)
+			delete(analysis.Files, rel)
+		}
+		if ignore.matchesPathOrParent(rel) {
+			continue
+		}
+
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			// Deleted, or not a regular file we would have counted.
+			continue
+		}
+		stat := statFile(path, info, opts)
+		analysis.addFile(stat, 1)
+		analysis.Files[rel] = stat
+	}
+
+	analysis.IsEmpty = isEmptyRepo(root)
+	analysis.LargeFiles = findLargeFiles(analysis.Files, opts.largeFileThreshold())
+	analysis.Secrets = collectSecrets(analysis.Files)
+	analysis.ExportedFuncs, analysis.ExportedTypes = 0, 0
+	analysis.GoModules = nil
+	if analysis.Languages[goLanguage] > 0 {
+		// Parse failures leave partial counts, as in a full analysis.
+		analysis.ExportedFuncs, analysis.ExportedTypes, _ = CountExportedSymbols(analysis.Repository)
+		analysis.GoModules = FindGoModules(analysis.Repository)
+	}
+	analysis.Tooling = DetectTooling(analysis.Repository)
+	analysis.Frameworks = DetectFrameworks(analysis.Repository)
+	analysis.Services = DetectServices(analysis.Repository)
+	analysis.Docs = InventoryDocs(analysis.Repository)
+	analysis.Dependencies = DependencyVersions(analysis.Repository)
+	return nil
+}
+
+// ModifiedFiles lists, sorted, the files recorded in analysis.Files whose
+// size or modification time no longer matches the file on disk, including
+// files since deleted. It catches what ChangedFiles cannot, such as a
+// deleted untracked file or an edit reverted to the committed content.
+func ModifiedFiles(analysis *RepositoryAnalysis) []string {
+	var paths []string
+	for rel, stat := range analysis.Files {
+		info, err := os.Stat(filepath.Join(analysis.Repository.Path, filepath.FromSlash(rel)))
+		if err != nil || info.Size() != stat.Size || info.ModTime().UnixNano() != stat.ModTime {
+			paths = append(paths, rel)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// skipsPath reports whether any directory along the slash-separated
+// relative path rel would be skipped by a full analysis.
+func skipsPath(rel string, opts ScanOptions) bool {
+	dirs := strings.Split(rel, "/")
+	for _, dir := range dirs[:len(dirs)-1] {
+		if opts.SkipsDir(dir) {
+			return true
+		}
+	}
+	return false
+}
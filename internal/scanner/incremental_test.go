@@ -0,0 +1,168 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestParsePorcelain(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"modified and untracked", " M main.go\x00?? docs/new.md\x00", []string{"main.go", "docs/new.md"}},
+		{"staged add and delete", "A  a.go\x00D  b.go\x00", []string{"a.go", "b.go"}},
+		{"rename includes origin", "R  new.go\x00old.go\x00 M x.py\x00", []string{"new.go", "old.go", "x.py"}},
+		{"path with spaces", " M my file.txt\x00", []string{"my file.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePorcelain([]byte(tt.out)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePorcelain(%q) = %q, want %q", tt.out, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateAnalysis_MatchesFullScan(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"main.go":           "package main\n\nfunc main() {}\n",
+		"main_test.go":      "package main\n",
+		"lib/util.py":       "x = 1\n",
+		"api/a.pb.go":       "package api\n",
+		"node_modules/x.js": "var x;\n",
+	})
+	repo := Repository{Path: dir, Name: "inc"}
+	log := logger.New(false)
+
+	analysis, err := AnalyzeRepositoryWithOptions(context.Background(), repo, ScanOptions{}, log)
+	if err != nil {
+		t.Fatalf("AnalyzeRepositoryWithOptions() error = %v", err)
+	}
+
+	// Simulate a working tree change: edit, add, delete, and touch an
+	// ignored directory.
+	writeTree(t, dir, map[string]string{
+		"main.go":           "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println() }\n",
+		"web/app.ts":        "export const a = 1;\n",
+		"node_modules/y.js": "var y;\n",
+	})
+	if err := os.Remove(filepath.Join(dir, "lib", "util.py")); err != nil {
+		t.Fatal(err)
+	}
+	changed := []string{"main.go", "web/app.ts", "lib/util.py", "node_modules/y.js"}
+
+	if err := UpdateAnalysis(context.Background(), analysis, changed, ScanOptions{}); err != nil {
+		t.Fatalf("UpdateAnalysis() error = %v", err)
+	}
+
+	want, err := AnalyzeRepositoryWithOptions(context.Background(), repo, ScanOptions{}, log)
+	if err != nil {
+		t.Fatalf("AnalyzeRepositoryWithOptions() error = %v", err)
+	}
+	if !reflect.DeepEqual(analysis, want) {
+		t.Errorf("UpdateAnalysis() = %+v, want %+v", analysis, want)
+	}
+	if _, ok := analysis.Languages["Python"]; ok {
+		t.Errorf("Languages still has Python after its only file was deleted: %v", analysis.Languages)
+	}
+}
+
+func TestUpdateAnalysis_NoFileData(t *testing.T) {
+	analysis := &RepositoryAnalysis{Repository: Repository{Path: t.TempDir()}}
+	if err := UpdateAnalysis(context.Background(), analysis, []string{"a.go"}, ScanOptions{}); err == nil {
+		t.Error("UpdateAnalysis() without per-file data error = nil, want error")
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	writeTree(t, dir, map[string]string{
+		"kept.go":    "package a\n",
+		"edited.go":  "package a\n",
+		"removed.go": "package a\n",
+	})
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-qm", "initial")
+
+	writeTree(t, dir, map[string]string{
+		"edited.go":    "package a\n\nvar x = 1\n",
+		"sub/added.go": "package sub\n",
+	})
+	if err := os.Remove(filepath.Join(dir, "removed.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ChangedFiles(Repository{Path: dir})
+	if err != nil {
+		t.Fatalf("ChangedFiles() error = %v", err)
+	}
+	want := map[string]bool{"edited.go": true, "removed.go": true, "sub/added.go": true}
+	if len(got) != len(want) {
+		t.Fatalf("ChangedFiles() = %q, want %d entries", got, len(want))
+	}
+	for _, path := range got {
+		if !want[path] {
+			t.Errorf("ChangedFiles() includes unexpected %q", path)
+		}
+	}
+}
+
+func TestChangedFiles_NotARepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	if _, err := ChangedFiles(Repository{Path: t.TempDir()}); err == nil {
+		t.Error("ChangedFiles() outside a repository error = nil, want error")
+	}
+}
+
+func TestModifiedFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"same.go":    "package main\n",
+		"edited.go":  "package main\n",
+		"touched.go": "package main\n",
+		"gone.go":    "package main\n",
+	})
+	analysis, err := AnalyzeRepositoryWithOptions(context.Background(), Repository{Path: dir, Name: "mod"}, ScanOptions{}, logger.New(false))
+	if err != nil {
+		t.Fatalf("AnalyzeRepositoryWithOptions() error = %v", err)
+	}
+
+	writeTree(t, dir, map[string]string{"edited.go": "package main\n\nfunc f() {}\n"})
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "touched.go"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "gone.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"edited.go", "gone.go", "touched.go"}
+	if got := ModifiedFiles(analysis); !reflect.DeepEqual(got, want) {
+		t.Errorf("ModifiedFiles() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+package scanner
+
+import "sort"
+
+// DefaultLargeFileThreshold is the size above which a file is reported in
+// RepositoryAnalysis.LargeFiles unless ScanOptions.LargeFileThreshold says
+// otherwise.
+const DefaultLargeFileThreshold int64 = 5 << 20
+
+// LargeFile is a file above the large-file threshold, often a build
+// artifact or data dump committed by mistake.
+type LargeFile struct {
+	Path      string `json:"path"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+// largeFileThreshold returns LargeFileThreshold, or
+// DefaultLargeFileThreshold when it is not positive.
+func (o ScanOptions) largeFileThreshold() int64 {
+	if o.LargeFileThreshold > 0 {
+		return o.LargeFileThreshold
+	}
+	return DefaultLargeFileThreshold
+}
+
+// findLargeFiles returns the files larger than threshold bytes, largest
+// first, ties broken by path. Binary and generated files are included:
+// they are the usual culprits.
+func findLargeFiles(files map[string]FileStat, threshold int64) []LargeFile {
+	var large []LargeFile
+	for path, stat := range files {
+		if stat.Size > threshold {
+			large = append(large, LargeFile{Path: path, SizeBytes: stat.Size})
+		}
+	}
+	sort.Slice(large, func(i, j int) bool {
+		if large[i].SizeBytes != large[j].SizeBytes {
+			return large[i].SizeBytes > large[j].SizeBytes
+		}
+		return large[i].Path < large[j].Path
+	})
+	return large
+}
@@ -0,0 +1,75 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestAnalyzeRepositoryWithOptions_LargeFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"main.go": "package main\n"})
+	sizes := map[string]int64{
+		"assets/app.tar": 3000,
+		"data.bin":       2049,
+		"exact.bin":      2048,
+		"small.txt":      100,
+	}
+	for name, size := range sizes {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := ScanOptions{LargeFileThreshold: 2048}
+	analysis, err := AnalyzeRepositoryWithOptions(context.Background(), Repository{Path: dir, Name: "app"}, opts, logger.New(false))
+	if err != nil {
+		t.Fatalf("AnalyzeRepositoryWithOptions() error = %v", err)
+	}
+	want := []LargeFile{{Path: "assets/app.tar", SizeBytes: 3000}, {Path: "data.bin", SizeBytes: 2049}}
+	if !reflect.DeepEqual(analysis.LargeFiles, want) {
+		t.Errorf("LargeFiles = %+v, want %+v", analysis.LargeFiles, want)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "data.bin")); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateAnalysis(context.Background(), analysis, []string{"data.bin"}, opts); err != nil {
+		t.Fatalf("UpdateAnalysis() error = %v", err)
+	}
+	if !reflect.DeepEqual(analysis.LargeFiles, want[:1]) {
+		t.Errorf("LargeFiles after update = %+v, want %+v", analysis.LargeFiles, want[:1])
+	}
+}
+
+func TestAnalyzeRepositoryWithOptions_DefaultLargeFileThreshold(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"notes.txt": "under the default threshold\n"})
+	analysis, err := AnalyzeRepositoryWithOptions(context.Background(), Repository{Path: dir, Name: "app"}, ScanOptions{}, logger.New(false))
+	if err != nil {
+		t.Fatalf("AnalyzeRepositoryWithOptions() error = %v", err)
+	}
+	if len(analysis.LargeFiles) != 0 {
+		t.Errorf("LargeFiles = %+v, want none", analysis.LargeFiles)
+	}
+}
+
+func TestFindLargeFiles_TiesByPath(t *testing.T) {
+	files := map[string]FileStat{
+		"b.bin": {Size: 10},
+		"a.bin": {Size: 10},
+		"c.bin": {Size: 5},
+	}
+	want := []LargeFile{{Path: "a.bin", SizeBytes: 10}, {Path: "b.bin", SizeBytes: 10}}
+	if got := findLargeFiles(files, 5); !reflect.DeepEqual(got, want) {
+		t.Errorf("findLargeFiles() = %+v, want %+v", got, want)
+	}
+}
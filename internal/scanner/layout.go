@@ -0,0 +1,36 @@
+package scanner
+
+import "path/filepath"
+
+// Layout classifies how the repositories under a scan root are arranged.
+type Layout string
+
+const (
+	// SingleRepo is one repository (or a plain directory with none).
+	SingleRepo Layout = "single-repo"
+	// Monorepo is a root repository that contains nested repositories or
+	// declares submodules.
+	Monorepo Layout = "monorepo"
+	// MultiRepo is a plain directory holding several independent repositories.
+	MultiRepo Layout = "multi-repo"
+)
+
+// ClassifyLayout reports whether repos, discovered under root, form a single
+// repository, a monorepo rooted at root, or a collection of sibling repos.
+func ClassifyLayout(repos []Repository, root string) Layout {
+	root = filepath.Clean(root)
+	for _, repo := range repos {
+		if filepath.Clean(repo.Path) != root {
+			continue
+		}
+		if len(repos) > 1 || repo.HasSubmodules {
+			return Monorepo
+		}
+		return SingleRepo
+	}
+
+	if len(repos) > 1 {
+		return MultiRepo
+	}
+	return SingleRepo
+}
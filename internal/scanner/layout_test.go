@@ -0,0 +1,35 @@
+package scanner
+
+import "testing"
+
+func TestClassifyLayout(t *testing.T) {
+	root := "/work/codebase"
+	tests := []struct {
+		name  string
+		repos []Repository
+		want  Layout
+	}{
+		{"no repositories", nil, SingleRepo},
+		{"root is the only repo", []Repository{{Path: root}}, SingleRepo},
+		{"single nested repo", []Repository{{Path: root + "/app"}}, SingleRepo},
+		{
+			"root repo with nested repos",
+			[]Repository{{Path: root}, {Path: root + "/libs/shared"}},
+			Monorepo,
+		},
+		{"root repo with submodules", []Repository{{Path: root + "/", HasSubmodules: true}}, Monorepo},
+		{
+			"sibling repos under plain directory",
+			[]Repository{{Path: root + "/api"}, {Path: root + "/web"}},
+			MultiRepo,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyLayout(tt.repos, root); got != tt.want {
+				t.Errorf("ClassifyLayout() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
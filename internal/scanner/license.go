@@ -0,0 +1,105 @@
+package scanner
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// LicenseUnknown is reported when a license file exists but its text does
+// not match any known license.
+const LicenseUnknown = "unknown"
+
+// licenseSniffLen bounds how much of a license file is read; the
+// identifying text is always near the top.
+const licenseSniffLen = 8192
+
+// licenseFileNames are the root-level files checked, in priority order.
+// Matching is case-insensitive.
+var licenseFileNames = []string{
+	"LICENSE", "LICENSE.md", "LICENSE.txt",
+	"LICENCE", "LICENCE.md", "LICENCE.txt",
+	"COPYING", "COPYING.md", "COPYING.txt",
+}
+
+// spdxPattern matches an explicit SPDX-License-Identifier tag.
+var spdxPattern = regexp.MustCompile(`SPDX-License-Identifier:\s*([A-Za-z0-9.+-]+)`)
+
+// licenseHeuristics identifies licenses by phrases that appear in their
+// canonical text. All phrases of an entry must be present.
+var licenseHeuristics = []struct {
+	spdx    string
+	phrases []string
+}{
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"GPL-3.0", []string{"gnu general public license", "version 3"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "neither the name"}},
+}
+
+// DetectLicense returns the SPDX identifier of the license file at the root
+// of repoPath, LicenseUnknown when a license file is present but not
+// recognized, or "" when there is no license file.
+func DetectLicense(repoPath string) string {
+	path, ok := findLicenseFile(repoPath)
+	if !ok {
+		return ""
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return LicenseUnknown
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, licenseSniffLen))
+	if err != nil {
+		return LicenseUnknown
+	}
+	return identifyLicense(string(data))
+}
+
+// findLicenseFile returns the first root-level license file in repoPath.
+func findLicenseFile(repoPath string) (string, bool) {
+	entries, err := os.ReadDir(repoPath)
+	if err != nil {
+		return "", false
+	}
+
+	present := make(map[string]string, len(entries))
+	for _, e := range entries {
+		if e.Type().IsRegular() {
+			present[strings.ToUpper(e.Name())] = e.Name()
+		}
+	}
+	for _, name := range licenseFileNames {
+		if actual, ok := present[strings.ToUpper(name)]; ok {
+			return filepath.Join(repoPath, actual), true
+		}
+	}
+	return "", false
+}
+
+// identifyLicense maps license text to an SPDX identifier.
+func identifyLicense(text string) string {
+	if m := spdxPattern.FindStringSubmatch(text); m != nil {
+		return m[1]
+	}
+
+	normalized := strings.ToLower(strings.Join(strings.Fields(text), " "))
+	for _, h := range licenseHeuristics {
+		matched := true
+		for _, phrase := range h.phrases {
+			if !strings.Contains(normalized, phrase) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return h.spdx
+		}
+	}
+	return LicenseUnknown
+}
@@ -0,0 +1,52 @@
+package scanner
+
+import (
+	"testing"
+)
+
+const mitText = `MIT License
+
+Copyright (c) 2024 Example Corp
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction.
+`
+
+const apacheText = `
+                                 Apache License
+                           Version 2.0, January 2004
+                        http://www.apache.org/licenses/
+
+   TERMS AND CONDITIONS FOR USE, REPRODUCTION, AND DISTRIBUTION
+`
+
+func TestDetectLicense(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"mit", map[string]string{"LICENSE": mitText}, "MIT"},
+		{"apache with extension", map[string]string{"LICENSE.txt": apacheText}, "Apache-2.0"},
+		{"lowercase copying", map[string]string{"copying": "GNU GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007\n"}, "GPL-3.0"},
+		{
+			"bsd 3 clause",
+			map[string]string{"LICENSE.md": "Redistribution and use in source and binary\nforms, with or without modification...\nNeither the name of the copyright holder..."},
+			"BSD-3-Clause",
+		},
+		{"spdx tag", map[string]string{"LICENSE": "SPDX-License-Identifier: MPL-2.0\n"}, "MPL-2.0"},
+		{"unrecognized", map[string]string{"LICENSE": "All rights reserved.\n"}, LicenseUnknown},
+		{"no license", map[string]string{"README.md": "# app\n"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTree(t, dir, tt.files)
+			if got := DetectLicense(dir); got != tt.want {
+				t.Errorf("DetectLicense() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
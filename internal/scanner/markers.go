@@ -0,0 +1,36 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"regexp"
+)
+
+// markerPattern matches TODO and FIXME written as whole, upper-case words.
+var markerPattern = regexp.MustCompile(`\b(?:TODO|FIXME)\b`)
+
+// maxMarkerLine bounds the line length countMarkers buffers; counting
+// stops at the first longer line, as such lines are minified or data.
+const maxMarkerLine = 1024 * 1024
+
+// countMarkers returns the number of TODO and FIXME markers in the text
+// file at path. Unreadable files count as zero.
+func countMarkers(path string) int {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), maxMarkerLine)
+	count := 0
+	for sc.Scan() {
+		line := sc.Bytes()
+		if bytes.Contains(line, []byte("TODO")) || bytes.Contains(line, []byte("FIXME")) {
+			count += len(markerPattern.FindAll(line, -1))
+		}
+	}
+	return count
+}
@@ -0,0 +1,53 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCountMarkers(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{"none", "package main\n", 0},
+		{"todo and fixme", "// TODO: split\nx := 1 // FIXME\n", 2},
+		{"two on one line", "// TODO fix this TODO\n", 2},
+		{"no trailing newline", "# FIXME", 1},
+		{"lower case ignored", "// todo: later\n", 0},
+		{"part of a word ignored", "TODOS := []string{}\nFIXMEPLEASE\n", 0},
+		{"empty", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "file.go")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if got := countMarkers(path); got != tt.want {
+				t.Errorf("countMarkers() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountMarkers_LongLineStopsCounting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "min.js")
+	content := "// TODO first\n" + strings.Repeat("x", maxMarkerLine+1) + "\n// TODO after\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := countMarkers(path); got != 1 {
+		t.Errorf("countMarkers() = %d, want 1 (counted up to the long line)", got)
+	}
+}
+
+func TestCountMarkers_Missing(t *testing.T) {
+	if got := countMarkers(filepath.Join(t.TempDir(), "missing")); got != 0 {
+		t.Errorf("countMarkers(missing) = %d, want 0", got)
+	}
+}
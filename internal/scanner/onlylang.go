@@ -0,0 +1,51 @@
+package scanner
+
+import "strings"
+
+// OtherLanguage is the entry of RepositoryAnalysis.Languages and
+// LinesByLanguage that tallies the files whose language
+// ScanOptions.OnlyLanguages leaves out. It is never a primary language.
+const OtherLanguage = "other"
+
+// selectsLanguage reports whether files of lang are counted under their
+// own language: always when OnlyLanguages is empty or lang is unknown,
+// otherwise when OnlyLanguages names lang in any case.
+func (o ScanOptions) selectsLanguage(lang string) bool {
+	if len(o.OnlyLanguages) == 0 || lang == "" {
+		return true
+	}
+	for _, only := range o.OnlyLanguages {
+		if strings.EqualFold(only, lang) {
+			return true
+		}
+	}
+	return false
+}
+
+// restrictLanguage moves a file whose language is not selected into the
+// OtherLanguage bucket. Its lines still count toward the totals, but it
+// is no longer counted as a test or code file and its markers are
+// dropped, so those figures describe only the selected languages.
+func (o ScanOptions) restrictLanguage(stat *FileStat) {
+	if o.selectsLanguage(stat.Language) {
+		return
+	}
+	stat.Language = OtherLanguage
+	stat.Test = false
+	stat.Markers = 0
+}
+
+// primaryLanguage returns the first entry of counts, sorted by
+// SortCounts, that has files and is not OtherLanguage, or "" when there is
+// none.
+func primaryLanguage(counts []NamedCount) string {
+	for _, c := range counts {
+		if c.Count <= 0 {
+			break
+		}
+		if c.Name != OtherLanguage {
+			return c.Name
+		}
+	}
+	return ""
+}
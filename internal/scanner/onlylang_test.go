@@ -0,0 +1,120 @@
+package scanner
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// mixedRepo lays out a repository with Go, Python, and TypeScript code,
+// plus a README, and returns it.
+func mixedRepo(t *testing.T) Repository {
+	t.Helper()
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"main.go":           "package main\n\n// TODO: flags\nfunc main() {}\n",
+		"main_test.go":      "package main\n",
+		"tools/gen.py":      "# TODO: port\nimport os\nprint(os.name)\n",
+		"tools/gen_test.py": "import gen\n",
+		"web/app.ts":        "export {}\n",
+		"web/app.test.ts":   "test()\n",
+		"README.md":         "# Mixed\n",
+	})
+	return Repository{Path: dir, Name: "mixed"}
+}
+
+func TestAnalyzeRepository_OnlyLanguages(t *testing.T) {
+	repo := mixedRepo(t)
+	log := logger.New(false)
+	all, err := AnalyzeRepositoryWithOptions(context.Background(), repo, ScanOptions{}, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goOnly, err := AnalyzeRepositoryWithOptions(context.Background(), repo, ScanOptions{OnlyLanguages: []string{"go"}}, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := map[string]int{"Go": 2, OtherLanguage: 5}; !reflect.DeepEqual(goOnly.Languages, want) {
+		t.Errorf("Languages = %v, want %v", goOnly.Languages, want)
+	}
+	if want := map[string]int{"Go": 5, OtherLanguage: 7}; !reflect.DeepEqual(goOnly.LinesByLanguage, want) {
+		t.Errorf("LinesByLanguage = %v, want %v", goOnly.LinesByLanguage, want)
+	}
+	if goOnly.TotalFiles != all.TotalFiles || goOnly.TotalLines != all.TotalLines {
+		t.Errorf("totals = %d files, %d lines, want the unfiltered %d files, %d lines",
+			goOnly.TotalFiles, goOnly.TotalLines, all.TotalFiles, all.TotalLines)
+	}
+	if goOnly.TestFiles != 1 || goOnly.CodeFiles != 1 {
+		t.Errorf("TestFiles, CodeFiles = %d, %d, want 1, 1 (Go only)", goOnly.TestFiles, goOnly.CodeFiles)
+	}
+	if goOnly.Markers != 1 {
+		t.Errorf("Markers = %d, want 1 (Go only)", goOnly.Markers)
+	}
+	if got := goOnly.PrimaryLanguage(); got != "Go" {
+		t.Errorf("PrimaryLanguage() = %q, want Go even though %q has more files", got, OtherLanguage)
+	}
+	if got := Aggregate([]*RepositoryAnalysis{goOnly}).PrimaryLanguage; got != "Go" {
+		t.Errorf("Aggregate().PrimaryLanguage = %q, want Go", got)
+	}
+}
+
+func TestScanOptions_SelectsLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		only []string
+		lang string
+		want bool
+	}{
+		{"no restriction", nil, "Python", true},
+		{"selected", []string{"go", "rust"}, "Rust", true},
+		{"case-insensitive", []string{"GO"}, "Go", true},
+		{"left out", []string{"go"}, "Python", false},
+		{"no language", []string{"go"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (ScanOptions{OnlyLanguages: tt.only}).selectsLanguage(tt.lang); got != tt.want {
+				t.Errorf("selectsLanguage(%q) = %v, want %v", tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanOptions_OnlyLanguagesFingerprintSettings(t *testing.T) {
+	settings := func(only ...string) string {
+		var b strings.Builder
+		ScanOptions{OnlyLanguages: only}.writeAnalysisSettings(&b)
+		return b.String()
+	}
+	if settings() == settings("go") {
+		t.Error("OnlyLanguages does not change the analysis settings")
+	}
+	if settings("go", "rust") != settings("Rust", "Go") {
+		t.Error("analysis settings depend on the order or case of OnlyLanguages")
+	}
+}
+
+func TestPrimaryLanguage_SkipsOther(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts []NamedCount
+		want   string
+	}{
+		{"empty", nil, ""},
+		{"first", []NamedCount{{"Go", 3}, {"Rust", 1}}, "Go"},
+		{"other skipped", []NamedCount{{OtherLanguage, 9}, {"Go", 1}}, "Go"},
+		{"only other", []NamedCount{{OtherLanguage, 9}}, ""},
+		{"no files", []NamedCount{{"Go", 0}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := primaryLanguage(tt.counts); got != tt.want {
+				t.Errorf("primaryLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package scanner
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ScanOptions tunes repository discovery and analysis. The zero value
+// preserves the default behavior.
+type ScanOptions struct {
+	// MaxDepth limits discovery to directories at most this many levels
+	// below the root (the root itself is level 0). Zero means unlimited.
+	MaxDepth int
+	// OnProgress, if set, is called with the running count of files seen
+	// every ProgressInterval files during a walk.
+	OnProgress func(filesSeen int)
+	// ProgressInterval is the number of files between OnProgress calls.
+	// Zero or negative uses DefaultProgressInterval.
+	ProgressInterval int
+	// SkipDirs names additional directories, by base name, to skip during
+	// discovery and analysis.
+	SkipDirs []string
+	// LangMap maps file extensions to languages, overriding or extending
+	// the built-in mapping. Keys may omit the leading dot.
+	LangMap map[string]string
+	// FollowSymlinks descends into symlinked directories during discovery
+	// and analysis, visiting each directory at most once.
+	FollowSymlinks bool
+	// IncludeHidden analyzes hidden directories such as .github and
+	// .config, which are skipped by default. .git is always skipped.
+	IncludeHidden bool
+	// LargeFileThreshold is the size in bytes above which a file is listed
+	// in RepositoryAnalysis.LargeFiles. Zero or negative uses
+	// DefaultLargeFileThreshold.
+	LargeFileThreshold int64
+	// WalkWorkers is the number of goroutines walking each repository
+	// during analysis. Zero or one walks sequentially. The results are the
+	// same either way; FollowSymlinks always walks sequentially.
+	WalkWorkers int
+	// ScanSecrets records potential secrets in each text file (see
+	// ScanSecrets), listed in RepositoryAnalysis.Secrets. It is off by
+	// default, as it reads every file once more.
+	ScanSecrets bool
+	// OnlyLanguages, when non-empty, restricts the per-language counts to
+	// these languages, matched in any case against the language mapping.
+	// Files of other languages are tallied under OtherLanguage; overall
+	// totals still include them.
+	OnlyLanguages []string
+}
+
+// skipsConfiguredDir reports whether name is listed in SkipDirs.
+func (o ScanOptions) skipsConfiguredDir(name string) bool {
+	for _, dir := range o.SkipDirs {
+		if dir == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SkipsDir reports whether analysis skips the directory name, either by
+// default (.git, hidden directories unless IncludeHidden, and
+// dependency/build directories) or because it is listed in SkipDirs.
+func (o ScanOptions) SkipsDir(name string) bool {
+	return skipAnalysisDir(name, o.IncludeHidden) || o.skipsConfiguredDir(name)
+}
+
+// language maps ext to a language, consulting LangMap before the
+// built-in mapping.
+func (o ScanOptions) language(ext string) string {
+	if ext == "" {
+		return ""
+	}
+	if lang, ok := o.LangMap[ext]; ok {
+		return lang
+	}
+	if lang, ok := o.LangMap[strings.TrimPrefix(ext, ".")]; ok {
+		return lang
+	}
+	return extensionToLanguage(ext)
+}
+
+// LanguageMap returns the effective extension-to-language mapping: the
+// built-in entries with LangMap applied on top. Keys always carry the
+// leading dot; when LangMap holds both ".x" and "x", ".x" wins as it does
+// during analysis.
+func (o ScanOptions) LanguageMap() map[string]string {
+	langs := make(map[string]string, len(extToLang)+len(o.LangMap))
+	for ext, lang := range extToLang {
+		langs[ext] = lang
+	}
+	for ext, lang := range o.LangMap {
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			if _, dotted := o.LangMap["."+ext]; dotted {
+				continue
+			}
+			ext = "." + ext
+		}
+		langs[ext] = lang
+	}
+	return langs
+}
+
+// writeAnalysisSettings writes the options that change analysis results,
+// in a stable order, so they can be folded into a fingerprint.
+func (o ScanOptions) writeAnalysisSettings(w io.Writer) {
+	skips := append([]string(nil), o.SkipDirs...)
+	sort.Strings(skips)
+	fmt.Fprintf(w, "skip %s\n", strings.Join(skips, ","))
+	fmt.Fprintf(w, "follow-symlinks %t\n", o.FollowSymlinks)
+	fmt.Fprintf(w, "include-hidden %t\n", o.IncludeHidden)
+	fmt.Fprintf(w, "large-file-threshold %d\n", o.largeFileThreshold())
+	fmt.Fprintf(w, "scan-secrets %t\n", o.ScanSecrets)
+	only := make([]string, len(o.OnlyLanguages))
+	for i, lang := range o.OnlyLanguages {
+		only[i] = strings.ToLower(lang)
+	}
+	sort.Strings(only)
+	fmt.Fprintf(w, "only-lang %s\n", strings.Join(only, ","))
+
+	exts := make([]string, 0, len(o.LangMap))
+	for ext := range o.LangMap {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	for _, ext := range exts {
+		fmt.Fprintf(w, "lang %s=%s\n", ext, o.LangMap[ext])
+	}
+}
@@ -0,0 +1,207 @@
+package scanner
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestScanOptionsLanguage(t *testing.T) {
+	opts := ScanOptions{LangMap: map[string]string{".tpl": "Go Template", "js": "ECMAScript"}}
+	tests := []struct {
+		ext  string
+		want string
+	}{
+		{".tpl", "Go Template"},
+		{".js", "ECMAScript"},
+		{".go", "Go"},
+		{".unknown", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ext, func(t *testing.T) {
+			if got := opts.language(tt.ext); got != tt.want {
+				t.Errorf("language(%q) = %q, want %q", tt.ext, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanOptionsLanguageMap(t *testing.T) {
+	opts := ScanOptions{LangMap: map[string]string{
+		"tpl":   "Go Template",
+		".js":   "ECMAScript",
+		"proto": "Protocol Buffers",
+		".x":    "Dotted",
+		"x":     "Bare",
+	}}
+	langs := opts.LanguageMap()
+
+	tests := []struct {
+		ext  string
+		want string
+	}{
+		{".go", "Go"},
+		{".tpl", "Go Template"},
+		{".js", "ECMAScript"},
+		{".proto", "Protocol Buffers"},
+		{".x", "Dotted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ext, func(t *testing.T) {
+			if got := langs[tt.ext]; got != tt.want {
+				t.Errorf("LanguageMap()[%q] = %q, want %q", tt.ext, got, tt.want)
+			}
+			if got := opts.language(tt.ext); got != tt.want {
+				t.Errorf("language(%q) = %q, disagrees with LanguageMap %q", tt.ext, got, tt.want)
+			}
+		})
+	}
+	if _, ok := langs["tpl"]; ok {
+		t.Error("LanguageMap() kept undotted key \"tpl\"")
+	}
+	if extToLang[".js"] != "JavaScript" {
+		t.Error("LanguageMap() modified the built-in mapping")
+	}
+}
+
+func TestAnalyzeRepositoryWithOptions_SkipDirsAndLangMap(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"main.go":            "package main\n",
+		"page.tpl":           "{{.}}\n",
+		"third_party/lib.go": "package lib\n",
+	})
+
+	opts := ScanOptions{
+		SkipDirs: []string{"third_party"},
+		LangMap:  map[string]string{".tpl": "Go Template"},
+	}
+	analysis, err := AnalyzeRepositoryWithOptions(context.Background(), Repository{Path: dir, Name: "app"}, opts, logger.New(false))
+	if err != nil {
+		t.Fatalf("AnalyzeRepositoryWithOptions() error = %v", err)
+	}
+	if analysis.TotalFiles != 2 {
+		t.Errorf("TotalFiles = %d, want 2 (third_party skipped)", analysis.TotalFiles)
+	}
+	if analysis.Languages["Go Template"] != 1 {
+		t.Errorf("Languages[Go Template] = %d, want 1", analysis.Languages["Go Template"])
+	}
+}
+
+func TestAnalyzeRepositoryWithOptions_UnclassifiedTypes(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"main.go":     "package main\n",
+		"a.foo":       "foo\n",
+		"sub/b.foo":   "foo\n",
+		"page.tpl":    "{{.}}\n",
+		"notes.bar":   "bar\n",
+		"Makefile":    "all:\n",
+		"run":         "#!/bin/sh\n",
+		"data/c.FOO2": "x\n",
+	})
+
+	opts := ScanOptions{LangMap: map[string]string{"tpl": "Go Template"}}
+	analysis, err := AnalyzeRepositoryWithOptions(context.Background(), Repository{Path: dir, Name: "app"}, opts, logger.New(false))
+	if err != nil {
+		t.Fatalf("AnalyzeRepositoryWithOptions() error = %v", err)
+	}
+	want := map[string]int{".foo": 2, ".bar": 1, ".FOO2": 1}
+	if !reflect.DeepEqual(analysis.UnclassifiedTypes, want) {
+		t.Errorf("UnclassifiedTypes = %v, want %v", analysis.UnclassifiedTypes, want)
+	}
+}
+
+func TestAnalyzeRepositoryWithOptions_NeverCountsGitMetadata(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"main.go":                       "package main\n",
+		".gitignore":                    "bin/\n",
+		".git/HEAD":                     "ref: refs/heads/main\n",
+		".git/objects/pack/pack-1.pack": "PACK",
+		".git/hooks/pre-commit.sh":      "#!/bin/sh\n",
+		"lib/.git":                      "gitdir: ../.git/modules/lib\n",
+		"lib/lib.go":                    "package lib\n",
+	})
+
+	analysis, err := AnalyzeRepositoryWithOptions(context.Background(), Repository{Path: dir, Name: "app"}, ScanOptions{}, logger.New(false))
+	if err != nil {
+		t.Fatalf("AnalyzeRepositoryWithOptions() error = %v", err)
+	}
+	if analysis.TotalFiles != 3 {
+		t.Errorf("TotalFiles = %d, want 3 (.git directory and gitlink file excluded)", analysis.TotalFiles)
+	}
+	for path := range analysis.Files {
+		if path == ".git" || strings.HasPrefix(path, ".git/") || strings.HasSuffix(path, "/.git") {
+			t.Errorf("analysis counted git metadata %s", path)
+		}
+	}
+}
+
+func TestAnalyzeRepositoryWithOptions_IncludeHidden(t *testing.T) {
+	tests := []struct {
+		name          string
+		includeHidden bool
+		wantFiles     int
+		wantYAML      int
+	}{
+		{"hidden directories skipped by default", false, 2, 0},
+		{"include hidden counts dotfile directories", true, 4, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTree(t, dir, map[string]string{
+				"main.go":                     "package main\n",
+				".gitignore":                  "bin/\n",
+				".github/workflows/ci.yml":    "on: push\n",
+				".github/workflows/lint.yaml": "on: push\n",
+				".git/HEAD":                   "ref: refs/heads/main\n",
+				".git/objects/ab/cdef":        "blob",
+			})
+
+			opts := ScanOptions{IncludeHidden: tt.includeHidden}
+			analysis, err := AnalyzeRepositoryWithOptions(context.Background(), Repository{Path: dir, Name: "app"}, opts, logger.New(false))
+			if err != nil {
+				t.Fatalf("AnalyzeRepositoryWithOptions() error = %v", err)
+			}
+			if analysis.TotalFiles != tt.wantFiles {
+				t.Errorf("TotalFiles = %d, want %d", analysis.TotalFiles, tt.wantFiles)
+			}
+			if got := analysis.Languages["YAML"]; got != tt.wantYAML {
+				t.Errorf("Languages[YAML] = %d, want %d", got, tt.wantYAML)
+			}
+			for path := range analysis.Files {
+				if strings.HasPrefix(path, ".git/") {
+					t.Errorf("analysis counted git metadata %s", path)
+				}
+			}
+		})
+	}
+}
+
+func TestFingerprint_IncludesAnalysisSettings(t *testing.T) {
+	repos := []Repository{{Path: t.TempDir()}}
+	base, _ := Fingerprint(context.Background(), repos, ScanOptions{})
+	withSkip, _ := Fingerprint(context.Background(), repos, ScanOptions{SkipDirs: []string{"gen"}})
+	withLang, _ := Fingerprint(context.Background(), repos, ScanOptions{LangMap: map[string]string{".x": "X"}})
+	withHidden, _ := Fingerprint(context.Background(), repos, ScanOptions{IncludeHidden: true})
+	withLarge, _ := Fingerprint(context.Background(), repos, ScanOptions{LargeFileThreshold: 1 << 10})
+	withDefaultLarge, _ := Fingerprint(context.Background(), repos, ScanOptions{LargeFileThreshold: DefaultLargeFileThreshold})
+
+	if base == withSkip || base == withLang || withSkip == withLang {
+		t.Error("Fingerprint() should differ when SkipDirs or LangMap change")
+	}
+	if base == withHidden {
+		t.Error("Fingerprint() should differ when IncludeHidden changes")
+	}
+	if base == withLarge || base != withDefaultLarge {
+		t.Error("Fingerprint() should differ only when the effective LargeFileThreshold changes")
+	}
+}
@@ -0,0 +1,159 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// walkParallel walks root like filepath.Walk, but lets up to workers
+// goroutines read directories and call fn, so fn must be safe for
+// concurrent use. Each directory's entries are visited in lexical order by
+// one goroutine, and fn sees a directory before anything in it; otherwise
+// the order is unspecified. As with filepath.Walk, symlinks are not
+// followed, fn returning filepath.SkipDir for a directory skips it and for
+// a file skips the rest of its directory, and any other error stops the
+// walk and is returned.
+func walkParallel(root string, workers int, fn filepath.WalkFunc) error {
+	info, err := os.Lstat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = fn(root, info, nil)
+	}
+	if errors.Is(err, filepath.SkipDir) {
+		return nil
+	}
+	if err != nil || info == nil || !info.IsDir() {
+		return err
+	}
+
+	q := &dirQueue{dirs: []queuedDir{{root, info}}, pending: 1}
+	q.cond = sync.NewCond(&q.mu)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				d, ok := q.next()
+				if !ok {
+					return
+				}
+				q.done(walkDir(d, fn, q.push))
+			}
+		}()
+	}
+	wg.Wait()
+	return q.err
+}
+
+// queuedDir is a directory waiting for its entries to be walked.
+type queuedDir struct {
+	path string
+	info os.FileInfo
+}
+
+// dirQueue hands directories to walkParallel's workers. pending counts the
+// directories queued or being walked; the walk is over when it reaches
+// zero or an error is recorded.
+type dirQueue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	dirs    []queuedDir
+	pending int
+	err     error
+}
+
+// next blocks until a directory is available and returns it, or returns
+// false once the walk is over.
+func (q *dirQueue) next() (queuedDir, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.dirs) == 0 && q.pending > 0 && q.err == nil {
+		q.cond.Wait()
+	}
+	if q.pending == 0 || q.err != nil {
+		return queuedDir{}, false
+	}
+	d := q.dirs[len(q.dirs)-1]
+	q.dirs = q.dirs[:len(q.dirs)-1]
+	return d, true
+}
+
+// push queues a subdirectory found while walking another.
+func (q *dirQueue) push(d queuedDir) {
+	q.mu.Lock()
+	q.dirs = append(q.dirs, d)
+	q.pending++
+	q.mu.Unlock()
+	q.cond.Signal()
+}
+
+// done marks a directory walked, recording the first error.
+func (q *dirQueue) done(err error) {
+	q.mu.Lock()
+	q.pending--
+	if err != nil && q.err == nil {
+		q.err = err
+	}
+	q.mu.Unlock()
+	q.cond.Broadcast()
+}
+
+// walkDir calls fn for each entry of d in lexical order, passing the
+// subdirectories fn does not skip to push.
+func walkDir(d queuedDir, fn filepath.WalkFunc, push func(queuedDir)) error {
+	f, err := os.Open(d.path)
+	var names []string
+	if err == nil {
+		names, err = f.Readdirnames(-1)
+		f.Close()
+	}
+	if err != nil {
+		// Reported a second time with the error, as filepath.Walk does.
+		if err := fn(d.path, d.info, err); err != nil && !errors.Is(err, filepath.SkipDir) {
+			return err
+		}
+		return nil
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(d.path, name)
+		info, err := os.Lstat(path)
+		if err != nil {
+			if err := fn(path, info, err); err != nil && !errors.Is(err, filepath.SkipDir) {
+				return err
+			}
+			continue
+		}
+		err = fn(path, info, nil)
+		switch {
+		case errors.Is(err, filepath.SkipDir):
+			if !info.IsDir() {
+				return nil
+			}
+		case err != nil:
+			return err
+		case info.IsDir():
+			push(queuedDir{path, info})
+		}
+	}
+	return nil
+}
+
+// walkOrderLess reports whether slash-separated path a comes before b in
+// the order filepath.Walk visits them: segment by segment, lexically.
+func walkOrderLess(a, b string) bool {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] != bs[i] {
+			return as[i] < bs[i]
+		}
+	}
+	return len(as) < len(bs)
+}
@@ -0,0 +1,108 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// deepTree returns a fixture of nested directories mixing languages,
+// skipped directories, and ignored paths.
+func deepTree() map[string]string {
+	files := map[string]string{
+		IgnoreFileName:              "*.log\ngen/\n",
+		"go.mod":                    "module example.com/deep\n\ngo 1.21\n",
+		"README.md":                 "# deep\n",
+		"node_modules/pkg/index.js": "module.exports = {}\n",
+		".cache/blob.bin":           "\x00\x01",
+	}
+	exts := []string{".go", ".py", ".ts", ".md", ".yaml", ".foo"}
+	for a := 0; a < 4; a++ {
+		for b := 0; b < 4; b++ {
+			dir := fmt.Sprintf("pkg%d/sub%d/leaf%d", a, b, a*b)
+			for i, ext := range exts {
+				files[fmt.Sprintf("%s/f%d%s", dir, i, ext)] = fmt.Sprintf("// TODO %d\nline\n", i)
+			}
+			files[fmt.Sprintf("pkg%d/sub%d/f_test.go", a, b)] = "package sub\n"
+			files[fmt.Sprintf("pkg%d/sub%d/run.log", a, b)] = "log\n"
+			files[fmt.Sprintf("pkg%d/gen/sub%d/z.go", a, b)] = "package gen\n"
+		}
+	}
+	return files
+}
+
+func TestAnalyzeRepositoryWithOptions_WalkWorkersMatchesSequential(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, deepTree())
+	if runtime.GOOS != "windows" && os.Geteuid() != 0 {
+		writeTree(t, dir, map[string]string{"pkg1/locked/a.go": "package locked\n", "pkg3/sub2/locked/b.go": "package locked\n"})
+		for _, locked := range []string{"pkg1/locked", "pkg3/sub2/locked"} {
+			path := filepath.Join(dir, locked)
+			if err := os.Chmod(path, 0000); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { os.Chmod(path, 0755) })
+		}
+	}
+	repo := Repository{Path: dir, Name: "deep"}
+
+	want, err := AnalyzeRepositoryWithOptions(context.Background(), repo, ScanOptions{}, logger.New(false))
+	if err != nil {
+		t.Fatalf("sequential AnalyzeRepositoryWithOptions() error = %v", err)
+	}
+	for _, workers := range []int{2, 8, 32} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			var lastSeen atomic.Int64
+			opts := ScanOptions{WalkWorkers: workers, ProgressInterval: 1, OnProgress: func(n int) { lastSeen.Store(int64(n)) }}
+			got, err := AnalyzeRepositoryWithOptions(context.Background(), repo, opts, logger.New(false))
+			if err != nil {
+				t.Fatalf("AnalyzeRepositoryWithOptions() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("parallel analysis differs from sequential:\n got %+v\nwant %+v", got, want)
+			}
+			if lastSeen.Load() != int64(want.TotalFiles) {
+				t.Errorf("OnProgress last reported %d files, want %d", lastSeen.Load(), want.TotalFiles)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepositoryWithOptions_WalkWorkersCancelled(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, deepTree())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := AnalyzeRepositoryWithOptions(ctx, Repository{Path: dir, Name: "deep"}, ScanOptions{WalkWorkers: 4}, logger.New(false))
+	if err == nil {
+		t.Fatal("AnalyzeRepositoryWithOptions() error = nil, want context.Canceled")
+	}
+}
+
+func TestWalkOrderLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"a", "b", true},
+		{"b", "a", false},
+		{"a", "a/b", true},
+		{"a/b", "a-b", true}, // "a" < "a-b" segment-wise, though "/" > "-"
+		{"a-b", "a/b", false},
+		{"a/z", "b", true},
+		{"a/b", "a/b", false},
+	}
+	for _, tt := range tests {
+		if got := walkOrderLess(tt.a, tt.b); got != tt.want {
+			t.Errorf("walkOrderLess(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package scanner
+
+// DefaultProgressInterval is how many files are seen between OnProgress
+// callbacks when ScanOptions.ProgressInterval is unset.
+const DefaultProgressInterval = 1000
+
+// progressCounter counts files seen during a walk and reports to the
+// OnProgress callback every ProgressInterval files. A nil callback is a no-op.
+type progressCounter struct {
+	onProgress func(filesSeen int)
+	interval   int
+	seen       int
+}
+
+func newProgressCounter(opts ScanOptions) *progressCounter {
+	interval := opts.ProgressInterval
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+	return &progressCounter{onProgress: opts.OnProgress, interval: interval}
+}
+
+// tick records one more file and invokes the callback on interval boundaries.
+func (p *progressCounter) tick() {
+	p.seen++
+	if p.onProgress != nil && p.seen%p.interval == 0 {
+		p.onProgress(p.seen)
+	}
+}
@@ -0,0 +1,78 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestAnalyzeRepositoryWithOptions_Progress(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 25; i++ {
+		name := filepath.Join(dir, fmt.Sprintf("file%02d.go", i))
+		if err := os.WriteFile(name, []byte("package x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var calls []int
+	opts := ScanOptions{
+		ProgressInterval: 10,
+		OnProgress:       func(filesSeen int) { calls = append(calls, filesSeen) },
+	}
+	repo := Repository{Path: dir, Name: "progress"}
+	analysis, err := AnalyzeRepositoryWithOptions(context.Background(), repo, opts, logger.New(false))
+	if err != nil {
+		t.Fatalf("AnalyzeRepositoryWithOptions() error = %v", err)
+	}
+	if analysis.TotalFiles != 25 {
+		t.Fatalf("TotalFiles = %d, want 25", analysis.TotalFiles)
+	}
+	if want := []int{10, 20}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("progress calls = %v, want %v", calls, want)
+	}
+}
+
+func TestProgressCounter(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval int
+		ticks    int
+		want     []int
+	}{
+		{"below interval", 5, 4, nil},
+		{"exact multiples", 5, 15, []int{5, 10, 15}},
+		{"default interval", 0, DefaultProgressInterval + 1, []int{DefaultProgressInterval}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			p := newProgressCounter(ScanOptions{
+				ProgressInterval: tt.interval,
+				OnProgress:       func(n int) { got = append(got, n) },
+			})
+			for i := 0; i < tt.ticks; i++ {
+				p.tick()
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("calls = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgressCounter_NilCallback(t *testing.T) {
+	p := newProgressCounter(ScanOptions{ProgressInterval: 1})
+	for i := 0; i < 3; i++ {
+		p.tick() // must not panic
+	}
+	if p.seen != 3 {
+		t.Errorf("seen = %d, want 3", p.seen)
+	}
+}
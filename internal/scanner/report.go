@@ -0,0 +1,173 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ScanReportSchemaVersion identifies the ScanReport JSON layout. Bump it on
+// any incompatible change to the struct tags below.
+const ScanReportSchemaVersion = "1"
+
+// ScanReportFileName is the file a ScanReport is written to.
+const ScanReportFileName = "scan-report.json"
+
+// ScanReport is the machine-readable result of a scan, for CI gating and
+// other tooling. Field names and JSON tags form a stable schema.
+type ScanReport struct {
+	SchemaVersion string                   `json:"schema_version"`
+	GeneratedAt   time.Time                `json:"generated_at"`
+	TargetPath    string                   `json:"target_path"`
+	Fingerprint   string                   `json:"fingerprint"`
+	TotalFiles    int                      `json:"total_files"`
+	TotalLines    int                      `json:"total_lines"`
+	Languages     map[string]LanguageStats `json:"languages"`
+	Frameworks    []string                 `json:"frameworks"`
+	Dependencies  map[string]string        `json:"dependencies"`
+	Repositories  []RepositoryReport       `json:"repositories"`
+}
+
+// LanguageStats counts the files and lines of code in one language.
+type LanguageStats struct {
+	Files int `json:"files"`
+	Lines int `json:"lines"`
+}
+
+// RepositoryReport is the per-repository section of a ScanReport.
+type RepositoryReport struct {
+	Name            string                   `json:"name"`
+	Path            string                   `json:"path"`
+	RelativePath    string                   `json:"relative_path"`
+	DefaultBranch   string                   `json:"default_branch,omitempty"`
+	RemoteURL       string                   `json:"remote_url,omitempty"`
+	License         string                   `json:"license,omitempty"`
+	PrimaryLanguage string                   `json:"primary_language"`
+	TotalFiles      int                      `json:"total_files"`
+	TotalLines      int                      `json:"total_lines"`
+	TestFiles       int                      `json:"test_files"`
+	Languages       map[string]LanguageStats `json:"languages"`
+	Frameworks      []string                 `json:"frameworks"`
+	SkippedPaths    []string                 `json:"skipped_paths,omitempty"`
+	Directories     []string                 `json:"directories,omitempty"`
+	Services        []Service                `json:"services,omitempty"`
+}
+
+// reportDirectoryDepth bounds how deep RepositoryReport.Directories, the
+// directories holding analyzed files, goes: enough to show the top-level
+// structure while keeping the report small.
+const reportDirectoryDepth = 2
+
+// NewScanReport builds a ScanReport from per-repository analyses.
+func NewScanReport(targetPath, fingerprint string, analyses []*RepositoryAnalysis) *ScanReport {
+	report := &ScanReport{
+		SchemaVersion: ScanReportSchemaVersion,
+		GeneratedAt:   time.Now().UTC(),
+		TargetPath:    targetPath,
+		Fingerprint:   fingerprint,
+		Languages:     make(map[string]LanguageStats),
+		Frameworks:    []string{},
+		Dependencies:  make(map[string]string),
+		Repositories:  []RepositoryReport{},
+	}
+
+	frameworks := make(map[string]bool)
+	for _, a := range analyses {
+		repo := RepositoryReport{
+			Name:            a.Repository.Name,
+			Path:            a.Repository.Path,
+			RelativePath:    a.Repository.RelativePath,
+			DefaultBranch:   a.Repository.DefaultBranch,
+			RemoteURL:       a.Repository.RemoteURL,
+			License:         a.Repository.License,
+			PrimaryLanguage: a.PrimaryLanguage(),
+			TotalFiles:      a.TotalFiles,
+			TotalLines:      a.TotalLines,
+			TestFiles:       a.TestFiles,
+			Languages:       languageStats(a),
+			Frameworks:      append([]string{}, a.Frameworks...),
+			SkippedPaths:    a.SkippedPaths,
+			Directories:     reportDirectories(a),
+			Services:        a.Services,
+		}
+		report.Repositories = append(report.Repositories, repo)
+
+		report.TotalFiles += a.TotalFiles
+		report.TotalLines += a.TotalLines
+		for lang, stats := range repo.Languages {
+			total := report.Languages[lang]
+			total.Files += stats.Files
+			total.Lines += stats.Lines
+			report.Languages[lang] = total
+		}
+		for _, f := range a.Frameworks {
+			frameworks[f] = true
+		}
+		for name, version := range a.Dependencies {
+			report.Dependencies[name] = version
+		}
+	}
+
+	for f := range frameworks {
+		report.Frameworks = append(report.Frameworks, f)
+	}
+	sort.Strings(report.Frameworks)
+	return report
+}
+
+// languageStats combines an analysis' file and line counts per language.
+func languageStats(a *RepositoryAnalysis) map[string]LanguageStats {
+	stats := make(map[string]LanguageStats, len(a.Languages))
+	for lang, files := range a.Languages {
+		stats[lang] = LanguageStats{Files: files, Lines: a.LinesByLanguage[lang]}
+	}
+	return stats
+}
+
+// reportDirectories returns the sorted directories, at most
+// reportDirectoryDepth levels deep, that contain the analysis' files.
+func reportDirectories(a *RepositoryAnalysis) []string {
+	seen := make(map[string]bool)
+	for file := range a.Files {
+		parts := strings.Split(file, "/")
+		parts = parts[:len(parts)-1]
+		if len(parts) > reportDirectoryDepth {
+			parts = parts[:reportDirectoryDepth]
+		}
+		for i := range parts {
+			seen[strings.Join(parts[:i+1], "/")] = true
+		}
+	}
+
+	var dirs []string
+	for dir := range seen {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// MarshalScanReport encodes r as indented JSON.
+func MarshalScanReport(r *ScanReport) ([]byte, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal scan report: %w", err)
+	}
+	return data, nil
+}
+
+// LoadScanReport reads a ScanReport previously written to path.
+func LoadScanReport(path string) (*ScanReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scan report: %w", err)
+	}
+	var r ScanReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse scan report: %w", err)
+	}
+	return &r, nil
+}
@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func sampleAnalyses() []*RepositoryAnalysis {
+	return []*RepositoryAnalysis{
+		{
+			Repository:      Repository{Name: "api", Path: "/src/api", RelativePath: "api", License: "MIT"},
+			Languages:       map[string]int{"Go": 3},
+			LinesByLanguage: map[string]int{"Go": 120},
+			TotalFiles:      4,
+			TotalLines:      120,
+			TestFiles:       1,
+			Frameworks:      []string{"Gin"},
+		},
+		{
+			Repository:      Repository{Name: "web", Path: "/src/web", RelativePath: "web"},
+			Languages:       map[string]int{"Go": 1, "TypeScript": 5},
+			LinesByLanguage: map[string]int{"Go": 10, "TypeScript": 300},
+			TotalFiles:      6,
+			TotalLines:      310,
+			Frameworks:      []string{"React", "Gin"},
+		},
+	}
+}
+
+func TestNewScanReport(t *testing.T) {
+	r := NewScanReport("/src", "sha256:abc", sampleAnalyses())
+
+	if r.SchemaVersion != ScanReportSchemaVersion {
+		t.Errorf("SchemaVersion = %q, want %q", r.SchemaVersion, ScanReportSchemaVersion)
+	}
+	if r.TotalFiles != 10 || r.TotalLines != 430 {
+		t.Errorf("totals = %d files, %d lines, want 10, 430", r.TotalFiles, r.TotalLines)
+	}
+	if got, want := r.Languages["Go"], (LanguageStats{Files: 4, Lines: 130}); got != want {
+		t.Errorf("Languages[Go] = %+v, want %+v", got, want)
+	}
+	if want := []string{"Gin", "React"}; !reflect.DeepEqual(r.Frameworks, want) {
+		t.Errorf("Frameworks = %v, want %v", r.Frameworks, want)
+	}
+	if len(r.Repositories) != 2 || r.Repositories[0].PrimaryLanguage != "Go" {
+		t.Errorf("Repositories = %+v", r.Repositories)
+	}
+}
+
+func TestScanReport_RoundTrip(t *testing.T) {
+	original := NewScanReport("/src", "sha256:abc", sampleAnalyses())
+	data, err := MarshalScanReport(original)
+	if err != nil {
+		t.Fatalf("MarshalScanReport() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), ScanReportFileName)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadScanReport(path)
+	if err != nil {
+		t.Fatalf("LoadScanReport() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded, original) {
+		t.Errorf("round trip mismatch:\n got  %+v\n want %+v", loaded, original)
+	}
+
+	// The documented snake_case schema keys must be present.
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"schema_version", "fingerprint", "total_files", "total_lines", "languages", "frameworks", "repositories"} {
+		if _, ok := raw[key]; !ok {
+			t.Errorf("JSON missing key %q", key)
+		}
+	}
+}
+
+func TestNewScanReport_Empty(t *testing.T) {
+	data, err := MarshalScanReport(NewScanReport("/src", "", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r ScanReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Frameworks == nil || r.Repositories == nil {
+		t.Error("empty report should encode empty arrays, not null")
+	}
+}
+
+func TestReportDirectories(t *testing.T) {
+	a := &RepositoryAnalysis{Files: map[string]FileStat{
+		"main.go":                  {},
+		"cmd/api/main.go":          {},
+		"internal/store/db/sql.go": {},
+		"docs/README.md":           {},
+	}}
+	want := []string{"cmd", "cmd/api", "docs", "internal", "internal/store"}
+	if got := reportDirectories(a); !reflect.DeepEqual(got, want) {
+		t.Errorf("reportDirectories() = %q, want %q", got, want)
+	}
+}
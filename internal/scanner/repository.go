@@ -0,0 +1,51 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// OpenRepository describes the git repository at repoPath without
+// searching for others, for callers that already know which repositories
+// to analyze. RelativePath is computed against rootPath. A repoPath that
+// does not exist yields an error wrapping ErrPathNotFound; one without a
+// .git directory or file yields an error wrapping ErrNotRepo.
+func OpenRepository(repoPath, rootPath string, log *logger.Logger) (Repository, error) {
+	info, err := os.Stat(repoPath)
+	if err != nil {
+		return Repository{}, rootError(repoPath, err)
+	}
+	if !info.IsDir() {
+		return Repository{}, fmt.Errorf("%w: %s is not a directory", ErrNotRepo, repoPath)
+	}
+	if _, err := os.Stat(filepath.Join(repoPath, ".git")); err != nil {
+		return Repository{}, fmt.Errorf("%w: %s has no .git", ErrNotRepo, repoPath)
+	}
+	return newRepository(repoPath, rootPath, log), nil
+}
+
+// newRepository reads the metadata of the repository at repoPath.
+func newRepository(repoPath, rootPath string, log *logger.Logger) Repository {
+	relPath, _ := filepath.Rel(rootPath, repoPath)
+
+	submodules, err := ParseSubmodules(repoPath)
+	if err != nil {
+		log.Warn("Failed to parse submodules for %s: %v", repoPath, err)
+	}
+
+	repo := Repository{
+		Path:          repoPath,
+		Name:          filepath.Base(repoPath),
+		RelativePath:  relPath,
+		HasSubmodules: len(submodules) > 0,
+		Submodules:    submodules,
+		DefaultBranch: readDefaultBranch(repoPath),
+		RemoteURL:     readRemoteURL(repoPath),
+		License:       DetectLicense(repoPath),
+	}
+	repo.LastCommitHash, repo.LastCommitDate = readLastCommit(repoPath)
+	return repo
+}
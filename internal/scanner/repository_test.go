@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestOpenRepository(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"api/.git/HEAD": "ref: refs/heads/main\n",
+		"api/main.go":   "package main\n",
+		"worktree/.git": "gitdir: ../api/.git/worktrees/w\n",
+		"plain/README":  "docs\n",
+		"file-not-dir":  "x\n",
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		wantBranch string
+		wantErr    error
+	}{
+		{"repository", "api", "main", nil},
+		{"gitlink file", "worktree", "", nil},
+		{"no .git", "plain", "", ErrNotRepo},
+		{"file", "file-not-dir", "", ErrNotRepo},
+		{"missing", "missing", "", ErrPathNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := OpenRepository(filepath.Join(root, tt.path), root, logger.New(false))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("OpenRepository() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OpenRepository() error = %v", err)
+			}
+			if repo.Name != tt.path || repo.RelativePath != tt.path {
+				t.Errorf("OpenRepository() = %q at %q, want %q", repo.Name, repo.RelativePath, tt.path)
+			}
+			if repo.DefaultBranch != tt.wantBranch {
+				t.Errorf("DefaultBranch = %q, want %q", repo.DefaultBranch, tt.wantBranch)
+			}
+		})
+	}
+}
+
+func TestOpenRepository_MatchesDiscovery(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"svc/.git/HEAD": "ref: refs/heads/trunk\n",
+		"svc/LICENSE":   "MIT License\n",
+	})
+	found, err := FindGitRepos(root, logger.New(false))
+	if err != nil || len(found) != 1 {
+		t.Fatalf("FindGitRepos() = %v, %v", found, err)
+	}
+	repo, err := OpenRepository(filepath.Join(root, "svc"), root, logger.New(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.Path != found[0].Path || repo.DefaultBranch != found[0].DefaultBranch || repo.License != found[0].License {
+		t.Errorf("OpenRepository() = %+v, want %+v", repo, found[0])
+	}
+}
@@ -0,0 +1,160 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RiskSeverity ranks a RiskArea; higher values are more severe.
+type RiskSeverity int
+
+// Risk severities, from least to most severe.
+const (
+	RiskLow RiskSeverity = iota + 1
+	RiskMedium
+	RiskHigh
+)
+
+// String returns the severity's lower-case name.
+func (s RiskSeverity) String() string {
+	switch s {
+	case RiskLow:
+		return "low"
+	case RiskMedium:
+		return "medium"
+	case RiskHigh:
+		return "high"
+	}
+	return "unknown"
+}
+
+// Risk signal names used in RiskArea.Signal.
+const (
+	RiskLowTestRatio = "low test ratio"
+	RiskMarkers      = "TODO/FIXME markers"
+	RiskLargeFiles   = "large files"
+	RiskSecrets      = "potential secrets"
+)
+
+// RiskArea is one signal that a repository deserves extra review attention.
+type RiskArea struct {
+	Repository string
+	Signal     string
+	Severity   RiskSeverity
+	Detail     string
+}
+
+// Thresholds for the risk signals assessed by AssessRisks.
+const (
+	// minCodeFilesForRatio is the number of code files below which the
+	// test ratio is too noisy to report.
+	minCodeFilesForRatio = 5
+	// lowTestRatio and sparseTestRatio bound test files per code file for
+	// medium and low severity; a repository with none at all is high.
+	lowTestRatio    = 0.1
+	sparseTestRatio = 0.25
+	// Marker counts at which the markers signal reaches each severity.
+	markersLow    = 5
+	markersMedium = 20
+	markersHigh   = 50
+	// largeFileLines is the line count at which a hand-written text file
+	// counts as large; largeFilesMedium and largeFilesHigh are the number
+	// of such files for each severity above low.
+	largeFileLines   = 1000
+	largeFilesMedium = 2
+	largeFilesHigh   = 5
+)
+
+// AssessRisks returns the risk signals raised by an analysis: a low ratio
+// of test files to code files, many TODO/FIXME markers, and large
+// hand-written files. Generated and binary files are not counted as large.
+// Potential secrets, when scanned for, are always high severity.
+func AssessRisks(a *RepositoryAnalysis) []RiskArea {
+	if a == nil {
+		return nil
+	}
+	var risks []RiskArea
+	add := func(signal string, severity RiskSeverity, detail string) {
+		risks = append(risks, RiskArea{Repository: a.Repository.Name, Signal: signal, Severity: severity, Detail: detail})
+	}
+
+	if a.CodeFiles >= minCodeFilesForRatio {
+		ratio := a.TestToCodeRatio()
+		detail := fmt.Sprintf("%d test files for %d code files", a.TestFiles, a.CodeFiles)
+		switch {
+		case a.TestFiles == 0:
+			add(RiskLowTestRatio, RiskHigh, detail)
+		case ratio < lowTestRatio:
+			add(RiskLowTestRatio, RiskMedium, detail)
+		case ratio < sparseTestRatio:
+			add(RiskLowTestRatio, RiskLow, detail)
+		}
+	}
+
+	if severity := markerSeverity(a.Markers); severity != 0 {
+		add(RiskMarkers, severity, fmt.Sprintf("%d found", a.Markers))
+	}
+
+	if large := largeFiles(a.Files); len(large) > 0 {
+		severity := RiskLow
+		switch {
+		case len(large) >= largeFilesHigh:
+			severity = RiskHigh
+		case len(large) >= largeFilesMedium:
+			severity = RiskMedium
+		}
+		add(RiskLargeFiles, severity, fmt.Sprintf("%d files over %d lines, largest %s (%d lines)",
+			len(large), largeFileLines, large[0], a.Files[large[0]].Lines))
+	}
+
+	if len(a.Secrets) > 0 {
+		add(RiskSecrets, RiskHigh, fmt.Sprintf("%d found: %s", len(a.Secrets), secretKindsSummary(a.Secrets)))
+	}
+	return risks
+}
+
+// markerSeverity maps a marker count to a severity, or 0 when the count is
+// below markersLow.
+func markerSeverity(markers int) RiskSeverity {
+	switch {
+	case markers >= markersHigh:
+		return RiskHigh
+	case markers >= markersMedium:
+		return RiskMedium
+	case markers >= markersLow:
+		return RiskLow
+	}
+	return 0
+}
+
+// largeFiles returns the paths of hand-written text files with at least
+// largeFileLines lines, largest first, ties broken by path.
+func largeFiles(files map[string]FileStat) []string {
+	var paths []string
+	for path, stat := range files {
+		if !stat.Binary && !stat.Generated && stat.Lines >= largeFileLines {
+			paths = append(paths, path)
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if files[paths[i]].Lines != files[paths[j]].Lines {
+			return files[paths[i]].Lines > files[paths[j]].Lines
+		}
+		return paths[i] < paths[j]
+	})
+	return paths
+}
+
+// RankRisks assesses every analysis and returns the combined risk areas,
+// most severe first. Areas of equal severity keep the order of analyses
+// and, within a repository, the order AssessRisks reports them in.
+func RankRisks(analyses []*RepositoryAnalysis) []RiskArea {
+	var risks []RiskArea
+	for _, a := range analyses {
+		risks = append(risks, AssessRisks(a)...)
+	}
+	sort.SliceStable(risks, func(i, j int) bool {
+		return risks[i].Severity > risks[j].Severity
+	})
+	return risks
+}
@@ -0,0 +1,124 @@
+package scanner
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// largeFileStats returns n files of the given line count, named big0.go
+// onwards.
+func largeFileStats(n, lines int) map[string]FileStat {
+	files := make(map[string]FileStat, n)
+	for i := 0; i < n; i++ {
+		files[fmt.Sprintf("big%d.go", i)] = FileStat{Language: "Go", Lines: lines}
+	}
+	return files
+}
+
+func TestAssessRisks(t *testing.T) {
+	tests := []struct {
+		name     string
+		analysis RepositoryAnalysis
+		want     map[string]RiskSeverity
+	}{
+		{"healthy", RepositoryAnalysis{CodeFiles: 10, TestFiles: 5, Markers: 2}, map[string]RiskSeverity{}},
+		{"no tests", RepositoryAnalysis{CodeFiles: 10}, map[string]RiskSeverity{RiskLowTestRatio: RiskHigh}},
+		{"few tests", RepositoryAnalysis{CodeFiles: 20, TestFiles: 1}, map[string]RiskSeverity{RiskLowTestRatio: RiskMedium}},
+		{"sparse tests", RepositoryAnalysis{CodeFiles: 10, TestFiles: 2}, map[string]RiskSeverity{RiskLowTestRatio: RiskLow}},
+		{"too little code to judge", RepositoryAnalysis{CodeFiles: 4}, map[string]RiskSeverity{}},
+		{"some markers", RepositoryAnalysis{Markers: markersLow}, map[string]RiskSeverity{RiskMarkers: RiskLow}},
+		{"many markers", RepositoryAnalysis{Markers: markersHigh}, map[string]RiskSeverity{RiskMarkers: RiskHigh}},
+		{"one large file", RepositoryAnalysis{Files: largeFileStats(1, largeFileLines)}, map[string]RiskSeverity{RiskLargeFiles: RiskLow}},
+		{"many large files", RepositoryAnalysis{Files: largeFileStats(largeFilesHigh, 5000)}, map[string]RiskSeverity{RiskLargeFiles: RiskHigh}},
+		{"generated files are not large", RepositoryAnalysis{Files: map[string]FileStat{
+			"gen.pb.go": {Language: "Go", Lines: 9000, Generated: true},
+		}}, map[string]RiskSeverity{}},
+		{"one potential secret", RepositoryAnalysis{Secrets: []SecretFinding{{Path: ".env", Line: 1, Kind: SecretAssignment}}},
+			map[string]RiskSeverity{RiskSecrets: RiskHigh}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]RiskSeverity)
+			for _, r := range AssessRisks(&tt.analysis) {
+				got[r.Signal] = r.Severity
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AssessRisks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssessRisks_LargeFileDetail(t *testing.T) {
+	a := &RepositoryAnalysis{Files: map[string]FileStat{
+		"a.go": {Language: "Go", Lines: 1200},
+		"b.go": {Language: "Go", Lines: 3000},
+		"c.go": {Language: "Go", Lines: 10},
+	}}
+	risks := AssessRisks(a)
+	if len(risks) != 1 {
+		t.Fatalf("AssessRisks() = %v, want one large-files area", risks)
+	}
+	if want := "2 files over 1000 lines, largest b.go (3000 lines)"; risks[0].Detail != want {
+		t.Errorf("Detail = %q, want %q", risks[0].Detail, want)
+	}
+}
+
+func TestRankRisks_OrdersBySeverity(t *testing.T) {
+	analyses := []*RepositoryAnalysis{
+		{Repository: Repository{Name: "api"}, CodeFiles: 10, TestFiles: 2, Markers: markersMedium},
+		nil,
+		{Repository: Repository{Name: "web"}, CodeFiles: 10, Files: largeFileStats(largeFilesMedium, 2000)},
+	}
+
+	var got []string
+	for _, r := range RankRisks(analyses) {
+		got = append(got, fmt.Sprintf("%s %s %s", r.Severity, r.Repository, r.Signal))
+	}
+	want := []string{
+		"high web low test ratio",
+		"medium api TODO/FIXME markers",
+		"medium web large files",
+		"low api low test ratio",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RankRisks() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestRiskSeverityString(t *testing.T) {
+	tests := []struct {
+		severity RiskSeverity
+		want     string
+	}{
+		{RiskLow, "low"},
+		{RiskMedium, "medium"},
+		{RiskHigh, "high"},
+		{0, "unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.severity.String(); got != tt.want {
+			t.Errorf("RiskSeverity(%d).String() = %q, want %q", tt.severity, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeRepository_CountsMarkers(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"main.go":   "package main\n// TODO: handle errors\n// FIXME leaks\n",
+		"README.md": "TODO write docs\n",
+		"logo.png":  "\x89PNG\x00TODO",
+	})
+	analysis, err := AnalyzeRepository(Repository{Path: dir, Name: "app"}, logger.New(false))
+	if err != nil {
+		t.Fatalf("AnalyzeRepository() error = %v", err)
+	}
+	if analysis.Markers != 3 {
+		t.Errorf("Markers = %d, want 3 (binary file skipped)", analysis.Markers)
+	}
+}
@@ -1,9 +1,13 @@
 package scanner
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/bordenet/codebase-reviewer/pkg/logger"
 )
@@ -14,35 +18,73 @@ type Repository struct {
 	Name          string
 	RelativePath  string
 	HasSubmodules bool
+	Submodules    []Submodule
+	// DefaultBranch is the branch checked out per .git/HEAD; empty when
+	// HEAD is detached.
+	DefaultBranch string
+	// RemoteURL is the origin (or first) remote from .git/config, with any
+	// credentials removed; empty when no remote is configured.
+	RemoteURL string
+	// LastCommitHash and LastCommitDate describe the newest .git/logs/HEAD
+	// entry; both are zero when the log is absent.
+	LastCommitHash string
+	LastCommitDate time.Time
+	// License is the SPDX identifier of the root license file, "unknown"
+	// when unrecognized, or empty when there is none.
+	License string
 }
 
 // FindGitRepos recursively finds all git repositories under the given path.
 // It skips hidden directories except .git and returns a slice of Repository.
-// An empty slice is returned if no repositories are found.
+// An empty slice is returned if no repositories are found; a rootPath that
+// is missing or unreadable yields an error wrapping ErrPathNotFound or
+// ErrNotReadable.
 func FindGitRepos(rootPath string, log *logger.Logger) ([]Repository, error) {
+	return FindGitReposWithOptions(context.Background(), rootPath, ScanOptions{}, log)
+}
+
+// FindGitReposWithOptions is FindGitRepos with tunable ScanOptions. The walk
+// stops early with ctx.Err() when ctx is cancelled. A repository whose
+// resolved path or remote URL matches one already found is skipped, so the
+// first occurrence wins.
+func FindGitReposWithOptions(ctx context.Context, rootPath string, opts ScanOptions, log *logger.Logger) ([]Repository, error) {
 	var repos []Repository
+	progress := newProgressCounter(opts)
+	dedupe := newRepoDeduper()
 
-	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+	err := walkTree(rootPath, opts, func(path string, info os.FileInfo, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		if err != nil {
+			if path == rootPath {
+				return rootError(rootPath, err)
+			}
 			log.Warn("Error accessing path %s: %v", path, err)
 			return nil // Continue walking
 		}
 
+		if !info.IsDir() {
+			progress.tick()
+		}
+
 		// Skip hidden directories except .git
 		if info.IsDir() && len(info.Name()) > 0 && info.Name()[0] == '.' && info.Name() != ".git" {
 			return filepath.SkipDir
 		}
 
+		if info.IsDir() && path != rootPath && opts.skipsConfiguredDir(info.Name()) {
+			return filepath.SkipDir
+		}
+
 		// Check if this is a .git directory
 		if info.IsDir() && info.Name() == ".git" {
 			repoPath := filepath.Dir(path)
-			relPath, _ := filepath.Rel(rootPath, repoPath)
+			repo := newRepository(repoPath, rootPath, log)
 
-			repo := Repository{
-				Path:          repoPath,
-				Name:          filepath.Base(repoPath),
-				RelativePath:  relPath,
-				HasSubmodules: hasSubmodules(repoPath),
+			if first, reason, dup := dedupe.duplicate(repo); dup {
+				log.Debug("Skipping duplicate repository %s (same %s as %s)", repoPath, reason, first)
+				return filepath.SkipDir
 			}
 
 			repos = append(repos, repo)
@@ -52,6 +94,12 @@ func FindGitRepos(rootPath string, log *logger.Logger) ([]Repository, error) {
 			return filepath.SkipDir
 		}
 
+		// A repository at MaxDepth is still found through its .git child
+		// above, but nothing deeper is descended into.
+		if info.IsDir() && opts.MaxDepth > 0 && depthBelow(rootPath, path) > opts.MaxDepth {
+			return filepath.SkipDir
+		}
+
 		return nil
 	})
 
@@ -62,69 +110,172 @@ func FindGitRepos(rootPath string, log *logger.Logger) ([]Repository, error) {
 	return repos, nil
 }
 
-// hasSubmodules checks if a repository has git submodules
-func hasSubmodules(repoPath string) bool {
-	submodulesPath := filepath.Join(repoPath, ".gitmodules")
-	_, err := os.Stat(submodulesPath)
-	return err == nil
+// depthBelow returns how many directory levels path is below root.
+func depthBelow(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
 }
 
-// AnalyzeRepository performs a detailed analysis of a repository
+// AnalyzeRepository performs a detailed analysis of a repository. A
+// repository path that is missing or unreadable yields an error wrapping
+// ErrPathNotFound or ErrNotReadable.
 func AnalyzeRepository(repo Repository, log *logger.Logger) (*RepositoryAnalysis, error) {
+	return AnalyzeRepositoryWithOptions(context.Background(), repo, ScanOptions{}, log)
+}
+
+// AnalyzeRepositoryWithOptions is AnalyzeRepository with tunable ScanOptions.
+// The walk stops early with ctx.Err() when ctx is cancelled.
+func AnalyzeRepositoryWithOptions(ctx context.Context, repo Repository, opts ScanOptions, log *logger.Logger) (*RepositoryAnalysis, error) {
 	log.Debug("Analyzing repository: %s", repo.Name)
+	progress := newProgressCounter(opts)
 
 	analysis := &RepositoryAnalysis{
-		Repository: repo,
-		Languages:  make(map[string]int),
-		FileTypes:  make(map[string]int),
+		Repository:        repo,
+		Languages:         make(map[string]int),
+		FileTypes:         make(map[string]int),
+		UnclassifiedTypes: make(map[string]int),
+		LinesByLanguage:   make(map[string]int),
+		Files:             make(map[string]FileStat),
+	}
+
+	ignore, err := loadIgnoreFile(filepath.Join(repo.Path, IgnoreFileName))
+	if err != nil {
+		log.Warn("Failed to read %s in %s; ignoring it: %v", IgnoreFileName, repo.Name, err)
 	}
 
 	// Count files by language/type
-	err := filepath.Walk(repo.Path, func(path string, info os.FileInfo, err error) error {
+	w := &analysisWalker{ctx: ctx, repo: repo, opts: opts, log: log, ignore: ignore, progress: progress, analysis: analysis}
+	if opts.WalkWorkers > 1 && !opts.FollowSymlinks {
+		err = walkParallel(repo.Path, opts.WalkWorkers, w.visit)
+		sort.Slice(analysis.SkippedPaths, func(i, j int) bool {
+			return walkOrderLess(analysis.SkippedPaths[i], analysis.SkippedPaths[j])
+		})
+	} else {
+		err = walkTree(repo.Path, opts, w.visit)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze repository: %w", err)
+	}
+
+	analysis.IsEmpty = isEmptyRepo(repo.Path)
+	analysis.LargeFiles = findLargeFiles(analysis.Files, opts.largeFileThreshold())
+	if n := len(analysis.LargeFiles); n > 0 {
+		log.Warn("%s: %d file(s) over %d bytes, largest %s", repo.Name, n, opts.largeFileThreshold(), analysis.LargeFiles[0].Path)
+	}
+	analysis.Secrets = collectSecrets(analysis.Files)
+	if n := len(analysis.Secrets); n > 0 {
+		log.Warn("%s: %d potential secret(s), first in %s", repo.Name, n, analysis.Secrets[0].Path)
+	}
+	if analysis.Languages[goLanguage] > 0 {
+		analysis.ExportedFuncs, analysis.ExportedTypes, err = CountExportedSymbols(repo)
 		if err != nil {
-			return nil
+			log.Warn("Exported symbol counts for %s are partial: %v", repo.Name, err)
 		}
+		analysis.GoModules = FindGoModules(repo)
+	}
+	analysis.Tooling = DetectTooling(repo)
+	analysis.Frameworks = DetectFrameworks(repo)
+	analysis.Services = DetectServices(repo)
+	analysis.Docs = InventoryDocs(repo)
+	analysis.Dependencies = DependencyVersions(repo)
 
-		// Skip hidden directories and common ignore patterns
-		if info.IsDir() {
-			name := info.Name()
-			if len(name) > 0 && name[0] == '.' {
-				return filepath.SkipDir
-			}
-			if name == "node_modules" || name == "vendor" || name == "dist" || name == "build" {
-				return filepath.SkipDir
-			}
-		}
+	return analysis, nil
+}
 
-		if !info.IsDir() {
-			ext := filepath.Ext(path)
-			if ext != "" {
-				analysis.FileTypes[ext]++
-
-				// Map extension to language
-				if lang := extensionToLanguage(ext); lang != "" {
-					analysis.Languages[lang]++
-				}
-			}
-			analysis.TotalFiles++
-		}
+// recordSkipped notes that path, inside the repository at root, could not
+// be read.
+func (a *RepositoryAnalysis) recordSkipped(root, path string) {
+	a.SkippedPaths = append(a.SkippedPaths, relativeTo(root, path))
+}
 
-		return nil
-	})
+// gitDir is a repository's metadata directory, or in a worktree or
+// submodule checkout the file pointing at it. It is never analyzed: its
+// object store would swamp the working tree's counts.
+const gitDir = ".git"
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to analyze repository: %w", err)
+// skipAnalysisDir reports whether a directory is excluded from analysis:
+// .git, other hidden directories unless includeHidden is set, and common
+// dependency/build output directories.
+func skipAnalysisDir(name string, includeHidden bool) bool {
+	if name == gitDir {
+		return true
 	}
-
-	return analysis, nil
+	if !includeHidden && len(name) > 0 && name[0] == '.' {
+		return true
+	}
+	return name == "node_modules" || name == "vendor" || name == "dist" || name == "build"
 }
 
 // RepositoryAnalysis contains analysis results for a repository
 type RepositoryAnalysis struct {
-	Repository Repository
-	Languages  map[string]int
-	FileTypes  map[string]int
-	TotalFiles int
+	Repository  Repository
+	Languages   map[string]int
+	FileTypes   map[string]int
+	TotalFiles  int
+	BinaryFiles int
+	TextFiles   int
+	// UnclassifiedTypes counts, by extension, the files in FileTypes whose
+	// extension maps to no language, so missing mappings can be requested
+	// or added through ScanOptions.LangMap.
+	UnclassifiedTypes map[string]int
+	// TestFiles counts files named by a test convention; CodeFiles counts
+	// the remaining files in programming languages.
+	TestFiles int
+	CodeFiles int
+	// LinesByLanguage and TotalLines count lines in text files of a
+	// recognized language.
+	LinesByLanguage map[string]int
+	TotalLines      int
+	// Tooling lists the build, CI, and container systems detected.
+	Tooling Tooling
+	// Frameworks lists application frameworks found in dependency manifests.
+	Frameworks []string
+	// Services lists the service boundaries found by DetectServices.
+	Services []Service
+	// Docs lists the documentation found by InventoryDocs.
+	Docs DocInventory
+	// Dependencies maps each dependency declared in the root manifests to
+	// its declared version (see DependencyVersions).
+	Dependencies map[string]string
+	// GeneratedFiles counts text files detected as generated (see
+	// IsGenerated); GeneratedByLanguage breaks them down by language. They
+	// remain included in Languages.
+	GeneratedFiles      int
+	GeneratedByLanguage map[string]int
+	// Markers counts TODO and FIXME comments across text files.
+	Markers int
+	// LargeFiles lists the files above ScanOptions.LargeFileThreshold,
+	// largest first.
+	LargeFiles []LargeFile
+	// Secrets lists the potential secrets found when
+	// ScanOptions.ScanSecrets is set, by path and line. Matches are
+	// redacted.
+	Secrets []SecretFinding
+	// SkippedPaths lists paths, relative to the repository, that could not
+	// be read during analysis (for example due to permissions), so the
+	// counts above are incomplete when it is non-empty.
+	SkippedPaths []string
+	// IgnoredFiles counts the paths excluded by the repository's
+	// IgnoreFileName; an ignored directory counts once.
+	IgnoredFiles int
+	// Files records each analyzed file's contribution, keyed by its
+	// slash-separated path relative to the repository.
+	Files map[string]FileStat
+	// ExportedFuncs and ExportedTypes count the exported top-level Go
+	// functions and types (see CountExportedSymbols); both are zero for
+	// repositories without Go files.
+	ExportedFuncs int
+	ExportedTypes int
+	// GoModules lists the Go modules found by FindGoModules, so module
+	// boundaries are known when a repository holds several.
+	GoModules []GoModule
+	// IsEmpty is set when the repository holds .git and nothing else, so
+	// the zero counts above reflect an empty tree rather than a failed scan.
+	IsEmpty bool
 }
 
 // extToLang maps file extensions to programming languages.
@@ -170,17 +321,17 @@ func extensionToLanguage(ext string) string {
 	return extToLang[ext]
 }
 
-// PrimaryLanguage returns the most common language in the analysis
-func (a *RepositoryAnalysis) PrimaryLanguage() string {
-	var maxLang string
-	var maxCount int
-
-	for lang, count := range a.Languages {
-		if count > maxCount {
-			maxCount = count
-			maxLang = lang
-		}
+// BinaryRatio returns the fraction of analyzed files that are binary,
+// or 0 when no files were analyzed.
+func (a *RepositoryAnalysis) BinaryRatio() float64 {
+	if a.TotalFiles == 0 {
+		return 0
 	}
+	return float64(a.BinaryFiles) / float64(a.TotalFiles)
+}
 
-	return maxLang
+// PrimaryLanguage returns the most common language in the analysis,
+// OtherLanguage aside. Ties go to the name that sorts first.
+func (a *RepositoryAnalysis) PrimaryLanguage() string {
+	return primaryLanguage(a.SortedLanguages())
 }
@@ -1,8 +1,12 @@
 package scanner
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/bordenet/codebase-reviewer/pkg/logger"
@@ -92,42 +96,6 @@ func TestFindGitRepos(t *testing.T) {
 	}
 }
 
-func TestHasSubmodules(t *testing.T) {
-	tests := []struct {
-		name  string
-		setup func(t *testing.T) string
-		want  bool
-	}{
-		{
-			name: "no submodules",
-			setup: func(t *testing.T) string {
-				return t.TempDir()
-			},
-			want: false,
-		},
-		{
-			name: "has submodules",
-			setup: func(t *testing.T) string {
-				dir := t.TempDir()
-				if err := os.WriteFile(filepath.Join(dir, ".gitmodules"), []byte("[submodule]"), 0644); err != nil {
-					t.Fatal(err)
-				}
-				return dir
-			},
-			want: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			dir := tt.setup(t)
-			if got := hasSubmodules(dir); got != tt.want {
-				t.Errorf("hasSubmodules() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
 func TestExtensionToLanguage(t *testing.T) {
 	tests := []struct {
 		ext  string
@@ -360,6 +328,11 @@ func TestPrimaryLanguage(t *testing.T) {
 			languages: map[string]int{"Go": 5, "Python": 100, "JavaScript": 20},
 			want:      "Python",
 		},
+		{
+			name:      "tie goes to the first name",
+			languages: map[string]int{"Rust": 7, "Go": 7, "Python": 3},
+			want:      "Go",
+		},
 	}
 
 	for _, tt := range tests {
@@ -373,3 +346,137 @@ func TestPrimaryLanguage(t *testing.T) {
 		})
 	}
 }
+
+func TestFindGitReposWithOptions_MaxDepth(t *testing.T) {
+	log := logger.New(false)
+
+	// Layout (depth of each repository directory in parentheses):
+	//   root/.git            (0)
+	//   root/a/.git          (1)
+	//   root/b/c/.git        (2)
+	//   root/d/e/f/.git      (3)
+	dir := t.TempDir()
+	for _, repo := range []string{".", "a", filepath.Join("b", "c"), filepath.Join("d", "e", "f")} {
+		if err := os.MkdirAll(filepath.Join(dir, repo, ".git"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		maxDepth  int
+		wantCount int
+	}{
+		{"unlimited", 0, 4},
+		{"depth 1", 1, 2},
+		{"depth 2", 2, 3},
+		{"depth 3", 3, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos, err := FindGitReposWithOptions(context.Background(), dir, ScanOptions{MaxDepth: tt.maxDepth}, log)
+			if err != nil {
+				t.Fatalf("FindGitReposWithOptions() error = %v", err)
+			}
+			if len(repos) != tt.wantCount {
+				t.Errorf("MaxDepth %d found %d repos, want %d", tt.maxDepth, len(repos), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestDepthBelow(t *testing.T) {
+	root := filepath.Join("/", "root")
+	tests := []struct {
+		path string
+		want int
+	}{
+		{root, 0},
+		{filepath.Join(root, "a"), 1},
+		{filepath.Join(root, "a", "b", "c"), 3},
+	}
+
+	for _, tt := range tests {
+		if got := depthBelow(root, tt.path); got != tt.want {
+			t.Errorf("depthBelow(%q, %q) = %d, want %d", root, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeRepositoryWithOptions_CancelMidWalk(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 20; i++ {
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%02d.go", i)), []byte("package x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	seen := 0
+	opts := ScanOptions{
+		ProgressInterval: 1,
+		OnProgress: func(filesSeen int) {
+			seen = filesSeen
+			if filesSeen == 5 {
+				cancel()
+			}
+		},
+	}
+
+	_, err := AnalyzeRepositoryWithOptions(ctx, Repository{Path: dir, Name: "big"}, opts, logger.New(false))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("AnalyzeRepositoryWithOptions() error = %v, want context.Canceled", err)
+	}
+	if seen != 5 {
+		t.Errorf("walk continued after cancellation: saw %d files, want 5", seen)
+	}
+}
+
+func TestFindGitReposWithOptions_Cancelled(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	repos, err := FindGitReposWithOptions(ctx, dir, ScanOptions{}, logger.New(false))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("FindGitReposWithOptions() error = %v, want context.Canceled", err)
+	}
+	if repos != nil {
+		t.Errorf("repos = %v, want nil on cancellation", repos)
+	}
+}
+
+func TestAnalyzeRepository_RecordsUnreadablePaths(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("directory permissions are not enforced on this platform or for root")
+	}
+
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"main.go":          "package main\n",
+		"locked/secret.go": "package locked\n",
+	})
+	locked := filepath.Join(dir, "locked")
+	if err := os.Chmod(locked, 0000); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(locked, 0755) })
+
+	analysis, err := AnalyzeRepository(Repository{Path: dir, Name: "shared"}, logger.New(false))
+	if err != nil {
+		t.Fatalf("AnalyzeRepository() error = %v", err)
+	}
+	if analysis.TotalFiles != 1 {
+		t.Errorf("TotalFiles = %d, want 1 (walk should continue past the locked dir)", analysis.TotalFiles)
+	}
+	if len(analysis.SkippedPaths) != 1 || analysis.SkippedPaths[0] != "locked" {
+		t.Errorf("SkippedPaths = %v, want [locked]", analysis.SkippedPaths)
+	}
+}
@@ -0,0 +1,172 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// Kinds of potential secret reported in SecretFinding.Kind.
+const (
+	SecretAWSAccessKey = "AWS access key"
+	SecretPrivateKey   = "private key"
+	SecretAssignment   = "credential assignment"
+)
+
+// SecretFinding is a potential secret committed to a repository. Match is
+// redacted: it identifies what was found without the secret itself.
+type SecretFinding struct {
+	// Path is slash-separated and relative to the repository. It is empty
+	// in a FileStat, which is already keyed by path.
+	Path  string `json:"path,omitempty"`
+	Line  int    `json:"line"`
+	Kind  string `json:"kind"`
+	Match string `json:"match"`
+}
+
+var (
+	// awsKeyPattern matches AWS access key IDs, long-term and temporary.
+	awsKeyPattern = regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)
+	// privateKeyPattern matches PEM and PGP private key headers.
+	privateKeyPattern = regexp.MustCompile(`-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----`)
+	// assignmentPattern matches a credential-named key assigned a literal
+	// of at least eight characters, quoted or ending the line, as in
+	// API_KEY=..., "api_key": "...", or password = '...'. Values built
+	// from variables or calls are not literals and do not match.
+	assignmentPattern = regexp.MustCompile(`(?i)\b([a-z0-9_]*(?:api_?key|secret_?key|secret|access_?token|auth_?token|password))["']?\s*[:=]\s*(?:"([^"\s]{8,})"|'([^'\s]{8,})'|([a-z0-9+/_.-]{8,})\s*$)`)
+)
+
+// secretMask replaces a redacted value, whatever its length.
+const secretMask = "********"
+
+// secretsInLine returns the potential secrets on one line, without Path or
+// Line set. An assignment whose value is itself an AWS key is reported
+// once, as the key.
+func secretsInLine(line string) []SecretFinding {
+	var found []SecretFinding
+	var keySpans [][]int
+	for _, m := range awsKeyPattern.FindAllStringIndex(line, -1) {
+		keySpans = append(keySpans, m)
+		found = append(found, SecretFinding{Kind: SecretAWSAccessKey, Match: line[m[0]:m[0]+4] + strings.Repeat("*", m[1]-m[0]-4)})
+	}
+	for _, header := range privateKeyPattern.FindAllString(line, -1) {
+		// The header marks the key without revealing it.
+		found = append(found, SecretFinding{Kind: SecretPrivateKey, Match: header})
+	}
+	for _, m := range assignmentPattern.FindAllStringSubmatchIndex(line, -1) {
+		if overlapsAny(m[0], m[1], keySpans) {
+			continue
+		}
+		name := line[m[2]:m[3]]
+		found = append(found, SecretFinding{Kind: SecretAssignment, Match: name + "=" + secretMask})
+	}
+	return found
+}
+
+// overlapsAny reports whether [start, end) overlaps any of spans.
+func overlapsAny(start, end int, spans [][]int) bool {
+	for _, s := range spans {
+		if start < s[1] && s[0] < end {
+			return true
+		}
+	}
+	return false
+}
+
+// secretsInFile returns the potential secrets in the text file at path,
+// with Line set. Unreadable files yield none; scanning stops at the first
+// line longer than maxMarkerLine, as such lines are minified or data.
+func secretsInFile(path string) []SecretFinding {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), maxMarkerLine)
+	var found []SecretFinding
+	for n := 1; sc.Scan(); n++ {
+		for _, s := range secretsInLine(sc.Text()) {
+			s.Line = n
+			found = append(found, s)
+		}
+	}
+	return found
+}
+
+// collectSecrets gathers the potential secrets recorded in files, setting
+// each finding's Path, ordered by path and line.
+func collectSecrets(files map[string]FileStat) []SecretFinding {
+	var found []SecretFinding
+	for path, stat := range files {
+		for _, s := range stat.Secrets {
+			s.Path = path
+			found = append(found, s)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Path != found[j].Path {
+			return found[i].Path < found[j].Path
+		}
+		return found[i].Line < found[j].Line
+	})
+	return found
+}
+
+// CountSecretsByKind counts findings by Kind.
+func CountSecretsByKind(findings []SecretFinding) map[string]int {
+	counts := make(map[string]int)
+	for _, s := range findings {
+		counts[s.Kind]++
+	}
+	return counts
+}
+
+// secretKindsSummary renders findings' counts by kind, most common first,
+// such as "2 AWS access key, 1 private key".
+func secretKindsSummary(findings []SecretFinding) string {
+	sorted := SortCounts(CountSecretsByKind(findings))
+	parts := make([]string, len(sorted))
+	for i, c := range sorted {
+		parts[i] = fmt.Sprintf("%d %s", c.Count, c.Name)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ScanSecrets returns the potential secrets in the text files of repo:
+// AWS access keys, private key headers, and literal values assigned to
+// credential-named keys such as API_KEY. It walks the files an analysis
+// with default options would, and matches are redacted. Unreadable files
+// are skipped.
+func ScanSecrets(repo Repository) []SecretFinding {
+	found, _ := ScanSecretsWithOptions(context.Background(), repo, ScanOptions{})
+	return found
+}
+
+// ScanSecretsWithOptions is ScanSecrets with tunable ScanOptions. The walk
+// stops early with ctx.Err() when ctx is cancelled.
+func ScanSecretsWithOptions(ctx context.Context, repo Repository, opts ScanOptions) ([]SecretFinding, error) {
+	opts.ScanSecrets = true
+	w := &analysisWalker{
+		ctx:      ctx,
+		repo:     repo,
+		opts:     opts,
+		log:      logger.NewWithWriter(io.Discard, false),
+		progress: newProgressCounter(ScanOptions{}),
+		analysis: &RepositoryAnalysis{Files: make(map[string]FileStat)},
+	}
+	w.ignore, _ = loadIgnoreFile(filepath.Join(repo.Path, IgnoreFileName))
+	if err := walkTree(repo.Path, opts, w.visit); err != nil {
+		return nil, err
+	}
+	return collectSecrets(w.analysis.Files), nil
+}
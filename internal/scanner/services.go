@@ -0,0 +1,222 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Service kinds reported by DetectServices.
+const (
+	ServiceGoBinary     = "go-binary"
+	ServiceCompose      = "docker-compose"
+	ServiceNPMWorkspace = "npm-workspace"
+)
+
+// Service is a deployable unit or package boundary within a repository.
+type Service struct {
+	Name string `json:"name"`
+	// Path is the service's slash-separated directory relative to the
+	// repository; empty when the marker names no directory (for example a
+	// Compose service that only references an image).
+	Path string `json:"path,omitempty"`
+	// Kind is the marker that revealed the service: ServiceGoBinary,
+	// ServiceCompose, or ServiceNPMWorkspace.
+	Kind string `json:"kind"`
+}
+
+// DetectServices identifies the services in repo: each directory under
+// cmd/, every main package when there are several, each Docker Compose
+// service, and each package.json workspace. Services are sorted by kind,
+// then path, then name; a directory found by more than one Go marker is
+// reported once.
+func DetectServices(repo Repository) []Service {
+	var services []Service
+	services = append(services, goBinaries(repo)...)
+	services = append(services, composeServices(repo.Path)...)
+	services = append(services, npmWorkspaces(repo.Path)...)
+
+	sort.Slice(services, func(i, j int) bool {
+		a, b := services[i], services[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Name < b.Name
+	})
+	return services
+}
+
+// goBinaries reports each directory under cmd/ and, when the repository
+// has more than one, each directory holding a main package's main.go.
+func goBinaries(repo Repository) []Service {
+	dirs := make(map[string]bool)
+
+	entries, _ := os.ReadDir(filepath.Join(repo.Path, "cmd"))
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			dirs["cmd/"+entry.Name()] = true
+		}
+	}
+
+	var mains []string
+	_ = filepath.WalkDir(repo.Path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if p != repo.Path && (ScanOptions{}).SkipsDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == "main.go" && isMainPackage(p) {
+			mains = append(mains, path.Dir(relativeTo(repo.Path, p)))
+		}
+		return nil
+	})
+	if len(mains) > 1 {
+		for _, dir := range mains {
+			dirs[dir] = true
+		}
+	}
+
+	var services []Service
+	for dir := range dirs {
+		name := path.Base(dir)
+		if dir == "." {
+			name, dir = repo.Name, ""
+		}
+		services = append(services, Service{Name: name, Path: dir, Kind: ServiceGoBinary})
+	}
+	return services
+}
+
+// isMainPackage reports whether the Go file at path declares package main.
+func isMainPackage(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) >= 2 && fields[0] == "package" {
+			return fields[1] == "main"
+		}
+	}
+	return false
+}
+
+// composeServices lists the services declared in the first Docker Compose
+// file found at root. A service's path is its build context, if any.
+func composeServices(root string) []Service {
+	for _, name := range composeFiles {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			continue
+		}
+		var compose struct {
+			Services map[string]struct {
+				Build yaml.Node `yaml:"build"`
+			} `yaml:"services"`
+		}
+		if err := yaml.Unmarshal(data, &compose); err != nil {
+			return nil
+		}
+		var services []Service
+		for svc, def := range compose.Services {
+			services = append(services, Service{Name: svc, Path: buildContext(def.Build), Kind: ServiceCompose})
+		}
+		return services
+	}
+	return nil
+}
+
+// buildContext extracts the directory from a Compose "build" entry, which
+// is either a path or a mapping with a "context" key.
+func buildContext(build yaml.Node) string {
+	var dir string
+	switch build.Kind {
+	case yaml.ScalarNode:
+		dir = build.Value
+	case yaml.MappingNode:
+		var b struct {
+			Context string `yaml:"context"`
+		}
+		if build.Decode(&b) == nil {
+			dir = b.Context
+		}
+	}
+	if dir == "" {
+		return ""
+	}
+	dir = path.Clean(filepath.ToSlash(dir))
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// npmWorkspaces expands the workspace globs in root's package.json, in
+// either the array or the {"packages": [...]} form, naming each workspace
+// after its own package.json when it has one.
+func npmWorkspaces(root string) []Service {
+	data, err := os.ReadFile(filepath.Join(root, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil || len(pkg.Workspaces) == 0 {
+		return nil
+	}
+	var patterns []string
+	if err := json.Unmarshal(pkg.Workspaces, &patterns); err != nil {
+		var obj struct {
+			Packages []string `json:"packages"`
+		}
+		if json.Unmarshal(pkg.Workspaces, &obj) != nil {
+			return nil
+		}
+		patterns = obj.Packages
+	}
+
+	var services []Service
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		for _, dir := range matches {
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				continue
+			}
+			services = append(services, Service{Name: packageName(dir), Path: relativeTo(root, dir), Kind: ServiceNPMWorkspace})
+		}
+	}
+	return services
+}
+
+// packageName returns the "name" in dir's package.json, or the directory
+// name when there is none.
+func packageName(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err == nil {
+		var pkg struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(data, &pkg) == nil && pkg.Name != "" {
+			return pkg.Name
+		}
+	}
+	return filepath.Base(dir)
+}
@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectServices(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []Service
+	}{
+		{
+			name: "cmd-based multi-binary Go repo",
+			files: map[string]string{
+				"go.mod":               "module example.com/app\n",
+				"cmd/api/main.go":      "package main\n",
+				"cmd/worker/main.go":   "// Worker drains the queue.\npackage main\n",
+				"cmd/.hidden/x.go":     "package main\n",
+				"internal/lib/main.go": "package lib\n",
+			},
+			want: []Service{
+				{Name: "api", Path: "cmd/api", Kind: ServiceGoBinary},
+				{Name: "worker", Path: "cmd/worker", Kind: ServiceGoBinary},
+			},
+		},
+		{
+			name: "several main packages outside cmd",
+			files: map[string]string{
+				"main.go":            "package main\n",
+				"tools/gen/main.go":  "package main\n",
+				"vendor/x/y/main.go": "package main\n",
+			},
+			want: []Service{
+				{Name: "svc", Path: "", Kind: ServiceGoBinary},
+				{Name: "gen", Path: "tools/gen", Kind: ServiceGoBinary},
+			},
+		},
+		{
+			name:  "single main package is not a service boundary",
+			files: map[string]string{"main.go": "package main\n"},
+			want:  nil,
+		},
+		{
+			name: "docker-compose project",
+			files: map[string]string{
+				"docker-compose.yml": `services:
+  web:
+    build: ./web
+  api:
+    build:
+      context: services/api
+  db:
+    image: postgres:16
+`,
+			},
+			want: []Service{
+				{Name: "db", Path: "", Kind: ServiceCompose},
+				{Name: "api", Path: "services/api", Kind: ServiceCompose},
+				{Name: "web", Path: "web", Kind: ServiceCompose},
+			},
+		},
+		{
+			name: "npm workspaces",
+			files: map[string]string{
+				"package.json":               `{"workspaces": {"packages": ["packages/*"]}}`,
+				"packages/ui/package.json":   `{"name": "@acme/ui"}`,
+				"packages/core/package.json": `{}`,
+				"packages/README.md":         "not a workspace",
+			},
+			want: []Service{
+				{Name: "core", Path: "packages/core", Kind: ServiceNPMWorkspace},
+				{Name: "@acme/ui", Path: "packages/ui", Kind: ServiceNPMWorkspace},
+			},
+		},
+		{
+			name:  "no markers",
+			files: map[string]string{"README.md": "hi\n"},
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTree(t, dir, tt.files)
+			got := DetectServices(Repository{Path: dir, Name: "svc"})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectServices() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package scanner
+
+import (
+	"path"
+	"strings"
+)
+
+// shebangMaxSize is the largest extensionless file whose first line is
+// read to look for a shebang. Larger files are unlikely to be scripts.
+const shebangMaxSize = 1 << 20
+
+// shebangToLang maps script interpreters, as named on a "#!" line, to
+// languages. It parallels extToLang for files without an extension.
+var shebangToLang = map[string]string{
+	"python": "Python",
+	"bash":   "Shell",
+	"sh":     "Shell",
+	"zsh":    "Shell",
+	"ksh":    "Shell",
+	"node":   "JavaScript",
+	"nodejs": "JavaScript",
+	"ruby":   "Ruby",
+	"perl":   "Perl",
+}
+
+// shebangLanguage returns the language of the interpreter named by a
+// "#!" first line, such as "#!/bin/bash" or "#!/usr/bin/env python3", or ""
+// when the line is not a recognized shebang.
+func shebangLanguage(firstLine string) string {
+	if !strings.HasPrefix(firstLine, "#!") {
+		return ""
+	}
+	fields := strings.Fields(firstLine[2:])
+	if len(fields) == 0 {
+		return ""
+	}
+
+	interpreter := path.Base(fields[0])
+	if interpreter == "env" {
+		interpreter = ""
+		// Skip env's options and VAR=value assignments.
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") && !strings.Contains(f, "=") {
+				interpreter = path.Base(f)
+				break
+			}
+		}
+	}
+
+	if lang, ok := shebangToLang[interpreter]; ok {
+		return lang
+	}
+	// Versioned interpreters such as python3 or python3.12.
+	return shebangToLang[strings.TrimRight(interpreter, "0123456789.")]
+}
@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestShebangLanguage(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"#!/bin/bash", "Shell"},
+		{"#!/bin/sh -e", "Shell"},
+		{"#!/usr/bin/env python3", "Python"},
+		{"#!/usr/bin/env python3.12", "Python"},
+		{"#!/usr/bin/python", "Python"},
+		{"#! /usr/bin/env node", "JavaScript"},
+		{"#!/usr/bin/env -S ruby --disable-gems", "Ruby"},
+		{"#!/usr/bin/env LC_ALL=C perl -w", "Perl"},
+		{"#!/usr/bin/awk -f", ""},
+		{"#!", ""},
+		{"# not a shebang", ""},
+		{"package main", ""},
+	}
+
+	for _, tt := range tests {
+		if got := shebangLanguage(tt.line); got != tt.want {
+			t.Errorf("shebangLanguage(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeRepository_ShebangScripts(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"bin/deploy":  "#!/bin/bash\nset -e\necho deploying\n",
+		"bin/migrate": "#!/usr/bin/env python3\nprint('migrating')\n",
+		"LICENSE":     "MIT License\n",
+		"Makefile":    "all:\n\tgo build\n",
+	})
+
+	analysis, err := AnalyzeRepositoryWithOptions(context.Background(), Repository{Path: dir, Name: "scripts"}, ScanOptions{}, logger.New(false))
+	if err != nil {
+		t.Fatalf("AnalyzeRepositoryWithOptions() error = %v", err)
+	}
+	if got := analysis.Languages["Shell"]; got != 1 {
+		t.Errorf("Languages[Shell] = %d, want 1", got)
+	}
+	if got := analysis.Languages["Python"]; got != 1 {
+		t.Errorf("Languages[Python] = %d, want 1", got)
+	}
+	if got := analysis.LinesByLanguage["Shell"]; got != 3 {
+		t.Errorf("LinesByLanguage[Shell] = %d, want 3", got)
+	}
+	if len(analysis.Languages) != 2 {
+		t.Errorf("Languages = %v, want only the two scripts counted", analysis.Languages)
+	}
+}
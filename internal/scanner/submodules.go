@@ -0,0 +1,103 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Submodule describes a single entry declared in a repository's .gitmodules file.
+type Submodule struct {
+	Name string
+	Path string
+	URL  string
+}
+
+// ParseSubmodules reads the .gitmodules file at the root of repoPath and
+// returns the declared submodules in file order. A missing .gitmodules file
+// is not an error; it yields an empty slice.
+func ParseSubmodules(repoPath string) ([]Submodule, error) {
+	data, err := os.ReadFile(filepath.Join(repoPath, ".gitmodules"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read .gitmodules: %w", err)
+	}
+
+	var submodules []Submodule
+	var current *Submodule
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := stripConfigComment(strings.TrimSpace(sc.Text()))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			current = nil
+			if name, ok := parseSubmoduleHeader(line); ok {
+				submodules = append(submodules, Submodule{Name: name})
+				current = &submodules[len(submodules)-1]
+			}
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "path":
+			current.Path = unquoteConfigValue(value)
+		case "url":
+			current.URL = unquoteConfigValue(value)
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse .gitmodules: %w", err)
+	}
+
+	return submodules, nil
+}
+
+// parseSubmoduleHeader extracts the name from a `[submodule "name"]` section
+// header. It reports false for sections other than submodule.
+func parseSubmoduleHeader(line string) (string, bool) {
+	kind, name := parseConfigSection(line)
+	if kind != "submodule" {
+		return "", false
+	}
+	return name, true
+}
+
+// stripConfigComment removes full-line and trailing git-config comments
+// (introduced by '#' or ';') that are not inside a quoted value.
+func stripConfigComment(line string) string {
+	inQuotes := false
+	for i, r := range line {
+		switch r {
+		case '"':
+			inQuotes = !inQuotes
+		case '#', ';':
+			if !inQuotes {
+				return strings.TrimSpace(line[:i])
+			}
+		}
+	}
+	return line
+}
+
+// unquoteConfigValue trims whitespace and surrounding quotes from a git-config value.
+func unquoteConfigValue(value string) string {
+	return strings.Trim(strings.TrimSpace(value), `"`)
+}
@@ -0,0 +1,113 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestParseSubmodules(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Submodule
+	}{
+		{
+			name: "single submodule",
+			content: `[submodule "libs/foo"]
+	path = libs/foo
+	url = https://example.com/foo.git
+`,
+			want: []Submodule{
+				{Name: "libs/foo", Path: "libs/foo", URL: "https://example.com/foo.git"},
+			},
+		},
+		{
+			name: "multiple submodules with comments",
+			content: `# Top-level comment
+[submodule "api"]
+	path = services/api
+	; the canonical remote
+	url = git@example.com:org/api.git
+[submodule "web"]
+	path = services/web # trailing comment
+	url = "https://example.com/web.git"
+`,
+			want: []Submodule{
+				{Name: "api", Path: "services/api", URL: "git@example.com:org/api.git"},
+				{Name: "web", Path: "services/web", URL: "https://example.com/web.git"},
+			},
+		},
+		{
+			name: "ignores non-submodule sections",
+			content: `[core]
+	path = ignored
+[submodule "docs"]
+	path = docs
+`,
+			want: []Submodule{
+				{Name: "docs", Path: "docs"},
+			},
+		},
+		{
+			name:    "empty file",
+			content: "",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, ".gitmodules"), []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ParseSubmodules(dir)
+			if err != nil {
+				t.Fatalf("ParseSubmodules() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSubmodules() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSubmodules_MissingFile(t *testing.T) {
+	got, err := ParseSubmodules(t.TempDir())
+	if err != nil {
+		t.Fatalf("ParseSubmodules() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("ParseSubmodules() = %+v, want empty", got)
+	}
+}
+
+func TestFindGitRepos_PopulatesSubmodules(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "[submodule \"a\"]\n\tpath = a\n[submodule \"b\"]\n\tpath = b\n"
+	if err := os.WriteFile(filepath.Join(dir, ".gitmodules"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repos, err := FindGitRepos(dir, logger.New(false))
+	if err != nil {
+		t.Fatalf("FindGitRepos() error = %v", err)
+	}
+	if len(repos) != 1 {
+		t.Fatalf("FindGitRepos() got %d repos, want 1", len(repos))
+	}
+	if !repos[0].HasSubmodules {
+		t.Error("HasSubmodules = false, want true")
+	}
+	if len(repos[0].Submodules) != 2 {
+		t.Errorf("len(Submodules) = %d, want 2", len(repos[0].Submodules))
+	}
+}
@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// walkTree walks root like filepath.Walk. With opts.FollowSymlinks set,
+// symlinks to directories are descended into as if they were ordinary
+// directories, and paths beneath them are reported under the link's path.
+// Each directory is visited at most once, keyed by its inode, so symlink
+// cycles terminate.
+func walkTree(root string, opts ScanOptions, fn filepath.WalkFunc) error {
+	if !opts.FollowSymlinks {
+		return filepath.Walk(root, fn)
+	}
+	w := &symlinkWalker{fn: fn, visited: make(map[fileKey]bool)}
+	return w.walk(root, root)
+}
+
+// symlinkWalker carries the visited-directory set across the nested walks
+// started for each followed symlink.
+type symlinkWalker struct {
+	fn      filepath.WalkFunc
+	visited map[fileKey]bool
+}
+
+// walk walks realRoot, reporting each path to fn relative to logicalRoot.
+func (w *symlinkWalker) walk(logicalRoot, realRoot string) error {
+	return filepath.Walk(realRoot, func(path string, info os.FileInfo, err error) error {
+		logical := logicalRoot
+		if rel, relErr := filepath.Rel(realRoot, path); relErr == nil && rel != "." {
+			logical = filepath.Join(logicalRoot, rel)
+		}
+		if err != nil {
+			return w.fn(logical, info, err)
+		}
+
+		if info.IsDir() {
+			if key, ok := fileKeyOf(path, info); ok {
+				if w.visited[key] {
+					return filepath.SkipDir
+				}
+				w.visited[key] = true
+			}
+			return w.fn(logical, info, nil)
+		}
+
+		if info.Mode()&os.ModeSymlink == 0 {
+			return w.fn(logical, info, nil)
+		}
+
+		// Broken links are reported as the link itself.
+		target, statErr := os.Stat(path)
+		if statErr != nil {
+			return w.fn(logical, info, nil)
+		}
+		if !target.IsDir() {
+			return w.fn(logical, target, nil)
+		}
+
+		resolved, evalErr := filepath.EvalSymlinks(path)
+		if evalErr != nil {
+			return w.fn(logical, info, evalErr)
+		}
+		if err := w.walk(logical, resolved); err != nil && !errors.Is(err, filepath.SkipDir) {
+			return err
+		}
+		return nil
+	})
+}
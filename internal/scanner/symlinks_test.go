@@ -0,0 +1,97 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestAnalyzeRepositoryWithOptions_FollowSymlinks(t *testing.T) {
+	root := t.TempDir()
+	repoDir := filepath.Join(root, "repo")
+	sharedDir := filepath.Join(root, "shared")
+	writeTree(t, repoDir, map[string]string{"main.go": "package main\n"})
+	writeTree(t, sharedDir, map[string]string{"lib.py": "x = 1\n", "util.py": "y = 2\n"})
+	if err := os.Symlink(sharedDir, filepath.Join(repoDir, "lib")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		follow        bool
+		wantFiles     int
+		wantPythonCnt int
+	}{
+		{"default leaves symlinked dir unvisited", false, 2, 0},
+		{"follow counts symlinked dir", true, 3, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := ScanOptions{FollowSymlinks: tt.follow}
+			analysis, err := AnalyzeRepositoryWithOptions(context.Background(), Repository{Path: repoDir, Name: "repo"}, opts, logger.New(false))
+			if err != nil {
+				t.Fatalf("AnalyzeRepositoryWithOptions() error = %v", err)
+			}
+			if analysis.TotalFiles != tt.wantFiles {
+				t.Errorf("TotalFiles = %d, want %d", analysis.TotalFiles, tt.wantFiles)
+			}
+			if got := analysis.Languages["Python"]; got != tt.wantPythonCnt {
+				t.Errorf("Languages[Python] = %d, want %d", got, tt.wantPythonCnt)
+			}
+		})
+	}
+}
+
+func TestWalkTree_SymlinkLoopTerminates(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"sub/a.go": "package a\n"})
+	if err := os.Symlink(root, filepath.Join(root, "sub", "loop")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	var files []string
+	err := walkTree(root, ScanOptions{FollowSymlinks: true}, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			rel, _ := filepath.Rel(root, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walkTree() error = %v", err)
+	}
+	if len(files) != 1 || files[0] != "sub/a.go" {
+		t.Errorf("walkTree() files = %v, want [sub/a.go]", files)
+	}
+}
+
+func TestWalkTree_ReportsPathsUnderLink(t *testing.T) {
+	root := t.TempDir()
+	target := t.TempDir()
+	writeTree(t, target, map[string]string{"pkg/b.go": "package b\n"})
+	if err := os.Symlink(target, filepath.Join(root, "linked")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	var got []string
+	err := walkTree(root, ScanOptions{FollowSymlinks: true}, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			got = append(got, path)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("walkTree() error = %v", err)
+	}
+	want := filepath.Join(root, "linked", "pkg", "b.go")
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("walkTree() files = %v, want [%s]", got, want)
+	}
+}
@@ -0,0 +1,55 @@
+package scanner
+
+import "strings"
+
+// nonCodeLanguages are languages from extToLang that hold data, markup, or
+// styles rather than code, and so are excluded from CodeFiles.
+var nonCodeLanguages = map[string]bool{
+	"YAML":     true,
+	"JSON":     true,
+	"XML":      true,
+	"HTML":     true,
+	"CSS":      true,
+	"SCSS":     true,
+	"LESS":     true,
+	"Markdown": true,
+}
+
+// testSuffixes are file name endings that mark a test file by convention.
+var testSuffixes = []string{
+	"_test.go",
+	"_test.py",
+	"Test.java", "Tests.java",
+	".spec.ts", ".spec.tsx", ".spec.js", ".spec.jsx",
+	".test.ts", ".test.tsx", ".test.js", ".test.jsx",
+	"_spec.rb",
+}
+
+// isTestFile reports whether the file name follows a language's test file
+// convention (for example foo_test.go, test_foo.py, or foo.spec.ts).
+func isTestFile(name string) bool {
+	if strings.HasPrefix(name, "test_") && strings.HasSuffix(name, ".py") {
+		return true
+	}
+	for _, suffix := range testSuffixes {
+		if strings.HasSuffix(name, suffix) && len(name) > len(suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCodeLanguage reports whether lang is a programming language rather than
+// a data or markup format or the OtherLanguage bucket.
+func isCodeLanguage(lang string) bool {
+	return lang != "" && lang != OtherLanguage && !nonCodeLanguages[lang]
+}
+
+// TestToCodeRatio returns the number of test files per non-test code file,
+// or 0 when there is no code.
+func (a *RepositoryAnalysis) TestToCodeRatio() float64 {
+	if a.CodeFiles == 0 {
+		return 0
+	}
+	return float64(a.TestFiles) / float64(a.CodeFiles)
+}
@@ -0,0 +1,93 @@
+package scanner
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestIsTestFile(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"scanner_test.go", true},
+		{"scanner.go", false},
+		{"test_scanner.py", true},
+		{"scanner_test.py", true},
+		{"testing.py", false},
+		{"app.spec.ts", true},
+		{"app.test.tsx", true},
+		{"app.ts", false},
+		{"ScannerTest.java", true},
+		{"Scanner.java", false},
+		{"_test.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTestFile(tt.name); got != tt.want {
+				t.Errorf("isTestFile(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepository_TestFiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     []string
+		wantTests int
+		wantCode  int
+		wantRatio float64
+	}{
+		{
+			name:      "go",
+			files:     []string{"main.go", "util.go", "util_test.go"},
+			wantTests: 1, wantCode: 2, wantRatio: 0.5,
+		},
+		{
+			name:      "python",
+			files:     []string{"app.py", "test_app.py", "README.md"},
+			wantTests: 1, wantCode: 1, wantRatio: 1,
+		},
+		{
+			name:      "typescript",
+			files:     []string{"a.ts", "b.ts", "c.tsx", "a.spec.ts", "package.json"},
+			wantTests: 1, wantCode: 3, wantRatio: 1.0 / 3,
+		},
+		{
+			name:      "no tests",
+			files:     []string{"main.go", "config.yaml"},
+			wantTests: 0, wantCode: 1, wantRatio: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, f), []byte("x\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			analysis, err := AnalyzeRepository(Repository{Path: dir, Name: tt.name}, logger.New(false))
+			if err != nil {
+				t.Fatalf("AnalyzeRepository() error = %v", err)
+			}
+			if analysis.TestFiles != tt.wantTests {
+				t.Errorf("TestFiles = %d, want %d", analysis.TestFiles, tt.wantTests)
+			}
+			if analysis.CodeFiles != tt.wantCode {
+				t.Errorf("CodeFiles = %d, want %d", analysis.CodeFiles, tt.wantCode)
+			}
+			if got := analysis.TestToCodeRatio(); math.Abs(got-tt.wantRatio) > 1e-9 {
+				t.Errorf("TestToCodeRatio() = %v, want %v", got, tt.wantRatio)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Tooling lists the build, CI, and container systems a repository uses.
+// Each entry names the system and the file that revealed it.
+type Tooling struct {
+	Build     []string
+	CI        []string
+	Container []string
+	// BuildCommands lists the likely commands to build the repository (see
+	// BuildCommands), most specific first; several mean it is ambiguous.
+	BuildCommands []string
+}
+
+// buildMarkers maps root-level build files to the system they indicate.
+// package.json is handled separately so its scripts can be reported.
+var buildMarkers = []struct{ file, system string }{
+	{"Makefile", "Make"},
+	{"go.mod", "Go modules"},
+	{"pom.xml", "Maven"},
+	{"build.gradle", "Gradle"},
+	{"build.gradle.kts", "Gradle"},
+	{"Cargo.toml", "Cargo"},
+	{"pyproject.toml", "Python (pyproject)"},
+	{"setup.py", "Python (setuptools)"},
+	{"CMakeLists.txt", "CMake"},
+}
+
+// ciMarkers maps single CI configuration files to their CI system.
+var ciMarkers = []struct{ file, system string }{
+	{".gitlab-ci.yml", "GitLab CI"},
+	{"Jenkinsfile", "Jenkins"},
+	{filepath.Join(".circleci", "config.yml"), "CircleCI"},
+	{"azure-pipelines.yml", "Azure Pipelines"},
+	{".travis.yml", "Travis CI"},
+}
+
+// composeFiles are the Docker Compose file names checked at the root.
+var composeFiles = []string{"docker-compose.yml", "docker-compose.yaml", "compose.yml", "compose.yaml"}
+
+// DetectTooling inspects the root of repo for well-known build, CI, and
+// container configuration files.
+func DetectTooling(repo Repository) Tooling {
+	var t Tooling
+
+	for _, m := range buildMarkers {
+		if fileExists(filepath.Join(repo.Path, m.file)) {
+			t.Build = append(t.Build, fmt.Sprintf("%s (%s)", m.system, m.file))
+		}
+	}
+	if entry, ok := npmTooling(filepath.Join(repo.Path, "package.json")); ok {
+		t.Build = append(t.Build, entry)
+	}
+
+	workflows, _ := filepath.Glob(filepath.Join(repo.Path, ".github", "workflows", "*.y*ml"))
+	sort.Strings(workflows)
+	for _, wf := range workflows {
+		t.CI = append(t.CI, fmt.Sprintf("GitHub Actions (%s)", filepath.Base(wf)))
+	}
+	for _, m := range ciMarkers {
+		if fileExists(filepath.Join(repo.Path, m.file)) {
+			t.CI = append(t.CI, fmt.Sprintf("%s (%s)", m.system, filepath.ToSlash(m.file)))
+		}
+	}
+
+	dockerfiles, _ := filepath.Glob(filepath.Join(repo.Path, "Dockerfile*"))
+	sort.Strings(dockerfiles)
+	for _, df := range dockerfiles {
+		t.Container = append(t.Container, fmt.Sprintf("Docker (%s)", filepath.Base(df)))
+	}
+	for _, name := range composeFiles {
+		if fileExists(filepath.Join(repo.Path, name)) {
+			t.Container = append(t.Container, fmt.Sprintf("Docker Compose (%s)", name))
+		}
+	}
+
+	t.BuildCommands = BuildCommands(repo.Path)
+	return t
+}
+
+// npmTooling describes package.json, listing its scripts when present.
+func npmTooling(path string) (string, bool) {
+	scripts, ok := npmScripts(path)
+	if !ok {
+		return "", false
+	}
+	if len(scripts) == 0 {
+		return "npm (package.json)", true
+	}
+
+	names := make([]string, 0, len(scripts))
+	for name := range scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("npm (package.json scripts: %s)", strings.Join(names, ", ")), true
+}
+
+// npmScripts returns the scripts declared in the package.json at path. It
+// reports false when the file cannot be read; a file that does not parse
+// yields no scripts.
+func npmScripts(path string) (map[string]string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, true
+	}
+	return pkg.Scripts, true
+}
+
+// fileExists reports whether path exists and is a regular file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
@@ -0,0 +1,92 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// writeTree creates files (relative path -> content) under dir.
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDetectTooling(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  Tooling
+	}{
+		{
+			name: "dockerfile and github workflow",
+			files: map[string]string{
+				"Dockerfile":                 "FROM golang:1.21\n",
+				".github/workflows/ci.yml":   "on: push\n",
+				".github/workflows/rel.yaml": "on: release\n",
+				"go.mod":                     "module example.com/app\n",
+				"Makefile":                   "all:\n",
+			},
+			want: Tooling{
+				Build:         []string{"Make (Makefile)", "Go modules (go.mod)"},
+				CI:            []string{"GitHub Actions (ci.yml)", "GitHub Actions (rel.yaml)"},
+				Container:     []string{"Docker (Dockerfile)"},
+				BuildCommands: []string{"make", "go build ./..."},
+			},
+		},
+		{
+			name: "npm scripts, gitlab, jenkins, compose",
+			files: map[string]string{
+				"package.json":       `{"scripts": {"test": "jest", "build": "tsc"}}`,
+				".gitlab-ci.yml":     "stages: [test]\n",
+				"Jenkinsfile":        "pipeline {}\n",
+				"docker-compose.yml": "services: {}\n",
+			},
+			want: Tooling{
+				Build:         []string{"npm (package.json scripts: build, test)"},
+				CI:            []string{"GitLab CI (.gitlab-ci.yml)", "Jenkins (Jenkinsfile)"},
+				Container:     []string{"Docker Compose (docker-compose.yml)"},
+				BuildCommands: []string{"npm run build"},
+			},
+		},
+		{
+			name:  "nothing detected",
+			files: map[string]string{"main.go": "package main\n"},
+			want:  Tooling{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTree(t, dir, tt.files)
+			if got := DetectTooling(Repository{Path: dir}); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectTooling() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepository_AttachesTooling(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"Dockerfile": "FROM scratch\n"})
+
+	analysis, err := AnalyzeRepository(Repository{Path: dir, Name: "app"}, logger.New(false))
+	if err != nil {
+		t.Fatalf("AnalyzeRepository() error = %v", err)
+	}
+	if want := []string{"Docker (Dockerfile)"}; !reflect.DeepEqual(analysis.Tooling.Container, want) {
+		t.Errorf("Tooling.Container = %v, want %v", analysis.Tooling.Container, want)
+	}
+}
@@ -0,0 +1,139 @@
+package learnings
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Backlog item sources, reported in BacklogItem.Source.
+const (
+	BacklogImprovement = "improvement"
+	BacklogFailure     = "failure"
+	BacklogEdgeCase    = "edge_case"
+)
+
+// failureIDPrefix numbers failures in the backlog; failures carry no ID of
+// their own.
+const failureIDPrefix = "FAIL"
+
+// failurePriorities maps the failure impact vocabulary of the learnings
+// schema onto priorities. Failures with any other impact are "high".
+var failurePriorities = map[string]string{
+	"critical": "critical",
+	"major":    "high",
+	"minor":    "low",
+}
+
+// effortRanks orders effort estimates from least to most work. Unknown
+// estimates sort after all known ones.
+var effortRanks = map[string]int{
+	"small":  0,
+	"medium": 1,
+	"large":  2,
+}
+
+// BacklogItem is a single prioritized work item exported from learnings
+// for triage.
+type BacklogItem struct {
+	ID          string `json:"id"`
+	Source      string `json:"source"`
+	Category    string `json:"category,omitempty"`
+	Description string `json:"description"`
+	Action      string `json:"action,omitempty"`
+	Priority    string `json:"priority"`
+	Effort      string `json:"effort,omitempty"`
+}
+
+// JSON returns the item encoded as a JSON object.
+func (b BacklogItem) JSON() ([]byte, error) {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode backlog item %s: %w", b.ID, err)
+	}
+	return data, nil
+}
+
+// ExportBacklog flattens the improvements, failures, and critical- and
+// high-priority edge cases in l into a single list ordered by priority and
+// then by effort. Items keep their recorded IDs; the rest are numbered by
+// position (IMP001, FAIL001, EC001), and any duplicate is suffixed so that
+// every ID in the list is unique and the same learnings always yield the
+// same IDs.
+func (l *Learnings) ExportBacklog() []BacklogItem {
+	var items []BacklogItem
+	for i, imp := range l.Improvements {
+		items = append(items, BacklogItem{
+			ID:          firstNonEmpty(imp.ImprovementID, fmt.Sprintf("%s%03d", improvementIDPrefix, i+1)),
+			Source:      BacklogImprovement,
+			Category:    imp.Category,
+			Description: imp.Description,
+			Action:      imp.ImplementationHint,
+			Priority:    imp.Priority,
+			Effort:      imp.EffortEstimate,
+		})
+	}
+	for i, f := range l.WhatFailed {
+		items = append(items, BacklogItem{
+			ID:          fmt.Sprintf("%s%03d", failureIDPrefix, i+1),
+			Source:      BacklogFailure,
+			Category:    f.Category,
+			Description: f.Description,
+			Action:      f.SuggestedFix,
+			Priority:    failurePriority(f.Impact),
+		})
+	}
+	for i, e := range l.EdgeCases {
+		if priorityRank(e.Priority) > priorityRank("high") {
+			continue
+		}
+		items = append(items, BacklogItem{
+			ID:          firstNonEmpty(e.CaseID, fmt.Sprintf("%s%03d", edgeCaseIDPrefix, i+1)),
+			Source:      BacklogEdgeCase,
+			Description: e.Description,
+			Action:      e.DesiredBehavior,
+			Priority:    e.Priority,
+		})
+	}
+
+	uniqueBacklogIDs(items)
+	sort.SliceStable(items, func(i, j int) bool {
+		if pi, pj := priorityRank(items[i].Priority), priorityRank(items[j].Priority); pi != pj {
+			return pi < pj
+		}
+		return effortRank(items[i].Effort) < effortRank(items[j].Effort)
+	})
+	return items
+}
+
+// failurePriority returns the backlog priority for a failure impact.
+func failurePriority(impact string) string {
+	if priority, ok := failurePriorities[strings.ToLower(strings.TrimSpace(impact))]; ok {
+		return priority
+	}
+	return "high"
+}
+
+// effortRank returns the sort rank of an effort estimate; lower is less
+// work.
+func effortRank(effort string) int {
+	if rank, ok := effortRanks[strings.ToLower(strings.TrimSpace(effort))]; ok {
+		return rank
+	}
+	return len(effortRanks)
+}
+
+// uniqueBacklogIDs suffixes repeated IDs in items with -2, -3, ... in
+// order of appearance.
+func uniqueBacklogIDs(items []BacklogItem) {
+	used := make(map[string]bool, len(items))
+	for i := range items {
+		id := items[i].ID
+		for n := 2; used[id]; n++ {
+			id = fmt.Sprintf("%s-%d", items[i].ID, n)
+		}
+		used[id] = true
+		items[i].ID = id
+	}
+}
@@ -0,0 +1,84 @@
+package learnings
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExportBacklogOrder(t *testing.T) {
+	l := NewLearnings()
+	l.Improvements = []Improvement{
+		{ImprovementID: "IMP001", Description: "tidy logs", Priority: "low", EffortEstimate: "small"},
+		{ImprovementID: "IMP002", Description: "rewrite parser", Priority: "critical", EffortEstimate: "large"},
+		{ImprovementID: "IMP003", Description: "guard nil map", Priority: "critical", EffortEstimate: "small"},
+	}
+	l.WhatFailed = []Failed{{Description: "crash on empty repo", Impact: "major", SuggestedFix: "check length"}}
+	l.EdgeCases = []EdgeCase{
+		{CaseID: "EC001", Description: "symlink loop", Priority: "high", DesiredBehavior: "stop at cycle"},
+		{CaseID: "EC002", Description: "emoji file names", Priority: "low"},
+	}
+
+	items := l.ExportBacklog()
+
+	wantIDs := []string{"IMP003", "IMP002", "FAIL001", "EC001", "IMP001"}
+	if len(items) != len(wantIDs) {
+		t.Fatalf("ExportBacklog() returned %d items, want %d: %+v", len(items), len(wantIDs), items)
+	}
+	for i, want := range wantIDs {
+		if items[i].ID != want {
+			t.Errorf("items[%d].ID = %q, want %q", i, items[i].ID, want)
+		}
+	}
+	if got := items[2]; got.Source != BacklogFailure || got.Priority != "high" || got.Action != "check length" {
+		t.Errorf("failure item = %+v, want high priority with the suggested fix", got)
+	}
+	if got := items[3]; got.Source != BacklogEdgeCase || got.Action != "stop at cycle" {
+		t.Errorf("edge case item = %+v, want desired behavior as action", got)
+	}
+}
+
+func TestExportBacklogUniqueIDs(t *testing.T) {
+	l := NewLearnings()
+	l.Improvements = []Improvement{
+		{ImprovementID: "IMP002", Description: "a", Priority: "high"},
+		{Description: "b", Priority: "high"},
+		{ImprovementID: "IMP002", Description: "c", Priority: "high"},
+	}
+	l.WhatFailed = []Failed{{Description: "d"}, {Description: "e"}}
+	l.EdgeCases = []EdgeCase{{Description: "f", Priority: "critical"}}
+
+	items := l.ExportBacklog()
+
+	seen := make(map[string]bool)
+	for _, item := range items {
+		if item.ID == "" || seen[item.ID] {
+			t.Errorf("ID %q is empty or repeated in %+v", item.ID, items)
+		}
+		seen[item.ID] = true
+	}
+	again := l.ExportBacklog()
+	for i := range items {
+		if items[i].ID != again[i].ID {
+			t.Errorf("IDs should be stable across exports: %q then %q", items[i].ID, again[i].ID)
+		}
+	}
+}
+
+func TestBacklogItemJSON(t *testing.T) {
+	item := BacklogItem{ID: "IMP001", Source: BacklogImprovement, Description: "add caching", Priority: "medium"}
+
+	data, err := item.JSON()
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("JSON() produced invalid JSON %s: %v", data, err)
+	}
+	if got["id"] != "IMP001" || got["source"] != "improvement" || got["priority"] != "medium" {
+		t.Errorf("JSON() = %s, want id, source, and priority fields", data)
+	}
+	if _, ok := got["effort"]; ok {
+		t.Errorf("JSON() = %s, want empty effort omitted", data)
+	}
+}
@@ -0,0 +1,79 @@
+package learnings
+
+import "fmt"
+
+// ID prefixes assigned by AddEdgeCase and AddImprovement, following the
+// EC001 / IMP001 convention of docs/EVOLUTION-SYSTEM.md.
+const (
+	edgeCaseIDPrefix    = "EC"
+	improvementIDPrefix = "IMP"
+)
+
+// WithMetadata sets l's metadata and returns l for chaining.
+func (l *Learnings) WithMetadata(m Metadata) *Learnings {
+	l.Metadata = m
+	return l
+}
+
+// AddWorkedWell appends w and returns l for chaining.
+func (l *Learnings) AddWorkedWell(w WorkedWell) *Learnings {
+	l.WhatWorkedWell = append(l.WhatWorkedWell, w)
+	return l
+}
+
+// AddFailure appends f and returns l for chaining.
+func (l *Learnings) AddFailure(f Failed) *Learnings {
+	l.WhatFailed = append(l.WhatFailed, f)
+	return l
+}
+
+// AddPattern appends p and returns l for chaining.
+func (l *Learnings) AddPattern(p Pattern) *Learnings {
+	l.Patterns = append(l.Patterns, p)
+	return l
+}
+
+// AddCustomNote appends n and returns l for chaining.
+func (l *Learnings) AddCustomNote(n CustomNote) *Learnings {
+	l.CustomNotes = append(l.CustomNotes, n)
+	return l
+}
+
+// AddEdgeCase appends e and returns l for chaining. An empty CaseID is
+// assigned the next unused sequential ID (EC001, EC002, ...).
+func (l *Learnings) AddEdgeCase(e EdgeCase) *Learnings {
+	if e.CaseID == "" {
+		used := make(map[string]bool, len(l.EdgeCases))
+		for _, existing := range l.EdgeCases {
+			used[existing.CaseID] = true
+		}
+		e.CaseID = nextID(edgeCaseIDPrefix, len(l.EdgeCases), used)
+	}
+	l.EdgeCases = append(l.EdgeCases, e)
+	return l
+}
+
+// AddImprovement appends i and returns l for chaining. An empty
+// ImprovementID is assigned the next unused sequential ID (IMP001,
+// IMP002, ...).
+func (l *Learnings) AddImprovement(i Improvement) *Learnings {
+	if i.ImprovementID == "" {
+		used := make(map[string]bool, len(l.Improvements))
+		for _, existing := range l.Improvements {
+			used[existing.ImprovementID] = true
+		}
+		i.ImprovementID = nextID(improvementIDPrefix, len(l.Improvements), used)
+	}
+	l.Improvements = append(l.Improvements, i)
+	return l
+}
+
+// nextID returns the first prefix-numbered ID after count that is not in
+// used.
+func nextID(prefix string, count int, used map[string]bool) string {
+	for n := count + 1; ; n++ {
+		if id := fmt.Sprintf("%s%03d", prefix, n); !used[id] {
+			return id
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package learnings
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBuilder_Chaining(t *testing.T) {
+	runDate := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	l := NewLearnings().
+		WithMetadata(Metadata{ToolName: "scanner", Generation: 2, RunDate: runDate}).
+		AddWorkedWell(WorkedWell{Category: "parsing", Description: "fast"}).
+		AddFailure(Failed{Category: "io", Description: "timeout"}).
+		AddFailure(Failed{Category: "io", Description: "permission denied"}).
+		AddPattern(Pattern{PatternName: "repository"}).
+		AddCustomNote(CustomNote{Note: "check vendored code"})
+
+	if l.Metadata.ToolName != "scanner" || l.Metadata.Generation != 2 || !l.Metadata.RunDate.Equal(runDate) {
+		t.Errorf("Metadata = %+v", l.Metadata)
+	}
+	if len(l.WhatWorkedWell) != 1 || len(l.Patterns) != 1 || len(l.CustomNotes) != 1 {
+		t.Errorf("builders did not append: %+v", l)
+	}
+	want := []Failed{{Category: "io", Description: "timeout"}, {Category: "io", Description: "permission denied"}}
+	if !reflect.DeepEqual(l.WhatFailed, want) {
+		t.Errorf("WhatFailed = %+v, want %+v", l.WhatFailed, want)
+	}
+}
+
+func TestBuilder_AppendsToExistingFields(t *testing.T) {
+	l := &Learnings{WhatFailed: []Failed{{Description: "set directly"}}}
+	l.AddFailure(Failed{Description: "added"})
+	if len(l.WhatFailed) != 2 || l.WhatFailed[0].Description != "set directly" {
+		t.Errorf("WhatFailed = %+v, want the direct entry kept and one appended", l.WhatFailed)
+	}
+}
+
+func TestBuilder_AutoIDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		add      []string
+		want     []string
+	}{
+		{"sequential from empty", nil, []string{"", "", ""}, []string{"001", "002", "003"}},
+		{"explicit IDs kept", nil, []string{"", "custom", ""}, []string{"001", "custom", "003"}},
+		{"skips IDs already used", []string{"002"}, []string{"", ""}, []string{"002", "003", "004"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLearnings()
+			for _, id := range tt.existing {
+				l.EdgeCases = append(l.EdgeCases, EdgeCase{CaseID: "EC" + id})
+				l.Improvements = append(l.Improvements, Improvement{ImprovementID: "IMP" + id})
+			}
+			for _, id := range tt.add {
+				edge, imp := id, id
+				if id != "" && id != "custom" {
+					edge, imp = "EC"+id, "IMP"+id
+				}
+				l.AddEdgeCase(EdgeCase{CaseID: edge}).AddImprovement(Improvement{ImprovementID: imp})
+			}
+
+			for i, id := range tt.want {
+				wantEdge, wantImp := "EC"+id, "IMP"+id
+				if id == "custom" {
+					wantEdge, wantImp = id, id
+				}
+				if got := l.EdgeCases[i].CaseID; got != wantEdge {
+					t.Errorf("EdgeCases[%d].CaseID = %q, want %q", i, got, wantEdge)
+				}
+				if got := l.Improvements[i].ImprovementID; got != wantImp {
+					t.Errorf("Improvements[%d].ImprovementID = %q, want %q", i, got, wantImp)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package learnings
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DetectDependencyChanges compares two dependency snapshots, each mapping a
+// dependency name to its declared version, and reports dependencies that
+// were added, removed, or moved to a higher semver major version. Entries
+// are sorted by name. Versions that cannot be parsed are never reported as
+// major upgrades.
+func DetectDependencyChanges(oldDeps, newDeps map[string]string) DependencyChanges {
+	var changes DependencyChanges
+
+	for _, name := range sortedKeys(newDeps) {
+		oldVersion, existed := oldDeps[name]
+		newVersion := newDeps[name]
+		if !existed {
+			changes.NewDependencies = append(changes.NewDependencies, versionedName(name, newVersion))
+			continue
+		}
+		oldMajor, okOld := majorVersion(oldVersion)
+		newMajor, okNew := majorVersion(newVersion)
+		if okOld && okNew && newMajor > oldMajor {
+			changes.MajorUpgrades = append(changes.MajorUpgrades, fmt.Sprintf("%s %s -> %s", name, oldVersion, newVersion))
+		}
+	}
+
+	for _, name := range sortedKeys(oldDeps) {
+		if _, kept := newDeps[name]; !kept {
+			changes.RemovedDependencies = append(changes.RemovedDependencies, versionedName(name, oldDeps[name]))
+		}
+	}
+
+	return changes
+}
+
+// versionedName formats a dependency as name@version, or just name when
+// the version is unknown.
+func versionedName(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + "@" + version
+}
+
+// majorVersion extracts the major component of a semver-like version,
+// ignoring a leading "v" and range operators such as "^", "~", or ">=".
+func majorVersion(version string) (int, bool) {
+	v := strings.TrimLeft(strings.TrimSpace(version), "v^~=<>! ")
+	end := 0
+	for end < len(v) && v[end] >= '0' && v[end] <= '9' {
+		end++
+	}
+	if end == 0 {
+		return 0, false
+	}
+	major, err := strconv.Atoi(v[:end])
+	if err != nil {
+		return 0, false
+	}
+	return major, true
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
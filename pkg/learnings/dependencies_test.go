@@ -0,0 +1,64 @@
+package learnings
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectDependencyChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldDeps map[string]string
+		newDeps map[string]string
+		want    DependencyChanges
+	}{
+		{
+			name:    "major bump",
+			oldDeps: map[string]string{"github.com/gin-gonic/gin": "v1.9.1"},
+			newDeps: map[string]string{"github.com/gin-gonic/gin": "v2.0.0"},
+			want:    DependencyChanges{MajorUpgrades: []string{"github.com/gin-gonic/gin v1.9.1 -> v2.0.0"}},
+		},
+		{
+			name:    "major bump of npm range",
+			oldDeps: map[string]string{"react": "^17.0.2"},
+			newDeps: map[string]string{"react": "^18.2.0"},
+			want:    DependencyChanges{MajorUpgrades: []string{"react ^17.0.2 -> ^18.2.0"}},
+		},
+		{
+			name:    "patch bump is not major",
+			oldDeps: map[string]string{"django": "==4.2.1"},
+			newDeps: map[string]string{"django": "==4.2.7"},
+			want:    DependencyChanges{},
+		},
+		{
+			name:    "downgrade is not major",
+			oldDeps: map[string]string{"vue": "3.1.0"},
+			newDeps: map[string]string{"vue": "2.7.0"},
+			want:    DependencyChanges{},
+		},
+		{
+			name:    "added and removed",
+			oldDeps: map[string]string{"flask": "", "requests": "==2.31.0"},
+			newDeps: map[string]string{"requests": "==2.31.0", "fastapi": "==0.110.0", "uvicorn": ""},
+			want: DependencyChanges{
+				NewDependencies:     []string{"fastapi@==0.110.0", "uvicorn"},
+				RemovedDependencies: []string{"flask"},
+			},
+		},
+		{
+			name:    "unparseable versions are not major",
+			oldDeps: map[string]string{"internal-lib": "latest"},
+			newDeps: map[string]string{"internal-lib": "2.0.0"},
+			want:    DependencyChanges{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectDependencyChanges(tt.oldDeps, tt.newDeps)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectDependencyChanges() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
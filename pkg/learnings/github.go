@@ -0,0 +1,116 @@
+package learnings
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Label prefixes applied by ToGitHubIssues.
+const (
+	githubCategoryLabel = "category:"
+	githubPriorityLabel = "priority:"
+)
+
+// GitHubIssue is an issue to file for an improvement or failure. Its JSON
+// form matches the body of the GitHub "create an issue" API.
+type GitHubIssue struct {
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Labels []string `json:"labels"`
+}
+
+// JSON returns the issue encoded as a JSON object.
+func (i GitHubIssue) JSON() ([]byte, error) {
+	data, err := json.Marshal(i)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode GitHub issue %q: %w", i.Title, err)
+	}
+	return data, nil
+}
+
+// ToGitHubIssues returns an issue for each improvement and failure in l
+// whose priority is at or above minPriority, most urgent first. Failures
+// take their priority from their impact as in ExportBacklog. Each issue is
+// labeled "category:<category>" (when set) and "priority:<priority>". An
+// unrecognized minPriority exports every item.
+func ToGitHubIssues(l *Learnings, minPriority string) []GitHubIssue {
+	limit := priorityRank(minPriority)
+
+	type ranked struct {
+		rank  int
+		issue GitHubIssue
+	}
+	var found []ranked
+	for _, imp := range l.Improvements {
+		if rank := priorityRank(imp.Priority); rank <= limit {
+			found = append(found, ranked{rank, improvementIssue(imp)})
+		}
+	}
+	for _, f := range l.WhatFailed {
+		priority := failurePriority(f.Impact)
+		if rank := priorityRank(priority); rank <= limit {
+			found = append(found, ranked{rank, failureIssue(f, priority)})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].rank < found[j].rank })
+
+	issues := make([]GitHubIssue, len(found))
+	for i, r := range found {
+		issues[i] = r.issue
+	}
+	return issues
+}
+
+// improvementIssue renders imp as a GitHub issue.
+func improvementIssue(imp Improvement) GitHubIssue {
+	title := imp.Description
+	if imp.ImprovementID != "" {
+		title = fmt.Sprintf("[%s] %s", imp.ImprovementID, title)
+	}
+	var body strings.Builder
+	body.WriteString(imp.Description + "\n")
+	writeIssueField(&body, "Current state", imp.CurrentState)
+	writeIssueField(&body, "Desired state", imp.DesiredState)
+	writeIssueField(&body, "Effort", imp.EffortEstimate)
+	writeIssueField(&body, "Implementation hint", imp.ImplementationHint)
+	return GitHubIssue{Title: title, Body: body.String(), Labels: issueLabels(imp.Category, imp.Priority)}
+}
+
+// failureIssue renders f, filed at priority, as a GitHub issue.
+func failureIssue(f Failed, priority string) GitHubIssue {
+	var body strings.Builder
+	body.WriteString(f.Description + "\n")
+	writeIssueField(&body, "Error type", f.ErrorType)
+	writeIssueField(&body, "Frequency", f.Frequency)
+	writeIssueField(&body, "Impact", f.Impact)
+	writeIssueField(&body, "Suggested fix", f.SuggestedFix)
+	if len(f.Examples) > 0 {
+		body.WriteString("\n**Examples:**\n")
+		for _, example := range f.Examples {
+			body.WriteString("- " + example + "\n")
+		}
+	}
+	return GitHubIssue{Title: "Failure: " + f.Description, Body: body.String(), Labels: issueLabels(f.Category, priority)}
+}
+
+// writeIssueField appends a bold-labeled paragraph to body when value is
+// set.
+func writeIssueField(body *strings.Builder, label, value string) {
+	if value != "" {
+		fmt.Fprintf(body, "\n**%s:** %s\n", label, value)
+	}
+}
+
+// issueLabels returns the category and priority labels for an issue.
+func issueLabels(category, priority string) []string {
+	var labels []string
+	if category = strings.ToLower(strings.TrimSpace(category)); category != "" {
+		labels = append(labels, githubCategoryLabel+category)
+	}
+	if priority = strings.ToLower(strings.TrimSpace(priority)); priority != "" {
+		labels = append(labels, githubPriorityLabel+priority)
+	}
+	return labels
+}
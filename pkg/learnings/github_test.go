@@ -0,0 +1,73 @@
+package learnings
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestToGitHubIssues(t *testing.T) {
+	l := NewLearnings()
+	l.Improvements = []Improvement{
+		{ImprovementID: "IMP001", Category: "usability", Description: "tidy logs", Priority: "low"},
+		{ImprovementID: "IMP002", Category: "Accuracy", Description: "detect Bazel", Priority: "high", EffortEstimate: "medium"},
+		{ImprovementID: "IMP003", Category: "performance", Description: "stream large files", Priority: "critical"},
+	}
+	l.WhatFailed = []Failed{
+		{Category: "parsing", Description: "crash on BOM", Impact: "major", SuggestedFix: "strip the BOM"},
+		{Category: "detection", Description: "missed Makefile", Impact: "minor"},
+	}
+
+	tests := []struct {
+		minPriority string
+		wantTitles  []string
+	}{
+		{"critical", []string{"[IMP003] stream large files"}},
+		{"high", []string{"[IMP003] stream large files", "[IMP002] detect Bazel", "Failure: crash on BOM"}},
+		{"low", []string{"[IMP003] stream large files", "[IMP002] detect Bazel", "Failure: crash on BOM", "[IMP001] tidy logs", "Failure: missed Makefile"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.minPriority, func(t *testing.T) {
+			issues := ToGitHubIssues(l, tt.minPriority)
+			var titles []string
+			for _, issue := range issues {
+				titles = append(titles, issue.Title)
+			}
+			if !reflect.DeepEqual(titles, tt.wantTitles) {
+				t.Errorf("titles = %q, want %q", titles, tt.wantTitles)
+			}
+		})
+	}
+
+	issues := ToGitHubIssues(l, "high")
+	if want := []string{"category:accuracy", "priority:high"}; !reflect.DeepEqual(issues[1].Labels, want) {
+		t.Errorf("improvement labels = %q, want %q", issues[1].Labels, want)
+	}
+	if want := []string{"category:parsing", "priority:high"}; !reflect.DeepEqual(issues[2].Labels, want) {
+		t.Errorf("failure labels = %q, want %q", issues[2].Labels, want)
+	}
+	if !strings.Contains(issues[2].Body, "**Suggested fix:** strip the BOM") {
+		t.Errorf("failure body should include the suggested fix, got:\n%s", issues[2].Body)
+	}
+}
+
+func TestGitHubIssueJSON(t *testing.T) {
+	issue := GitHubIssue{Title: "detect Bazel", Body: "details", Labels: []string{"priority:high"}}
+
+	data, err := issue.JSON()
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+	var got struct {
+		Title  string   `json:"title"`
+		Body   string   `json:"body"`
+		Labels []string `json:"labels"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("JSON() produced invalid JSON %s: %v", data, err)
+	}
+	if got.Title != issue.Title || got.Body != issue.Body || !reflect.DeepEqual(got.Labels, issue.Labels) {
+		t.Errorf("JSON() = %s, want title, body, and labels", data)
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-// Learnings captures operational insights from Phase 2 tool runs
+// Learnings captures operational insights from Phase 2 tool runs.
+// It is not safe for concurrent writes; goroutines recording learnings
+// together should share a Recorder.
 type Learnings struct {
 	Metadata           Metadata                      `yaml:"metadata"`
 	ExecutionMetrics   ExecutionMetrics              `yaml:"execution_metrics"`
@@ -22,6 +24,7 @@ type Learnings struct {
 	Obsolescence       ObsolescenceIndicators        `yaml:"obsolescence_indicators"`
 	NextGenRecommendations NextGenerationRecommendations `yaml:"next_generation_recommendations"`
 	CustomNotes        []CustomNote                  `yaml:"custom_notes,omitempty"`
+	SecuritySignals    SecuritySignals               `yaml:"security_signals,omitempty"`
 }
 
 type Metadata struct {
@@ -190,6 +193,30 @@ func (l *Learnings) Save(path string) error {
 	return nil
 }
 
+// Append accumulates this run into the learnings file at path. Any existing
+// file is loaded, this run's entries are merged in (see Merge), and the
+// generation is incremented before the result is written back. If no file
+// exists the run is saved as generation 1. The receiver's Generation is
+// updated to match what was written.
+func (l *Learnings) Append(path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		l.Metadata.Generation = 1
+		return l.Save(path)
+	}
+
+	existing, err := Load(path)
+	if err != nil {
+		return fmt.Errorf("failed to load existing learnings: %w", err)
+	}
+
+	generation := existing.Metadata.Generation + 1
+	merged := Merge(existing, l)
+	merged.Metadata.Generation = generation
+	l.Metadata.Generation = generation
+
+	return merged.Save(path)
+}
+
 // NewLearnings creates a new empty Learnings instance
 func NewLearnings() *Learnings {
 	return &Learnings{
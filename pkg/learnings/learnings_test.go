@@ -115,3 +115,61 @@ func TestLoadInvalidYAML(t *testing.T) {
 		t.Error("Load() should return error for invalid YAML")
 	}
 }
+
+func TestAppendCreatesFirstGeneration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "learnings.yaml")
+
+	l := NewLearnings()
+	l.WhatFailed = append(l.WhatFailed, Failed{Description: "first failure"})
+
+	if err := l.Append(path); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.Metadata.Generation != 1 {
+		t.Errorf("Generation = %d, want 1", loaded.Metadata.Generation)
+	}
+	if len(loaded.WhatFailed) != 1 {
+		t.Errorf("WhatFailed length = %d, want 1", len(loaded.WhatFailed))
+	}
+}
+
+func TestAppendAccumulatesRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "learnings.yaml")
+	start := time.Now().Truncate(time.Second)
+
+	for i, desc := range []string{"run one", "run two", "run three"} {
+		l := NewLearnings()
+		l.Metadata.RunDate = start.Add(time.Duration(i) * time.Hour)
+		l.WhatWorkedWell = append(l.WhatWorkedWell, WorkedWell{Description: desc})
+		l.ExecutionMetrics.FilesProcessed = 10
+
+		if err := l.Append(path); err != nil {
+			t.Fatalf("Append() run %d error = %v", i+1, err)
+		}
+		if l.Metadata.Generation != i+1 {
+			t.Errorf("receiver Generation after run %d = %d, want %d", i+1, l.Metadata.Generation, i+1)
+		}
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.Metadata.Generation != 3 {
+		t.Errorf("Generation = %d, want 3", loaded.Metadata.Generation)
+	}
+	if len(loaded.WhatWorkedWell) != 3 {
+		t.Fatalf("WhatWorkedWell length = %d, want 3", len(loaded.WhatWorkedWell))
+	}
+	if loaded.WhatWorkedWell[0].Description != "run one" {
+		t.Errorf("WhatWorkedWell[0] = %q, want prior entry to survive", loaded.WhatWorkedWell[0].Description)
+	}
+	if loaded.ExecutionMetrics.FilesProcessed != 30 {
+		t.Errorf("FilesProcessed = %d, want 30", loaded.ExecutionMetrics.FilesProcessed)
+	}
+}
@@ -0,0 +1,97 @@
+package learnings
+
+// Merge folds src into dst and returns dst. If dst is nil a new Learnings is
+// allocated. Slices are concatenated, execution counters are summed, and the
+// Metadata, codebase changes, obsolescence verdict, next-generation
+// recommendations, and security signals of whichever input has the most
+// recent RunDate are kept.
+// EdgeCases are deduplicated by CaseID and Improvements by ImprovementID;
+// entries with an empty ID are always kept.
+func Merge(dst, src *Learnings) *Learnings {
+	if dst == nil {
+		dst = NewLearnings()
+	}
+	if src == nil {
+		return dst
+	}
+
+	dst.WhatWorkedWell = append(dst.WhatWorkedWell, src.WhatWorkedWell...)
+	dst.WhatFailed = append(dst.WhatFailed, src.WhatFailed...)
+	dst.Patterns = append(dst.Patterns, src.Patterns...)
+	dst.CustomNotes = append(dst.CustomNotes, src.CustomNotes...)
+	dst.EdgeCases = mergeEdgeCases(dst.EdgeCases, src.EdgeCases)
+	dst.Improvements = mergeImprovements(dst.Improvements, src.Improvements)
+
+	dst.ExecutionMetrics = sumMetrics(dst.ExecutionMetrics, src.ExecutionMetrics)
+
+	if src.Metadata.RunDate.After(dst.Metadata.RunDate) {
+		dst.Metadata = src.Metadata
+		dst.CodebaseChanges = src.CodebaseChanges
+		dst.Obsolescence = src.Obsolescence
+		dst.NextGenRecommendations = src.NextGenRecommendations
+		dst.SecuritySignals = src.SecuritySignals
+	}
+
+	return dst
+}
+
+// AddMetrics folds one run's execution counters into l, as Merge does for
+// a whole Learnings.
+func (l *Learnings) AddMetrics(m ExecutionMetrics) {
+	l.ExecutionMetrics = sumMetrics(l.ExecutionMetrics, m)
+}
+
+// sumMetrics adds the counters of two runs. MemoryPeakMB is a high-water mark,
+// so the larger value is kept rather than summed.
+func sumMetrics(a, b ExecutionMetrics) ExecutionMetrics {
+	peak := a.MemoryPeakMB
+	if b.MemoryPeakMB > peak {
+		peak = b.MemoryPeakMB
+	}
+	return ExecutionMetrics{
+		DurationSeconds:   a.DurationSeconds + b.DurationSeconds,
+		FilesProcessed:    a.FilesProcessed + b.FilesProcessed,
+		ErrorsEncountered: a.ErrorsEncountered + b.ErrorsEncountered,
+		WarningsGenerated: a.WarningsGenerated + b.WarningsGenerated,
+		ReportsGenerated:  a.ReportsGenerated + b.ReportsGenerated,
+		MemoryPeakMB:      peak,
+	}
+}
+
+func mergeEdgeCases(dst, src []EdgeCase) []EdgeCase {
+	seen := make(map[string]bool, len(dst))
+	for _, ec := range dst {
+		if ec.CaseID != "" {
+			seen[ec.CaseID] = true
+		}
+	}
+	for _, ec := range src {
+		if ec.CaseID != "" {
+			if seen[ec.CaseID] {
+				continue
+			}
+			seen[ec.CaseID] = true
+		}
+		dst = append(dst, ec)
+	}
+	return dst
+}
+
+func mergeImprovements(dst, src []Improvement) []Improvement {
+	seen := make(map[string]bool, len(dst))
+	for _, imp := range dst {
+		if imp.ImprovementID != "" {
+			seen[imp.ImprovementID] = true
+		}
+	}
+	for _, imp := range src {
+		if imp.ImprovementID != "" {
+			if seen[imp.ImprovementID] {
+				continue
+			}
+			seen[imp.ImprovementID] = true
+		}
+		dst = append(dst, imp)
+	}
+	return dst
+}
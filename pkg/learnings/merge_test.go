@@ -0,0 +1,155 @@
+package learnings
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeSumsCounters(t *testing.T) {
+	dst := &Learnings{
+		ExecutionMetrics: ExecutionMetrics{
+			DurationSeconds:   1.5,
+			FilesProcessed:    10,
+			ErrorsEncountered: 1,
+			WarningsGenerated: 2,
+			ReportsGenerated:  3,
+			MemoryPeakMB:      64,
+		},
+	}
+	src := &Learnings{
+		ExecutionMetrics: ExecutionMetrics{
+			DurationSeconds:   2.5,
+			FilesProcessed:    5,
+			ErrorsEncountered: 4,
+			WarningsGenerated: 1,
+			ReportsGenerated:  1,
+			MemoryPeakMB:      32,
+		},
+	}
+
+	got := Merge(dst, src).ExecutionMetrics
+	want := ExecutionMetrics{
+		DurationSeconds:   4,
+		FilesProcessed:    15,
+		ErrorsEncountered: 5,
+		WarningsGenerated: 3,
+		ReportsGenerated:  4,
+		MemoryPeakMB:      64,
+	}
+	if got != want {
+		t.Errorf("Merge() metrics = %+v, want %+v", got, want)
+	}
+}
+
+func TestMergeConcatenatesAndDeduplicates(t *testing.T) {
+	dst := &Learnings{
+		WhatWorkedWell: []WorkedWell{{Description: "fast parse"}},
+		WhatFailed:     []Failed{{Description: "timeout"}},
+		EdgeCases: []EdgeCase{
+			{CaseID: "EC-1", Description: "symlink loop"},
+			{Description: "unnamed"},
+		},
+		Improvements: []Improvement{
+			{ImprovementID: "IMP-1", Description: "cache results"},
+		},
+	}
+	src := &Learnings{
+		WhatWorkedWell: []WorkedWell{{Description: "clear output"}},
+		Patterns:       []Pattern{{PatternName: "repository"}},
+		CustomNotes:    []CustomNote{{Note: "check again"}},
+		EdgeCases: []EdgeCase{
+			{CaseID: "EC-1", Description: "symlink loop (duplicate)"},
+			{CaseID: "EC-2", Description: "huge file"},
+			{Description: "unnamed"},
+		},
+		Improvements: []Improvement{
+			{ImprovementID: "IMP-1", Description: "cache results (duplicate)"},
+			{ImprovementID: "IMP-2", Description: "parallel walk"},
+		},
+	}
+
+	got := Merge(dst, src)
+
+	if len(got.WhatWorkedWell) != 2 {
+		t.Errorf("WhatWorkedWell length = %d, want 2", len(got.WhatWorkedWell))
+	}
+	if len(got.WhatFailed) != 1 {
+		t.Errorf("WhatFailed length = %d, want 1", len(got.WhatFailed))
+	}
+	if len(got.Patterns) != 1 {
+		t.Errorf("Patterns length = %d, want 1", len(got.Patterns))
+	}
+	if len(got.CustomNotes) != 1 {
+		t.Errorf("CustomNotes length = %d, want 1", len(got.CustomNotes))
+	}
+	if len(got.EdgeCases) != 4 {
+		t.Errorf("EdgeCases length = %d, want 4", len(got.EdgeCases))
+	}
+	if got.EdgeCases[0].Description != "symlink loop" {
+		t.Errorf("EdgeCases[0] = %q, want first occurrence kept", got.EdgeCases[0].Description)
+	}
+	if len(got.Improvements) != 2 {
+		t.Errorf("Improvements length = %d, want 2", len(got.Improvements))
+	}
+	if got.Improvements[0].Description != "cache results" {
+		t.Errorf("Improvements[0] = %q, want first occurrence kept", got.Improvements[0].Description)
+	}
+}
+
+func TestMergeKeepsMostRecentMetadata(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(48 * time.Hour)
+
+	tests := []struct {
+		name    string
+		dstDate time.Time
+		srcDate time.Time
+		want    string
+	}{
+		{"src is newer", older, newer, "src"},
+		{"dst is newer", newer, older, "dst"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := &Learnings{Metadata: Metadata{ToolName: "dst", RunDate: tt.dstDate}}
+			dst.RecordSecrets(map[string]int{"dst": 1}, nil)
+			src := &Learnings{Metadata: Metadata{ToolName: "src", RunDate: tt.srcDate}}
+			src.RecordSecrets(map[string]int{"src": 2}, nil)
+
+			merged := Merge(dst, src)
+			if got := merged.Metadata.ToolName; got != tt.want {
+				t.Errorf("Metadata.ToolName = %q, want %q", got, tt.want)
+			}
+			if got := merged.SecuritySignals.ByRepository[tt.want]; got == 0 {
+				t.Errorf("SecuritySignals = %+v, want those of %s", merged.SecuritySignals, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeNil(t *testing.T) {
+	src := &Learnings{WhatFailed: []Failed{{Description: "x"}}}
+
+	got := Merge(nil, src)
+	if got == nil || len(got.WhatFailed) != 1 {
+		t.Errorf("Merge(nil, src) = %+v, want one failure", got)
+	}
+
+	dst := NewLearnings()
+	if Merge(dst, nil) != dst {
+		t.Error("Merge(dst, nil) should return dst unchanged")
+	}
+}
+
+func TestAddMetrics(t *testing.T) {
+	l := NewLearnings()
+	l.ExecutionMetrics = ExecutionMetrics{WarningsGenerated: 2, MemoryPeakMB: 10}
+
+	l.AddMetrics(ExecutionMetrics{WarningsGenerated: 3, ErrorsEncountered: 1, MemoryPeakMB: 5})
+
+	want := ExecutionMetrics{WarningsGenerated: 5, ErrorsEncountered: 1, MemoryPeakMB: 10}
+	if l.ExecutionMetrics != want {
+		t.Errorf("AddMetrics() metrics = %+v, want %+v", l.ExecutionMetrics, want)
+	}
+}
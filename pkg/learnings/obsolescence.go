@@ -0,0 +1,130 @@
+package learnings
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultObsolescenceThreshold is the score at or above which Phase 2 tools
+// are considered obsolete.
+const DefaultObsolescenceThreshold = 0.5
+
+// ErrObsolete is wrapped by errors reporting that the codebase has changed
+// enough for its Phase 2 tools to be regenerated.
+var ErrObsolete = errors.New("phase 2 tools are obsolete")
+
+// ObsolescenceWeights controls how much each category of codebase change
+// contributes to the obsolescence score. Weights are relative; they are
+// normalized by their sum, so they need not add up to 1.
+type ObsolescenceWeights struct {
+	Structural   float64
+	Language     float64
+	Framework    float64
+	Dependency   float64
+	Architecture float64
+}
+
+// DefaultObsolescenceWeights returns the weights used by ComputeObsolescence.
+// Language and architecture shifts weigh most because they invalidate the
+// assumptions Phase 2 tools were generated under.
+func DefaultObsolescenceWeights() ObsolescenceWeights {
+	return ObsolescenceWeights{
+		Structural:   0.15,
+		Language:     0.25,
+		Framework:    0.20,
+		Dependency:   0.15,
+		Architecture: 0.25,
+	}
+}
+
+// Number of changes in each category at which that category is considered
+// fully changed. Counts beyond these saturate at 1.0.
+const (
+	structuralSaturation   = 5
+	languageSaturation     = 2
+	frameworkSaturation    = 3
+	dependencySaturation   = 5
+	architectureSaturation = 2
+)
+
+// ComputeObsolescence scores the detected codebase changes using the default
+// weights and threshold.
+func ComputeObsolescence(l *Learnings) ObsolescenceIndicators {
+	return ComputeObsolescenceWithWeights(l, DefaultObsolescenceWeights())
+}
+
+// ComputeObsolescenceWithWeights weighs structural, language, framework,
+// dependency, and architecture changes into a score between 0 and 1. Each
+// category contributes its weight scaled by how many changes it saw relative
+// to its saturation point. IsObsolete is set when the score reaches
+// DefaultObsolescenceThreshold.
+func ComputeObsolescenceWithWeights(l *Learnings, w ObsolescenceWeights) ObsolescenceIndicators {
+	c := l.CodebaseChanges
+
+	languageCount := len(c.LanguageChanges.NewLanguages) + len(c.LanguageChanges.RemovedLanguages)
+	if languageCount == 0 && c.LanguageChanges.LanguageShift != "" {
+		languageCount = 1
+	}
+
+	categories := []struct {
+		name       string
+		weight     float64
+		count      int
+		saturation int
+	}{
+		{"structural", w.Structural, len(c.StructuralChanges.NewDirectories) + len(c.StructuralChanges.RemovedDirectories) + len(c.StructuralChanges.RenamedDirectories), structuralSaturation},
+		{"language", w.Language, languageCount, languageSaturation},
+		{"framework", w.Framework, len(c.FrameworkChanges.NewFrameworks) + len(c.FrameworkChanges.RemovedFrameworks) + len(c.FrameworkChanges.VersionUpgrades), frameworkSaturation},
+		{"dependency", w.Dependency, len(c.DependencyChanges.NewDependencies) + len(c.DependencyChanges.RemovedDependencies) + len(c.DependencyChanges.MajorUpgrades), dependencySaturation},
+		{"architecture", w.Architecture, len(c.ArchitectureChanges.NewServices) + len(c.ArchitectureChanges.RemovedServices) + len(c.ArchitectureChanges.RefactoredServices) + len(c.ArchitectureChanges.PatternShifts), architectureSaturation},
+	}
+
+	var totalWeight, weighted float64
+	reasons := []string{}
+	for _, cat := range categories {
+		if cat.weight <= 0 {
+			continue
+		}
+		totalWeight += cat.weight
+		if cat.count == 0 {
+			continue
+		}
+		intensity := float64(cat.count) / float64(cat.saturation)
+		if intensity > 1 {
+			intensity = 1
+		}
+		weighted += cat.weight * intensity
+		reasons = append(reasons, fmt.Sprintf("%d %s change(s) detected", cat.count, cat.name))
+	}
+
+	score := 0.0
+	if totalWeight > 0 {
+		score = weighted / totalWeight
+	}
+
+	indicators := ObsolescenceIndicators{
+		IsObsolete:        score >= DefaultObsolescenceThreshold,
+		ObsolescenceScore: score,
+		Reasons:           reasons,
+		Confidence:        obsolescenceConfidence(len(reasons)),
+	}
+	if indicators.IsObsolete {
+		indicators.Recommendation = "Regenerate Phase 1 analysis and Phase 2 tools"
+	} else {
+		indicators.Recommendation = "Existing Phase 2 tools remain viable"
+	}
+	return indicators
+}
+
+// obsolescenceConfidence reports confidence based on how many independent
+// categories of change corroborate the score.
+func obsolescenceConfidence(signals int) string {
+	switch {
+	case signals == 0 || signals >= 3:
+		return "high"
+	case signals == 2:
+		return "medium"
+	default:
+		return "low"
+	}
+}
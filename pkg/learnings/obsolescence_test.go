@@ -0,0 +1,89 @@
+package learnings
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComputeObsolescence_NoChanges(t *testing.T) {
+	got := ComputeObsolescence(NewLearnings())
+
+	if got.ObsolescenceScore != 0 {
+		t.Errorf("ObsolescenceScore = %v, want 0", got.ObsolescenceScore)
+	}
+	if got.IsObsolete {
+		t.Error("IsObsolete = true, want false")
+	}
+	if len(got.Reasons) != 0 {
+		t.Errorf("Reasons = %v, want none", got.Reasons)
+	}
+}
+
+func TestComputeObsolescence_HeavyChanges(t *testing.T) {
+	l := NewLearnings()
+	l.CodebaseChanges = CodebaseChanges{
+		StructuralChanges: StructuralChanges{
+			NewDirectories:     []string{"a", "b", "c"},
+			RemovedDirectories: []string{"d", "e"},
+		},
+		LanguageChanges: LanguageChanges{
+			NewLanguages: []string{"Rust", "Kotlin"},
+		},
+		FrameworkChanges: FrameworkChanges{
+			NewFrameworks: []string{"Tokio", "Ktor", "Axum"},
+		},
+		DependencyChanges: DependencyChanges{
+			MajorUpgrades: []string{"x", "y", "z", "w", "v"},
+		},
+		ArchitectureChanges: ArchitectureChanges{
+			NewServices:   []string{"billing"},
+			PatternShifts: []string{"monolith to microservices"},
+		},
+	}
+
+	got := ComputeObsolescence(l)
+
+	if got.ObsolescenceScore != 1 {
+		t.Errorf("ObsolescenceScore = %v, want 1", got.ObsolescenceScore)
+	}
+	if !got.IsObsolete {
+		t.Error("IsObsolete = false, want true")
+	}
+	if len(got.Reasons) != 5 {
+		t.Errorf("Reasons = %v, want 5 entries", got.Reasons)
+	}
+}
+
+func TestComputeObsolescence_PartialChanges(t *testing.T) {
+	l := NewLearnings()
+	l.CodebaseChanges.StructuralChanges.NewDirectories = []string{"a"}
+
+	got := ComputeObsolescence(l)
+
+	if got.ObsolescenceScore <= 0 || got.ObsolescenceScore >= DefaultObsolescenceThreshold {
+		t.Errorf("ObsolescenceScore = %v, want between 0 and threshold", got.ObsolescenceScore)
+	}
+	if got.IsObsolete {
+		t.Error("IsObsolete = true, want false")
+	}
+	if len(got.Reasons) != 1 || !strings.Contains(got.Reasons[0], "structural") {
+		t.Errorf("Reasons = %v, want one structural reason", got.Reasons)
+	}
+}
+
+func TestComputeObsolescenceWithWeights(t *testing.T) {
+	l := NewLearnings()
+	l.CodebaseChanges.LanguageChanges.NewLanguages = []string{"Rust", "Zig"}
+
+	// Only language changes count, so a fully-changed language category
+	// yields the maximum score.
+	weights := ObsolescenceWeights{Language: 1}
+	got := ComputeObsolescenceWithWeights(l, weights)
+
+	if got.ObsolescenceScore != 1 {
+		t.Errorf("ObsolescenceScore = %v, want 1", got.ObsolescenceScore)
+	}
+	if !got.IsObsolete {
+		t.Error("IsObsolete = false, want true")
+	}
+}
@@ -0,0 +1,34 @@
+package learnings
+
+import (
+	"sort"
+	"strings"
+)
+
+// priorityRanks orders the standard priority vocabulary from most to least
+// urgent. Unknown priorities sort after all known ones.
+var priorityRanks = map[string]int{
+	"critical": 0,
+	"high":     1,
+	"medium":   2,
+	"low":      3,
+}
+
+// priorityRank returns the sort rank of a priority; lower is more urgent.
+func priorityRank(priority string) int {
+	if rank, ok := priorityRanks[strings.ToLower(strings.TrimSpace(priority))]; ok {
+		return rank
+	}
+	return len(priorityRanks)
+}
+
+// sortedImprovements returns a copy of improvements ordered by priority,
+// preserving the original order among equal priorities.
+func sortedImprovements(improvements []Improvement) []Improvement {
+	sorted := make([]Improvement, len(improvements))
+	copy(sorted, improvements)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return priorityRank(sorted[i].Priority) < priorityRank(sorted[j].Priority)
+	})
+	return sorted
+}
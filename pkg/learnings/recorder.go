@@ -0,0 +1,87 @@
+package learnings
+
+import "sync"
+
+// Recorder accumulates learnings from multiple goroutines. Each method
+// locks, so Phase 2 tools can record from concurrent workers; the
+// underlying Learnings must not be written directly while a Recorder is in
+// use.
+type Recorder struct {
+	mu sync.Mutex
+	l  *Learnings
+}
+
+// NewRecorder returns a Recorder that appends to l, or to a new Learnings
+// when l is nil.
+func NewRecorder(l *Learnings) *Recorder {
+	if l == nil {
+		l = NewLearnings()
+	}
+	return &Recorder{l: l}
+}
+
+// RecordWorkedWell appends w.
+func (r *Recorder) RecordWorkedWell(w WorkedWell) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.l.AddWorkedWell(w)
+}
+
+// RecordFailure appends f.
+func (r *Recorder) RecordFailure(f Failed) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.l.AddFailure(f)
+}
+
+// RecordEdgeCase appends e, assigning a CaseID as AddEdgeCase does.
+func (r *Recorder) RecordEdgeCase(e EdgeCase) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.l.AddEdgeCase(e)
+}
+
+// RecordPattern appends p.
+func (r *Recorder) RecordPattern(p Pattern) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.l.AddPattern(p)
+}
+
+// RecordImprovement appends i, assigning an ImprovementID as
+// AddImprovement does.
+func (r *Recorder) RecordImprovement(i Improvement) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.l.AddImprovement(i)
+}
+
+// RecordCustomNote appends n.
+func (r *Recorder) RecordCustomNote(n CustomNote) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.l.AddCustomNote(n)
+}
+
+// RecordMetrics folds m into the execution metrics as AddMetrics does.
+func (r *Recorder) RecordMetrics(m ExecutionMetrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.l.AddMetrics(m)
+}
+
+// Snapshot returns a copy of the learnings recorded so far. Its top-level
+// slices are independent of the Recorder, so later records do not change
+// it.
+func (r *Recorder) Snapshot() *Learnings {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	snapshot := *r.l
+	snapshot.WhatWorkedWell = append([]WorkedWell{}, r.l.WhatWorkedWell...)
+	snapshot.WhatFailed = append([]Failed{}, r.l.WhatFailed...)
+	snapshot.EdgeCases = append([]EdgeCase{}, r.l.EdgeCases...)
+	snapshot.Patterns = append([]Pattern{}, r.l.Patterns...)
+	snapshot.Improvements = append([]Improvement{}, r.l.Improvements...)
+	snapshot.CustomNotes = append([]CustomNote{}, r.l.CustomNotes...)
+	return &snapshot
+}
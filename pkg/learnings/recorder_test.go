@@ -0,0 +1,60 @@
+package learnings
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestRecorder_ConcurrentRecordFailure(t *testing.T) {
+	const workers, perWorker = 16, 200
+	r := NewRecorder(nil)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				r.RecordFailure(Failed{Description: fmt.Sprintf("worker %d failure %d", w, i)})
+				r.RecordEdgeCase(EdgeCase{Description: "edge"})
+				if i%50 == 0 {
+					_ = r.Snapshot()
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	got := r.Snapshot()
+	if len(got.WhatFailed) != workers*perWorker {
+		t.Errorf("recorded %d failures, want %d", len(got.WhatFailed), workers*perWorker)
+	}
+	ids := make(map[string]bool)
+	for _, e := range got.EdgeCases {
+		if ids[e.CaseID] {
+			t.Fatalf("duplicate CaseID %s", e.CaseID)
+		}
+		ids[e.CaseID] = true
+	}
+}
+
+func TestRecorder_SnapshotIsIndependent(t *testing.T) {
+	base := NewLearnings()
+	r := NewRecorder(base)
+	r.RecordPattern(Pattern{PatternName: "first"})
+	r.RecordMetrics(ExecutionMetrics{FilesProcessed: 3})
+
+	snapshot := r.Snapshot()
+	r.RecordPattern(Pattern{PatternName: "second"})
+
+	if len(snapshot.Patterns) != 1 {
+		t.Errorf("snapshot changed after a later record: %+v", snapshot.Patterns)
+	}
+	if snapshot.ExecutionMetrics.FilesProcessed != 3 {
+		t.Errorf("FilesProcessed = %d, want 3", snapshot.ExecutionMetrics.FilesProcessed)
+	}
+	if len(base.Patterns) != 2 {
+		t.Errorf("Recorder should append to the wrapped Learnings; got %d patterns", len(base.Patterns))
+	}
+}
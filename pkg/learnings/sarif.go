@@ -0,0 +1,186 @@
+package learnings
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// sarifDefaultTool names the driver when the learnings carry no tool name.
+	sarifDefaultTool = "codebase-reviewer"
+
+	// sarifArtifact is the location reported for each result; findings are
+	// recorded in the learnings file rather than in source code.
+	sarifArtifact = "learnings.yaml"
+)
+
+// SARIF levels used for results.
+const (
+	sarifLevelError   = "error"
+	sarifLevelWarning = "warning"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version,omitempty"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// ToSARIF renders the failures and the critical- and high-priority
+// improvements in l as a SARIF 2.1.0 log. Failures are reported at the
+// "error" level, critical improvements as "error", and high-priority
+// improvements as "warning".
+func ToSARIF(l *Learnings) ([]byte, error) {
+	toolName := l.Metadata.ToolName
+	if toolName == "" {
+		toolName = sarifDefaultTool
+	}
+
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: toolName, Version: l.Metadata.ToolVersion, Rules: []sarifRule{}}},
+		Results: []sarifResult{},
+	}
+	seenRules := make(map[string]bool)
+	addResult := func(ruleID, summary, level, text string, props map[string]string) {
+		if !seenRules[ruleID] {
+			seenRules[ruleID] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: ruleID, ShortDescription: sarifMessage{Text: summary}})
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:     ruleID,
+			Level:      level,
+			Message:    sarifMessage{Text: text},
+			Locations:  []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: sarifArtifact}}}},
+			Properties: props,
+		})
+	}
+
+	for _, f := range l.WhatFailed {
+		kind := firstNonEmpty(f.ErrorType, f.Category, "general")
+		text := f.Description
+		if f.SuggestedFix != "" {
+			text += " Suggested fix: " + f.SuggestedFix
+		}
+		addResult("failed/"+sarifSlug(kind), "Failure: "+kind, sarifLevelError, text,
+			nonEmptyProps(map[string]string{"category": f.Category, "impact": f.Impact, "frequency": f.Frequency}))
+	}
+
+	for _, imp := range l.Improvements {
+		level, ok := improvementLevel(imp.Priority)
+		if !ok {
+			continue
+		}
+		ruleID := imp.ImprovementID
+		if ruleID == "" {
+			ruleID = "improvement/" + sarifSlug(firstNonEmpty(imp.Category, "general"))
+		}
+		addResult(ruleID, "Improvement: "+firstNonEmpty(imp.Category, "general"), level, imp.Description,
+			nonEmptyProps(map[string]string{"category": imp.Category, "priority": strings.ToLower(imp.Priority), "effort": imp.EffortEstimate}))
+	}
+
+	data, err := json.MarshalIndent(sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SARIF: %w", err)
+	}
+	return data, nil
+}
+
+// improvementLevel maps an improvement priority to a SARIF level. Only
+// critical and high priorities are reported.
+func improvementLevel(priority string) (string, bool) {
+	switch priorityRank(priority) {
+	case priorityRanks["critical"]:
+		return sarifLevelError, true
+	case priorityRanks["high"]:
+		return sarifLevelWarning, true
+	}
+	return "", false
+}
+
+// sarifSlug lowercases s and replaces runs of non-alphanumerics with '-'
+// to form a rule ID segment.
+func sarifSlug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// nonEmptyProps drops empty values, returning nil when none remain.
+func nonEmptyProps(props map[string]string) map[string]string {
+	for k, v := range props {
+		if v == "" {
+			delete(props, k)
+		}
+	}
+	if len(props) == 0 {
+		return nil
+	}
+	return props
+}
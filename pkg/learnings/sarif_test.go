@@ -0,0 +1,108 @@
+package learnings
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestToSARIF(t *testing.T) {
+	l := &Learnings{
+		Metadata: Metadata{ToolName: "codebase-reviewer", ToolVersion: "1.0.0"},
+		WhatFailed: []Failed{
+			{Category: "parsing", ErrorType: "Timeout Error", Description: "parser timed out", SuggestedFix: "stream input"},
+		},
+		Improvements: []Improvement{
+			{ImprovementID: "IMP-1", Category: "security", Description: "scan secrets", Priority: "critical"},
+			{Category: "performance", Description: "parallel walk", Priority: "High"},
+			{Category: "docs", Description: "nicer README", Priority: "low"},
+		},
+	}
+
+	data, err := ToSARIF(l)
+	if err != nil {
+		t.Fatalf("ToSARIF() error = %v", err)
+	}
+
+	var doc struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID  string `json:"ruleId"`
+				Level   string `json:"level"`
+				Message struct {
+					Text string `json:"text"`
+				} `json:"message"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("SARIF is not valid JSON: %v", err)
+	}
+	if doc.Version != "2.1.0" {
+		t.Errorf("version = %q, want 2.1.0", doc.Version)
+	}
+	if len(doc.Runs) != 1 {
+		t.Fatalf("len(runs) = %d, want 1", len(doc.Runs))
+	}
+
+	results := doc.Runs[0].Results
+	want := []struct{ ruleID, level string }{
+		{"failed/timeout-error", "error"},
+		{"IMP-1", "error"},
+		{"improvement/performance", "warning"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("len(results) = %d, want %d (low priority excluded)", len(results), len(want))
+	}
+	for i, w := range want {
+		if results[i].RuleID != w.ruleID || results[i].Level != w.level {
+			t.Errorf("results[%d] = %s/%s, want %s/%s", i, results[i].RuleID, results[i].Level, w.ruleID, w.level)
+		}
+	}
+	if results[0].Message.Text != "parser timed out Suggested fix: stream input" {
+		t.Errorf("results[0].message = %q", results[0].Message.Text)
+	}
+	if got := len(doc.Runs[0].Tool.Driver.Rules); got != 3 {
+		t.Errorf("len(rules) = %d, want 3", got)
+	}
+}
+
+func TestToSARIF_Empty(t *testing.T) {
+	data, err := ToSARIF(NewLearnings())
+	if err != nil {
+		t.Fatalf("ToSARIF() error = %v", err)
+	}
+
+	var doc struct {
+		Runs []struct {
+			Results []json.RawMessage `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Runs) != 1 || doc.Runs[0].Results == nil || len(doc.Runs[0].Results) != 0 {
+		t.Errorf("expected one run with an empty results array, got %s", data)
+	}
+}
+
+func TestSarifSlug(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Timeout Error", "timeout-error"},
+		{"  edge--case  ", "edge-case"},
+		{"IO/Read", "io-read"},
+	}
+	for _, tt := range tests {
+		if got := sarifSlug(tt.in); got != tt.want {
+			t.Errorf("sarifSlug(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
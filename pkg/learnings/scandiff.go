@@ -0,0 +1,122 @@
+package learnings
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+)
+
+// ApplyScanReports replaces the learnings-derived scan figures in p with
+// ones measured from the previous and current scan reports: repository,
+// file, and service counts, primary languages, and the directories,
+// languages, frameworks, dependencies, and services that changed. This
+// keeps the prompt accurate even when the learnings are sparse.
+func (p *RegenerationPrompt) ApplyScanReports(previous, current *scanner.ScanReport) {
+	oldDirs, newDirs := reportDirectories(previous), reportDirectories(current)
+	added, removed := setDifference(newDirs, oldDirs), setDifference(oldDirs, newDirs)
+
+	p.Context.PreviousAnalysis = PreviousAnalysis{
+		RepositoriesFound:  len(previous.Repositories),
+		PrimaryLanguages:   primaryLanguages(previous),
+		TotalFiles:         previous.TotalFiles,
+		ServicesIdentified: len(reportServices(previous)),
+	}
+	p.Context.CurrentScan = CurrentScan{
+		RepositoriesFound:  len(current.Repositories),
+		PrimaryLanguages:   primaryLanguages(current),
+		TotalFiles:         current.TotalFiles,
+		NewDirectories:     added,
+		RemovedDirectories: removed,
+	}
+
+	structural := []string{}
+	if len(added) > 0 {
+		structural = append(structural, fmt.Sprintf("Added %d new directories", len(added)))
+	}
+	if len(removed) > 0 {
+		structural = append(structural, fmt.Sprintf("Removed %d directories", len(removed)))
+	}
+
+	oldServices, newServices := reportServices(previous), reportServices(current)
+	architecture := []string{}
+	for _, svc := range setDifference(newServices, oldServices) {
+		architecture = append(architecture, "New service: "+svc)
+	}
+	for _, svc := range setDifference(oldServices, newServices) {
+		architecture = append(architecture, "Removed service: "+svc)
+	}
+
+	p.Context.ChangesDetected = ChangesDetected{
+		StructuralChanges:   structural,
+		NewLanguages:        setDifference(languageNames(current), languageNames(previous)),
+		NewFrameworks:       setDifference(setOf(current.Frameworks), setOf(previous.Frameworks)),
+		DependencyShifts:    DetectDependencyChanges(previous.Dependencies, current.Dependencies).MajorUpgrades,
+		ArchitectureChanges: architecture,
+	}
+}
+
+// reportDirectories collects every repository's directories as paths
+// relative to the scan target.
+func reportDirectories(r *scanner.ScanReport) map[string]bool {
+	dirs := make(map[string]bool)
+	for _, repo := range r.Repositories {
+		for _, dir := range repo.Directories {
+			dirs[path.Join(repo.RelativePath, dir)] = true
+		}
+	}
+	return dirs
+}
+
+// reportServices identifies every repository's services as "name (path)",
+// with the path relative to the scan target.
+func reportServices(r *scanner.ScanReport) map[string]bool {
+	services := make(map[string]bool)
+	for _, repo := range r.Repositories {
+		for _, svc := range repo.Services {
+			services[fmt.Sprintf("%s (%s)", svc.Name, path.Join(repo.RelativePath, svc.Path))] = true
+		}
+	}
+	return services
+}
+
+// primaryLanguages returns each repository's primary language, sorted and
+// without duplicates.
+func primaryLanguages(r *scanner.ScanReport) []string {
+	langs := make(map[string]bool)
+	for _, repo := range r.Repositories {
+		if repo.PrimaryLanguage != "" {
+			langs[repo.PrimaryLanguage] = true
+		}
+	}
+	return setDifference(langs, nil)
+}
+
+func languageNames(r *scanner.ScanReport) map[string]bool {
+	langs := make(map[string]bool, len(r.Languages))
+	for lang := range r.Languages {
+		langs[lang] = true
+	}
+	return langs
+}
+
+func setOf(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// setDifference returns the sorted members of a that are not in b.
+func setDifference(a, b map[string]bool) []string {
+	diff := []string{}
+	for v := range a {
+		if !b[v] {
+			diff = append(diff, v)
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
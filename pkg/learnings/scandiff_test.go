@@ -0,0 +1,88 @@
+package learnings
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+)
+
+func TestApplyScanReports(t *testing.T) {
+	previous := &scanner.ScanReport{
+		TotalFiles: 40,
+		Languages:  map[string]scanner.LanguageStats{"Go": {Files: 30}, "Shell": {Files: 10}},
+		Frameworks: []string{"Gin"},
+		Dependencies: map[string]string{
+			"github.com/gin-gonic/gin": "v1.9.0",
+		},
+		Repositories: []scanner.RepositoryReport{{
+			RelativePath:    ".",
+			PrimaryLanguage: "Go",
+			Directories:     []string{"cmd", "cmd/api", "legacy"},
+			Services:        []scanner.Service{{Name: "api", Path: "cmd/api"}},
+		}},
+	}
+	current := &scanner.ScanReport{
+		TotalFiles: 55,
+		Languages:  map[string]scanner.LanguageStats{"Go": {Files: 30}, "Rust": {Files: 5}, "TypeScript": {Files: 20}},
+		Frameworks: []string{"Gin", "React"},
+		Dependencies: map[string]string{
+			"github.com/gin-gonic/gin": "v2.0.0",
+		},
+		Repositories: []scanner.RepositoryReport{
+			{
+				RelativePath:    ".",
+				PrimaryLanguage: "Go",
+				Directories:     []string{"cmd", "cmd/api", "cmd/worker"},
+				Services:        []scanner.Service{{Name: "api", Path: "cmd/api"}, {Name: "worker", Path: "cmd/worker"}},
+			},
+			{RelativePath: "web", PrimaryLanguage: "TypeScript", Directories: []string{"src"}},
+		},
+	}
+
+	p, err := GenerateRegenerationPrompt("tool", "1.0", 2, "app", "/src/app", "sha256:a", "sha256:b", "drift", NewLearnings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.ApplyScanReports(previous, current)
+
+	wantPrevious := PreviousAnalysis{RepositoriesFound: 1, PrimaryLanguages: []string{"Go"}, TotalFiles: 40, ServicesIdentified: 1}
+	if !reflect.DeepEqual(p.Context.PreviousAnalysis, wantPrevious) {
+		t.Errorf("PreviousAnalysis = %+v, want %+v", p.Context.PreviousAnalysis, wantPrevious)
+	}
+	wantCurrent := CurrentScan{
+		RepositoriesFound:  2,
+		PrimaryLanguages:   []string{"Go", "TypeScript"},
+		TotalFiles:         55,
+		NewDirectories:     []string{"cmd/worker", "web/src"},
+		RemovedDirectories: []string{"legacy"},
+	}
+	if !reflect.DeepEqual(p.Context.CurrentScan, wantCurrent) {
+		t.Errorf("CurrentScan = %+v, want %+v", p.Context.CurrentScan, wantCurrent)
+	}
+
+	wantChanges := ChangesDetected{
+		StructuralChanges:   []string{"Added 2 new directories", "Removed 1 directories"},
+		NewLanguages:        []string{"Rust", "TypeScript"},
+		NewFrameworks:       []string{"React"},
+		DependencyShifts:    []string{"github.com/gin-gonic/gin v1.9.0 -> v2.0.0"},
+		ArchitectureChanges: []string{"New service: worker (cmd/worker)"},
+	}
+	if !reflect.DeepEqual(p.Context.ChangesDetected, wantChanges) {
+		t.Errorf("ChangesDetected = %+v, want %+v", p.Context.ChangesDetected, wantChanges)
+	}
+}
+
+func TestApplyScanReports_Unchanged(t *testing.T) {
+	report := &scanner.ScanReport{
+		Languages:    map[string]scanner.LanguageStats{"Go": {Files: 1}},
+		Repositories: []scanner.RepositoryReport{{RelativePath: ".", Directories: []string{"pkg"}}},
+	}
+	p := &RegenerationPrompt{}
+	p.ApplyScanReports(report, report)
+
+	c := p.Context.ChangesDetected
+	if len(c.NewLanguages) != 0 || len(c.StructuralChanges) != 0 || len(c.ArchitectureChanges) != 0 {
+		t.Errorf("ChangesDetected = %+v, want no changes for identical reports", c)
+	}
+}
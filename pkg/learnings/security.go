@@ -0,0 +1,38 @@
+package learnings
+
+// SecuritySignals counts the potential secrets found by the latest scan
+// that looked for them. It holds counts only: the secrets themselves are
+// never written to learnings.
+type SecuritySignals struct {
+	PotentialSecrets int            `yaml:"potential_secrets"`
+	ByKind           map[string]int `yaml:"by_kind,omitempty"`
+	ByRepository     map[string]int `yaml:"by_repository,omitempty"`
+}
+
+// RecordSecrets replaces the security signals with the counts of one scan:
+// byRepository maps each scanned repository to its number of findings,
+// and byKind counts the findings by kind. Repositories without findings
+// are left out.
+func (l *Learnings) RecordSecrets(byRepository, byKind map[string]int) {
+	s := SecuritySignals{}
+	for repo, n := range byRepository {
+		if n == 0 {
+			continue
+		}
+		if s.ByRepository == nil {
+			s.ByRepository = make(map[string]int)
+		}
+		s.ByRepository[repo] = n
+		s.PotentialSecrets += n
+	}
+	for kind, n := range byKind {
+		if n == 0 {
+			continue
+		}
+		if s.ByKind == nil {
+			s.ByKind = make(map[string]int)
+		}
+		s.ByKind[kind] = n
+	}
+	l.SecuritySignals = s
+}
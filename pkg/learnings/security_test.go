@@ -0,0 +1,55 @@
+package learnings
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecordSecrets(t *testing.T) {
+	l := NewLearnings()
+	l.RecordSecrets(map[string]int{"api": 2, "web": 0, "lib": 1}, map[string]int{"AWS access key": 3, "private key": 0})
+	want := SecuritySignals{
+		PotentialSecrets: 3,
+		ByKind:           map[string]int{"AWS access key": 3},
+		ByRepository:     map[string]int{"api": 2, "lib": 1},
+	}
+	if !reflect.DeepEqual(l.SecuritySignals, want) {
+		t.Errorf("SecuritySignals = %+v, want %+v", l.SecuritySignals, want)
+	}
+
+	// A later scan replaces the counts rather than adding to them.
+	l.RecordSecrets(map[string]int{"api": 0}, nil)
+	if !reflect.DeepEqual(l.SecuritySignals, SecuritySignals{}) {
+		t.Errorf("SecuritySignals after a clean scan = %+v, want zero", l.SecuritySignals)
+	}
+}
+
+func TestSecuritySignals_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "learnings.yaml")
+	l := NewLearnings()
+	if err := l.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "security_signals") {
+		t.Error("learnings without a secrets scan record security_signals")
+	}
+
+	l.RecordSecrets(map[string]int{"api": 1}, map[string]int{"private key": 1})
+	if err := l.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.SecuritySignals, l.SecuritySignals) {
+		t.Errorf("loaded SecuritySignals = %+v, want %+v", loaded.SecuritySignals, l.SecuritySignals)
+	}
+}
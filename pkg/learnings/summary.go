@@ -0,0 +1,66 @@
+package learnings
+
+import (
+	"fmt"
+	"strings"
+)
+
+// summaryTopImprovements is the number of improvements listed in Summary.
+const summaryTopImprovements = 3
+
+// Summary returns a short human-readable digest of the learnings: entry
+// counts, the highest-priority improvements, the obsolescence verdict, and
+// key execution metrics.
+func (l *Learnings) Summary() string {
+	var b strings.Builder
+
+	b.WriteString("Learnings Summary\n")
+	if l.Metadata.ToolName != "" {
+		b.WriteString(fmt.Sprintf("Tool: %s %s (generation %d)\n", l.Metadata.ToolName, l.Metadata.ToolVersion, l.Metadata.Generation))
+	}
+	if l.Metadata.CodebaseName != "" {
+		b.WriteString(fmt.Sprintf("Codebase: %s\n", l.Metadata.CodebaseName))
+	}
+
+	b.WriteString("\nCounts:\n")
+	b.WriteString(fmt.Sprintf("- Worked well: %d\n", len(l.WhatWorkedWell)))
+	b.WriteString(fmt.Sprintf("- Failed: %d\n", len(l.WhatFailed)))
+	b.WriteString(fmt.Sprintf("- Edge cases: %d\n", len(l.EdgeCases)))
+	b.WriteString(fmt.Sprintf("- Patterns: %d\n", len(l.Patterns)))
+	b.WriteString(fmt.Sprintf("- Improvements: %d\n", len(l.Improvements)))
+
+	b.WriteString("\nTop Improvements:\n")
+	top := sortedImprovements(l.Improvements)
+	if len(top) > summaryTopImprovements {
+		top = top[:summaryTopImprovements]
+	}
+	if len(top) == 0 {
+		b.WriteString("- none\n")
+	}
+	for i, imp := range top {
+		priority := imp.Priority
+		if priority == "" {
+			priority = "unspecified"
+		}
+		b.WriteString(fmt.Sprintf("%d. [%s] %s\n", i+1, priority, imp.Description))
+	}
+
+	b.WriteString("\nObsolescence:\n")
+	verdict := "not obsolete"
+	if l.Obsolescence.IsObsolete {
+		verdict = "OBSOLETE"
+	}
+	b.WriteString(fmt.Sprintf("- Verdict: %s (score %.2f)\n", verdict, l.Obsolescence.ObsolescenceScore))
+	if l.Obsolescence.Recommendation != "" {
+		b.WriteString(fmt.Sprintf("- Recommendation: %s\n", l.Obsolescence.Recommendation))
+	}
+
+	m := l.ExecutionMetrics
+	b.WriteString("\nExecution Metrics:\n")
+	b.WriteString(fmt.Sprintf("- Duration: %.1fs\n", m.DurationSeconds))
+	b.WriteString(fmt.Sprintf("- Files processed: %d\n", m.FilesProcessed))
+	b.WriteString(fmt.Sprintf("- Errors: %d\n", m.ErrorsEncountered))
+	b.WriteString(fmt.Sprintf("- Warnings: %d\n", m.WarningsGenerated))
+
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package learnings
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSummaryOrdersImprovementsByPriority(t *testing.T) {
+	l := NewLearnings()
+	l.Improvements = []Improvement{
+		{Description: "tidy logs", Priority: "low"},
+		{Description: "add caching", Priority: "medium"},
+		{Description: "fix data loss", Priority: "critical"},
+		{Description: "speed up walk", Priority: "high"},
+	}
+
+	summary := l.Summary()
+
+	if !strings.Contains(summary, "1. [critical] fix data loss") {
+		t.Errorf("expected critical improvement first, got:\n%s", summary)
+	}
+	if !strings.Contains(summary, "2. [high] speed up walk") {
+		t.Errorf("expected high improvement second, got:\n%s", summary)
+	}
+	if !strings.Contains(summary, "3. [medium] add caching") {
+		t.Errorf("expected medium improvement third, got:\n%s", summary)
+	}
+	if strings.Contains(summary, "tidy logs") {
+		t.Errorf("expected only the top 3 improvements, got:\n%s", summary)
+	}
+}
+
+func TestSummaryContents(t *testing.T) {
+	l := NewLearnings()
+	l.Metadata.ToolName = "doc-gen"
+	l.WhatWorkedWell = []WorkedWell{{Description: "a"}, {Description: "b"}}
+	l.WhatFailed = []Failed{{Description: "c"}}
+	l.ExecutionMetrics.FilesProcessed = 42
+	l.Obsolescence = ObsolescenceIndicators{IsObsolete: true, ObsolescenceScore: 0.75}
+
+	summary := l.Summary()
+
+	for _, want := range []string{
+		"Tool: doc-gen",
+		"- Worked well: 2",
+		"- Failed: 1",
+		"- Edge cases: 0",
+		"- Verdict: OBSOLETE (score 0.75)",
+		"- Files processed: 42",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Summary() missing %q in:\n%s", want, summary)
+		}
+	}
+}
+
+func TestSummaryNoImprovements(t *testing.T) {
+	summary := NewLearnings().Summary()
+	if !strings.Contains(summary, "Top Improvements:\n- none") {
+		t.Errorf("expected placeholder for empty improvements, got:\n%s", summary)
+	}
+}
@@ -0,0 +1,176 @@
+package learnings
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// templateHeader opens the file written by WriteTemplate.
+const templateHeader = `learnings.yaml scaffold for a Phase 2 tool.
+Every section is present with an example entry; replace the examples with
+what your tool observes, and keep the keys so the next generation's prompt
+can read them.`
+
+// sectionComments describes each top-level section of a learnings file,
+// keyed by its YAML key.
+var sectionComments = map[string]string{
+	"metadata":                        "Which tool produced this file, for which codebase, and when.",
+	"execution_metrics":               "Measurements from the run.",
+	"what_worked_well":                "Approaches worth keeping. confidence: high, medium, or low.",
+	"what_failed":                     "Failures to fix. frequency: always, often, sometimes, or rare; impact: critical, major, or minor.",
+	"edge_cases_discovered":           "Inputs the tool handles badly. priority: high, medium, or low.",
+	"patterns_identified":             "Recurring patterns in the codebase the next generation can exploit.",
+	"improvements_needed":             "Planned changes. priority: critical, high, medium, or low; effort_estimate: small, medium, or large.",
+	"codebase_changes_detected":       "How the codebase changed since the previous run.",
+	"obsolescence_indicators":         "Whether the tool should be regenerated. obsolescence_score runs from 0.0 (fresh) to 1.0 (obsolete); recommendation: regenerate, update, or continue.",
+	"next_generation_recommendations": "Ideas for the next generation of the tool.",
+	"custom_notes":                    "Free-form notes.",
+	"security_signals":                "Counts of potential secrets from the latest --scan-secrets run; the secrets themselves are never recorded.",
+}
+
+// ExampleLearnings returns learnings with every section populated by an
+// illustrative entry, for use as a starting point.
+func ExampleLearnings() *Learnings {
+	return &Learnings{
+		Metadata: Metadata{
+			ToolName:            "update-docs",
+			ToolVersion:         "1.0.0",
+			Generation:          1,
+			RunDate:             time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			CodebaseName:        "my-app",
+			CodebasePath:        "/path/to/my-app",
+			CodebaseFingerprint: "sha256:0000000000000000",
+		},
+		ExecutionMetrics: ExecutionMetrics{
+			DurationSeconds:   12.5,
+			FilesProcessed:    340,
+			ErrorsEncountered: 1,
+			WarningsGenerated: 3,
+			ReportsGenerated:  4,
+			MemoryPeakMB:      48.2,
+		},
+		WhatWorkedWell: []WorkedWell{{
+			Category:    "parsing",
+			Description: "Go packages parsed without errors",
+			Confidence:  "high",
+			Examples:    []string{"internal/api"},
+		}},
+		WhatFailed: []Failed{{
+			Category:     "detection",
+			Description:  "Missed HTTP handlers registered through a router wrapper",
+			ErrorType:    "false_negative",
+			Frequency:    "often",
+			Impact:       "major",
+			Examples:     []string{"internal/server/routes.go"},
+			SuggestedFix: "Follow calls to the wrapper's Handle method",
+		}},
+		EdgeCases: []EdgeCase{{
+			CaseID:           "EC001",
+			Description:      "Generated protobuf code inflates Go file counts",
+			TriggerCondition: "*.pb.go files under api/",
+			CurrentBehavior:  "Counted as hand-written code",
+			DesiredBehavior:  "Reported separately as generated",
+			Workaround:       "Add api/ to skip_dirs",
+			Priority:         "medium",
+		}},
+		Patterns: []Pattern{{
+			PatternType:    "architecture",
+			PatternName:    "handler-service-store",
+			Description:    "Each feature has a handler, a service, and a store package",
+			Frequency:      6,
+			Locations:      []string{"internal/users", "internal/orders"},
+			Significance:   "Features can be documented from one template",
+			Recommendation: "Generate per-feature docs from the three layers",
+		}},
+		Improvements: []Improvement{{
+			ImprovementID:      "IMP001",
+			Category:           "performance",
+			Description:        "Cache parsed files between runs",
+			CurrentState:       "Every run parses all files",
+			DesiredState:       "Only changed files are parsed",
+			Priority:           "high",
+			EffortEstimate:     "medium",
+			ImplementationHint: "Key the cache by path and modification time",
+		}},
+		CodebaseChanges: CodebaseChanges{
+			StructuralChanges:   StructuralChanges{NewDirectories: []string{"internal/billing"}},
+			LanguageChanges:     LanguageChanges{NewLanguages: []string{"TypeScript"}, LanguageShift: "JavaScript → TypeScript"},
+			FrameworkChanges:    FrameworkChanges{VersionUpgrades: []string{"gin v1.8 → v1.9"}},
+			DependencyChanges:   DependencyChanges{NewDependencies: []string{"github.com/stripe/stripe-go"}},
+			ArchitectureChanges: ArchitectureChanges{NewServices: []string{"billing"}},
+		},
+		Obsolescence: ObsolescenceIndicators{
+			ObsolescenceScore: 0.2,
+			Reasons:           []string{"New billing service is not covered"},
+			Confidence:        "medium",
+			Recommendation:    "update",
+		},
+		NextGenRecommendations: NextGenerationRecommendations{
+			NewReportTypes:           []string{"API endpoint inventory"},
+			EnhancedDetections:       []string{"Router wrapper handlers"},
+			PerformanceOptimizations: []string{"Parse packages concurrently"},
+			UsabilityImprovements:    []string{"Link reports from an index page"},
+			CodeQualityImprovements:  []string{"Split the report writer by format"},
+		},
+		CustomNotes: []CustomNote{{
+			Note:     "The legacy/ directory is frozen; skip it in reviews",
+			Category: "scope",
+			Priority: "low",
+		}},
+		SecuritySignals: SecuritySignals{
+			PotentialSecrets: 1,
+			ByKind:           map[string]int{"AWS access key": 1},
+			ByRepository:     map[string]int{"my-app": 1},
+		},
+	}
+}
+
+// Template returns ExampleLearnings as commented YAML: a header explaining
+// the file, then each section preceded by a description of what it holds.
+func Template() ([]byte, error) {
+	var doc yaml.Node
+	if err := doc.Encode(ExampleLearnings()); err != nil {
+		return nil, fmt.Errorf("failed to encode learnings template: %w", err)
+	}
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		key := doc.Content[i]
+		// A blank line sets each section off from the one before it,
+		// and the header from the first.
+		prefix := "\n"
+		if i == 0 {
+			prefix = templateHeader + "\n\n"
+		}
+		key.HeadComment = prefix + sectionComments[key.Value]
+	}
+
+	data, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal learnings template: %w", err)
+	}
+	return data, nil
+}
+
+// WriteTemplate writes Template to path, creating its directory. It
+// refuses to overwrite an existing file.
+func WriteTemplate(path string) error {
+	data, err := Template()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create learnings template: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write learnings template: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,63 @@
+package learnings
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteTemplate_LoadsBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "phase2", "learnings.yaml")
+	if err := WriteTemplate(path); err != nil {
+		t.Fatalf("WriteTemplate() error = %v", err)
+	}
+
+	l, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(l, ExampleLearnings()) {
+		t.Errorf("Load() = %+v, want the example learnings", l)
+	}
+}
+
+func TestTemplate_CommentsEverySection(t *testing.T) {
+	data, err := Template()
+	if err != nil {
+		t.Fatalf("Template() error = %v", err)
+	}
+	out := string(data)
+	if !strings.HasPrefix(out, "# learnings.yaml scaffold") {
+		t.Errorf("Template() does not open with the header:\n%s", out)
+	}
+	for key, comment := range sectionComments {
+		if !strings.Contains(out, "# "+comment+"\n"+key+":") {
+			t.Errorf("section %s is missing or not preceded by its comment", key)
+		}
+	}
+}
+
+func TestExampleLearnings_PopulatesEverySection(t *testing.T) {
+	l := ExampleLearnings()
+	v := reflect.ValueOf(*l)
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).IsZero() {
+			t.Errorf("ExampleLearnings().%s is empty", v.Type().Field(i).Name)
+		}
+	}
+}
+
+func TestWriteTemplate_RefusesToOverwrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "learnings.yaml")
+	if err := os.WriteFile(path, []byte("kept"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteTemplate(path); err == nil {
+		t.Fatal("WriteTemplate() error = nil, want refusal to overwrite")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "kept" {
+		t.Errorf("existing file was modified: %q", data)
+	}
+}
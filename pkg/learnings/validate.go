@@ -0,0 +1,82 @@
+package learnings
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationConfig lists the values Validate accepts in priority and
+// confidence fields. An empty list selects the default vocabulary.
+type ValidationConfig struct {
+	// Priorities are the accepted values of edge case, improvement, and
+	// custom note priorities, such as "P0".."P3".
+	Priorities []string
+	// Confidences are the accepted values of what_worked_well and
+	// obsolescence_indicators confidence.
+	Confidences []string
+}
+
+// Default vocabularies, as documented in the learnings schema.
+var (
+	defaultPriorities  = []string{"critical", "high", "medium", "low"}
+	defaultConfidences = []string{"high", "medium", "low"}
+)
+
+// DefaultValidationConfig returns the vocabularies of the learnings
+// schema: critical, high, medium, or low priorities and high, medium, or
+// low confidence.
+func DefaultValidationConfig() ValidationConfig {
+	return ValidationConfig{
+		Priorities:  append([]string(nil), defaultPriorities...),
+		Confidences: append([]string(nil), defaultConfidences...),
+	}
+}
+
+// Validate checks every priority and confidence field against cfg and
+// returns one error per value outside the allowed vocabulary, naming the
+// field by its YAML path (for example "improvements_needed[1].priority").
+// Values are compared case-insensitively; empty values are not checked.
+// It returns nil when every value is allowed.
+func (l *Learnings) Validate(cfg ValidationConfig) []error {
+	priorities := cfg.Priorities
+	if len(priorities) == 0 {
+		priorities = defaultPriorities
+	}
+	confidences := cfg.Confidences
+	if len(confidences) == 0 {
+		confidences = defaultConfidences
+	}
+
+	var errs []error
+	check := func(path, value string, allowed []string) {
+		if value == "" || containsFold(allowed, strings.TrimSpace(value)) {
+			return
+		}
+		errs = append(errs, fmt.Errorf("%s: %q is not one of %s", path, value, strings.Join(allowed, ", ")))
+	}
+
+	for i, w := range l.WhatWorkedWell {
+		check(fmt.Sprintf("what_worked_well[%d].confidence", i), w.Confidence, confidences)
+	}
+	for i, e := range l.EdgeCases {
+		check(fmt.Sprintf("edge_cases_discovered[%d].priority", i), e.Priority, priorities)
+	}
+	for i, imp := range l.Improvements {
+		check(fmt.Sprintf("improvements_needed[%d].priority", i), imp.Priority, priorities)
+	}
+	check("obsolescence_indicators.confidence", l.Obsolescence.Confidence, confidences)
+	for i, n := range l.CustomNotes {
+		check(fmt.Sprintf("custom_notes[%d].priority", i), n.Priority, priorities)
+	}
+	return errs
+}
+
+// containsFold reports whether values holds s, ignoring case.
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
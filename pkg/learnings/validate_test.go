@@ -0,0 +1,82 @@
+package learnings
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	pScale := ValidationConfig{Priorities: []string{"P0", "P1", "P2", "P3"}}
+
+	tests := []struct {
+		name     string
+		learning *Learnings
+		cfg      ValidationConfig
+		want     []string
+	}{
+		{
+			name:     "example passes the default vocabulary",
+			learning: ExampleLearnings(),
+			cfg:      DefaultValidationConfig(),
+		},
+		{
+			name: "custom P0-P3 vocabulary passes",
+			learning: NewLearnings().
+				AddImprovement(Improvement{Priority: "P0"}).
+				AddEdgeCase(EdgeCase{Priority: "p2"}).
+				AddCustomNote(CustomNote{Priority: "P3"}).
+				AddWorkedWell(WorkedWell{Confidence: "high"}),
+			cfg: pScale,
+		},
+		{
+			name:     "default vocabulary rejects urgent",
+			learning: NewLearnings().AddImprovement(Improvement{Priority: "high"}).AddImprovement(Improvement{Priority: "urgent"}),
+			cfg:      ValidationConfig{},
+			want:     []string{`improvements_needed[1].priority: "urgent" is not one of critical, high, medium, low`},
+		},
+		{
+			name:     "custom vocabulary rejects the default scale",
+			learning: NewLearnings().AddCustomNote(CustomNote{Priority: "high"}),
+			cfg:      pScale,
+			want:     []string{`custom_notes[0].priority: "high" is not one of P0, P1, P2, P3`},
+		},
+		{
+			name: "confidence fields",
+			learning: &Learnings{
+				WhatWorkedWell: []WorkedWell{{Confidence: "certain"}},
+				Obsolescence:   ObsolescenceIndicators{Confidence: "maybe"},
+			},
+			cfg: ValidationConfig{Confidences: []string{"sure", "unsure"}},
+			want: []string{
+				`what_worked_well[0].confidence: "certain" is not one of sure, unsure`,
+				`obsolescence_indicators.confidence: "maybe" is not one of sure, unsure`,
+			},
+		},
+		{
+			name:     "empty values are not checked",
+			learning: NewLearnings().AddEdgeCase(EdgeCase{}).AddImprovement(Improvement{}),
+			cfg:      DefaultValidationConfig(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, err := range tt.learning.Validate(tt.cfg) {
+				got = append(got, err.Error())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultValidationConfig_IsACopy(t *testing.T) {
+	cfg := DefaultValidationConfig()
+	cfg.Priorities[0] = "P0"
+	if got := fmt.Sprint(DefaultValidationConfig().Priorities); got != "[critical high medium low]" {
+		t.Errorf("DefaultValidationConfig().Priorities = %s after modifying a copy", got)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"io"
 	"log"
 	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -12,8 +14,10 @@ import (
 type Level int
 
 const (
+	// LevelTrace for per-item detail, such as every file visited in a walk
+	LevelTrace Level = iota - 1
 	// LevelDebug for detailed debugging information
-	LevelDebug Level = iota
+	LevelDebug
 	// LevelInfo for general informational messages
 	LevelInfo
 	// LevelWarn for warning messages
@@ -22,23 +26,112 @@ const (
 	LevelError
 )
 
-// Logger provides structured logging
+// LevelEnvVar names the environment variable consulted by New to choose the
+// logging level. Accepted values are trace, debug, info, warn, and error.
+const LevelEnvVar = "CODEBASE_REVIEWER_LOG_LEVEL"
+
+// DefaultTimeFormat is the timestamp layout used unless overridden with SetTimeFormat.
+const DefaultTimeFormat = "2006-01-02 15:04:05"
+
+// ANSI escape sequences used to colorize level tags on terminals.
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+	colorGray   = "\033[90m"
+)
+
+// levelTags are the names written in brackets before each message.
+var levelTags = map[Level]string{
+	LevelTrace: "TRACE",
+	LevelDebug: "DEBUG",
+	LevelInfo:  "INFO",
+	LevelWarn:  "WARN",
+	LevelError: "ERROR",
+}
+
+// levelColors maps level tags to their terminal color. Levels without an
+// entry are written uncolored.
+var levelColors = map[string]string{
+	"TRACE": colorGray,
+	"DEBUG": colorGray,
+	"WARN":  colorYellow,
+	"ERROR": colorRed,
+}
+
+// FatalFn is called by Logger.Fatal after the message is written. It defaults
+// to os.Exit and may be replaced in tests to intercept the exit.
+var FatalFn = os.Exit
+
+// Logger provides structured logging. It is safe for concurrent use:
+// configuration and output are guarded by a mutex so lines written from
+// multiple goroutines are never interleaved.
 type Logger struct {
-	level  Level
-	logger *log.Logger
+	mu         sync.Mutex
+	level      Level
+	logger     *log.Logger
+	timeFormat string
+	now        func() time.Time
+	color      bool
+
+	// dedupWindow, when positive, collapses identical consecutive lines;
+	// last tracks the line being collapsed.
+	dedupWindow time.Duration
+	last        repeatedLine
+
+	// onLog, if set, observes every line written.
+	onLog func(level Level, msg string)
 }
 
-// New creates a new logger
+// repeatedLine is the most recent line emitted in dedup mode and how many
+// identical lines have been suppressed since.
+type repeatedLine struct {
+	level   Level
+	message string
+	since   time.Time
+	repeats int
+}
+
+// New creates a new logger writing to stdout. The level defaults to info,
+// may be overridden by the CODEBASE_REVIEWER_LOG_LEVEL environment variable,
+// and is always debug when verbose is set.
 func New(verbose bool) *Logger {
-	level := LevelInfo
-	if verbose {
-		level = LevelDebug
+	l := NewWithWriter(os.Stdout, verbose)
+	l.applyEnvLevel(os.Getenv(LevelEnvVar), verbose)
+	return l
+}
+
+// ParseLevel converts a level name (trace, debug, info, warn, error) to a
+// Level. Matching is case-insensitive.
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "trace":
+		return LevelTrace, nil
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
 	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", name)
+}
 
-	return &Logger{
-		level:  level,
-		logger: log.New(os.Stdout, "", 0),
+// applyEnvLevel sets the level from an environment value. Empty values are
+// ignored, verbose always wins, and invalid values keep the current level
+// and emit a single warning.
+func (l *Logger) applyEnvLevel(value string, verbose bool) {
+	if value == "" || verbose {
+		return
+	}
+	level, err := ParseLevel(value)
+	if err != nil {
+		l.Warn("Ignoring %s: %v", LevelEnvVar, err)
+		return
 	}
+	l.SetLevel(level)
 }
 
 // NewWithWriter creates a logger with a custom writer
@@ -49,46 +142,194 @@ func NewWithWriter(w io.Writer, verbose bool) *Logger {
 	}
 
 	return &Logger{
-		level:  level,
-		logger: log.New(w, "", 0),
+		level:      level,
+		logger:     log.New(w, "", 0),
+		timeFormat: DefaultTimeFormat,
+		now:        time.Now,
+		color:      isTerminal(w),
+	}
+}
+
+// NewTee creates a logger that writes every line to each of writers, such
+// as stdout and a log file. The level is chosen as in New. Output is never
+// colorized, so files do not collect escape sequences.
+func NewTee(verbose bool, writers ...io.Writer) *Logger {
+	l := NewWithWriter(io.MultiWriter(writers...), verbose)
+	l.applyEnvLevel(os.Getenv(LevelEnvVar), verbose)
+	return l
+}
+
+// isTerminal reports whether w is a character device such as a TTY.
+// Files, pipes, and in-memory buffers are never treated as terminals.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Trace logs a trace message
+func (l *Logger) Trace(format string, args ...interface{}) {
+	if l.enabled(LevelTrace) {
+		l.log(LevelTrace, format, args...)
 	}
 }
 
 // Debug logs a debug message
 func (l *Logger) Debug(format string, args ...interface{}) {
-	if l.level <= LevelDebug {
-		l.log("DEBUG", format, args...)
+	if l.enabled(LevelDebug) {
+		l.log(LevelDebug, format, args...)
 	}
 }
 
 // Info logs an info message
 func (l *Logger) Info(format string, args ...interface{}) {
-	if l.level <= LevelInfo {
-		l.log("INFO", format, args...)
+	if l.enabled(LevelInfo) {
+		l.log(LevelInfo, format, args...)
 	}
 }
 
 // Warn logs a warning message
 func (l *Logger) Warn(format string, args ...interface{}) {
-	if l.level <= LevelWarn {
-		l.log("WARN", format, args...)
+	if l.enabled(LevelWarn) {
+		l.log(LevelWarn, format, args...)
 	}
 }
 
 // Error logs an error message
 func (l *Logger) Error(format string, args ...interface{}) {
-	if l.level <= LevelError {
-		l.log("ERROR", format, args...)
+	if l.enabled(LevelError) {
+		l.log(LevelError, format, args...)
 	}
 }
 
-func (l *Logger) log(level, format string, args ...interface{}) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
+// enabled reports whether messages at the given level should be emitted.
+func (l *Logger) enabled(level Level) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.level <= level
+}
+
+// Fatal logs an error message and terminates the program via FatalFn with exit code 1.
+// The message is always written, regardless of the configured level.
+func (l *Logger) Fatal(format string, args ...interface{}) {
+	l.log(LevelError, format, args...)
+	l.Flush()
+	FatalFn(1)
+}
+
+// Dedup collapses identical consecutive lines (same level and message)
+// logged within window of the first one. The first line is written as
+// usual; the suppressed copies are reported by a single "(repeated N times)"
+// line when a different line is logged, the window closes, or Flush is
+// called. A zero window turns dedup off, which is the default.
+func (l *Logger) Dedup(window time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.flushRepeatsLocked()
+	l.dedupWindow = window
+	l.last = repeatedLine{}
+}
+
+// OnLog registers fn to be called with the level and message of every line
+// the logger writes, such as to count warnings and errors. Lines suppressed
+// by the level or by Dedup do not reach fn. fn runs while the logger is
+// locked, so it must not log through the same Logger. A nil fn removes the
+// hook.
+func (l *Logger) OnLog(fn func(level Level, msg string)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onLog = fn
+}
+
+// Flush writes any pending "(repeated N times)" summary from Dedup mode.
+func (l *Logger) Flush() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.flushRepeatsLocked()
+}
+
+func (l *Logger) log(level Level, format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
-	l.logger.Printf("[%s] [%s] %s", timestamp, level, message)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.dedupWindow > 0 {
+		now := l.now()
+		if level == l.last.level && message == l.last.message && now.Sub(l.last.since) < l.dedupWindow {
+			l.last.repeats++
+			return
+		}
+		l.flushRepeatsLocked()
+		l.last = repeatedLine{level: level, message: message, since: now}
+	}
+	l.writeLocked(level, message)
+}
+
+// flushRepeatsLocked writes the summary for suppressed repeats, if any.
+// The caller must hold l.mu.
+func (l *Logger) flushRepeatsLocked() {
+	if l.last.repeats == 0 {
+		return
+	}
+	l.writeLocked(l.last.level, fmt.Sprintf("%s (repeated %d times)", l.last.message, l.last.repeats))
+	l.last.repeats = 0
+}
+
+// writeLocked formats and writes one line, then passes it to the OnLog
+// hook. The caller must hold l.mu.
+func (l *Logger) writeLocked(level Level, message string) {
+	if l.onLog != nil {
+		defer l.onLog(level, message)
+	}
+	name := levelTags[level]
+	tag := "[" + name + "]"
+	if color, ok := levelColors[name]; ok && l.color {
+		tag = color + tag + colorReset
+	}
+	if l.timeFormat == "" {
+		l.logger.Printf("%s %s", tag, message)
+		return
+	}
+	timestamp := l.now().Format(l.timeFormat)
+	l.logger.Printf("[%s] %s %s", timestamp, tag, message)
 }
 
 // SetLevel sets the logging level
 func (l *Logger) SetLevel(level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.level = level
 }
+
+// SetTimeFormat sets the timestamp layout (see time.Layout). An empty layout
+// omits the timestamp entirely, which is useful for golden-file tests.
+func (l *Logger) SetTimeFormat(layout string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.timeFormat = layout
+}
+
+// SetClock overrides the time source used for timestamps. A nil clock
+// restores time.Now.
+func (l *Logger) SetClock(now func() time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now == nil {
+		now = time.Now
+	}
+	l.now = now
+}
+
+// SetColor forces ANSI coloring of level tags on or off, overriding the
+// automatic terminal detection performed at construction.
+func (l *Logger) SetColor(enabled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.color = enabled
+}
@@ -2,8 +2,14 @@ package logger
 
 import (
 	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestNew(t *testing.T) {
@@ -47,10 +53,15 @@ func TestLogLevels(t *testing.T) {
 		logMethod  string
 		shouldLog  bool
 	}{
+		{"trace at trace level", LevelTrace, "trace", true},
+		{"debug at trace level", LevelTrace, "debug", true},
+		{"info at trace level", LevelTrace, "info", true},
+		{"trace at debug level", LevelDebug, "trace", false},
 		{"debug at debug level", LevelDebug, "debug", true},
 		{"info at debug level", LevelDebug, "info", true},
 		{"warn at debug level", LevelDebug, "warn", true},
 		{"error at debug level", LevelDebug, "error", true},
+		{"trace at info level", LevelInfo, "trace", false},
 		{"debug at info level", LevelInfo, "debug", false},
 		{"info at info level", LevelInfo, "info", true},
 		{"warn at info level", LevelInfo, "warn", true},
@@ -72,6 +83,8 @@ func TestLogLevels(t *testing.T) {
 			log.SetLevel(tt.logLevel)
 
 			switch tt.logMethod {
+			case "trace":
+				log.Trace("test")
 			case "debug":
 				log.Debug("test")
 			case "info":
@@ -132,3 +145,341 @@ func TestLogTimestamp(t *testing.T) {
 		t.Errorf("expected timestamp in output, got %q", output)
 	}
 }
+
+func TestConcurrentLogging(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithWriter(&buf, true)
+
+	const goroutines = 50
+	const perGoroutine = 100
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				if i%2 == 0 {
+					log.Info("worker %d message %d", id, i)
+				} else {
+					log.Warn("worker %d message %d", id, i)
+				}
+				if i == perGoroutine/2 {
+					log.SetLevel(LevelDebug)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != goroutines*perGoroutine {
+		t.Fatalf("got %d lines, want %d", len(lines), goroutines*perGoroutine)
+	}
+
+	linePattern := regexp.MustCompile(`^\[\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\] \[(INFO|WARN)\] worker \d+ message \d+$`)
+	seen := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		if !linePattern.MatchString(line) {
+			t.Fatalf("torn or malformed line: %q", line)
+		}
+		seen[line[strings.Index(line, "worker"):]] = true
+	}
+
+	for g := 0; g < goroutines; g++ {
+		for i := 0; i < perGoroutine; i++ {
+			if msg := fmt.Sprintf("worker %d message %d", g, i); !seen[msg] {
+				t.Errorf("missing line %q", msg)
+			}
+		}
+	}
+}
+
+func TestFatal(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithWriter(&buf, false)
+	log.SetLevel(LevelError + 1)
+
+	var gotCode int
+	called := false
+	origFatalFn := FatalFn
+	FatalFn = func(code int) {
+		called = true
+		gotCode = code
+	}
+	defer func() { FatalFn = origFatalFn }()
+
+	log.Fatal("cannot continue: %s", "boom")
+
+	if !called {
+		t.Fatal("expected FatalFn to be called")
+	}
+	if gotCode != 1 {
+		t.Errorf("exit code = %d, want 1", gotCode)
+	}
+	output := buf.String()
+	if !strings.Contains(output, "[ERROR] cannot continue: boom") {
+		t.Errorf("expected fatal message in output, got %q", output)
+	}
+}
+
+func TestSetTimeFormat(t *testing.T) {
+	fixed := time.Date(2024, 3, 15, 9, 30, 0, 0, time.FixedZone("PST", -8*60*60))
+
+	tests := []struct {
+		name   string
+		layout string
+		want   string
+	}{
+		{"default layout", DefaultTimeFormat, "[2024-03-15 09:30:00] [INFO] hello\n"},
+		{"RFC3339", time.RFC3339, "[2024-03-15T09:30:00-08:00] [INFO] hello\n"},
+		{"omit timestamp", "", "[INFO] hello\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := NewWithWriter(&buf, false)
+			log.SetClock(func() time.Time { return fixed })
+			log.SetTimeFormat(tt.layout)
+
+			log.Info("hello")
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetColor(t *testing.T) {
+	tests := []struct {
+		name      string
+		color     bool
+		log       func(l *Logger)
+		wantCodes bool
+	}{
+		{"warn with color", true, func(l *Logger) { l.Warn("careful") }, true},
+		{"error with color", true, func(l *Logger) { l.Error("broken") }, true},
+		{"debug with color", true, func(l *Logger) { l.Debug("detail") }, true},
+		{"info with color is plain", true, func(l *Logger) { l.Info("note") }, false},
+		{"warn without color", false, func(l *Logger) { l.Warn("careful") }, false},
+		{"error without color", false, func(l *Logger) { l.Error("broken") }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := NewWithWriter(&buf, true)
+			log.SetColor(tt.color)
+
+			tt.log(log)
+
+			hasCodes := strings.Contains(buf.String(), "\033[")
+			if hasCodes != tt.wantCodes {
+				t.Errorf("escape codes present = %v, want %v (output %q)", hasCodes, tt.wantCodes, buf.String())
+			}
+		})
+	}
+}
+
+func TestColorDisabledForNonTerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	log := NewWithWriter(f, false)
+	log.Error("written to a file")
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "\033[") {
+		t.Errorf("expected no escape codes in file output, got %q", data)
+	}
+}
+
+func TestNewLevelFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		verbose bool
+		want    Level
+	}{
+		{"trace", "trace", false, LevelTrace},
+		{"debug", "debug", false, LevelDebug},
+		{"info", "info", false, LevelInfo},
+		{"warn", "warn", false, LevelWarn},
+		{"error", "error", false, LevelError},
+		{"case insensitive", "WARN", false, LevelWarn},
+		{"verbose forces debug", "error", true, LevelDebug},
+		{"invalid falls back to default", "loud", false, LevelInfo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(LevelEnvVar, tt.env)
+			log := New(tt.verbose)
+			if log.level != tt.want {
+				t.Errorf("New(%v) with %s=%q level = %v, want %v", tt.verbose, LevelEnvVar, tt.env, log.level, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyEnvLevel_InvalidWarnsOnce(t *testing.T) {
+	t.Setenv(LevelEnvVar, "loud")
+
+	var buf bytes.Buffer
+	log := NewWithWriter(&buf, false)
+	log.applyEnvLevel(os.Getenv(LevelEnvVar), false)
+
+	if log.level != LevelInfo {
+		t.Errorf("level = %v, want %v", log.level, LevelInfo)
+	}
+	if got := strings.Count(buf.String(), "[WARN]"); got != 1 {
+		t.Errorf("expected exactly one warning, got %d in %q", got, buf.String())
+	}
+	if !strings.Contains(buf.String(), LevelEnvVar) {
+		t.Errorf("expected warning to name %s, got %q", LevelEnvVar, buf.String())
+	}
+}
+
+func TestNewTee(t *testing.T) {
+	t.Setenv(LevelEnvVar, "")
+	var buf bytes.Buffer
+	path := filepath.Join(t.TempDir(), "run.log")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	log := NewTee(false, &buf, f)
+	log.Info("scan started")
+	log.Debug("hidden at info level")
+
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != string(fileData) {
+		t.Errorf("tee outputs differ:\nbuffer: %q\nfile:   %q", buf.String(), fileData)
+	}
+	if !strings.Contains(buf.String(), "[INFO] scan started") {
+		t.Errorf("expected the info line in both outputs, got %q", buf.String())
+	}
+	if strings.Contains(buf.String(), "hidden") {
+		t.Errorf("debug line should be suppressed, got %q", buf.String())
+	}
+}
+
+func TestDedup(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithWriter(&buf, false)
+	log.SetTimeFormat("")
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	log.SetClock(func() time.Time { return clock })
+	log.Dedup(time.Minute)
+
+	for i := 0; i < 50; i++ {
+		log.Warn("Error accessing path %s: permission denied", "/mnt/bad")
+	}
+	log.Info("Scan complete")
+
+	want := "[WARN] Error accessing path /mnt/bad: permission denied\n" +
+		"[WARN] Error accessing path /mnt/bad: permission denied (repeated 49 times)\n" +
+		"[INFO] Scan complete\n"
+	if buf.String() != want {
+		t.Errorf("dedup output =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestDedup_WindowAndFlush(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithWriter(&buf, false)
+	log.SetTimeFormat("")
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	log.SetClock(func() time.Time { return clock })
+	log.Dedup(10 * time.Second)
+
+	log.Warn("flaky")
+	log.Warn("flaky")
+	clock = clock.Add(11 * time.Second)
+	log.Warn("flaky")
+	log.Warn("flaky")
+	log.Warn("flaky")
+	log.Flush()
+
+	want := "[WARN] flaky\n" +
+		"[WARN] flaky (repeated 1 times)\n" +
+		"[WARN] flaky\n" +
+		"[WARN] flaky (repeated 2 times)\n"
+	if buf.String() != want {
+		t.Errorf("dedup output =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestDedup_OffByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithWriter(&buf, false)
+	for i := 0; i < 3; i++ {
+		log.Warn("same")
+	}
+	log.Flush()
+	if got := strings.Count(buf.String(), "[WARN] same\n"); got != 3 {
+		t.Errorf("expected 3 uncollapsed lines without Dedup, got %d:\n%s", got, buf.String())
+	}
+}
+
+func TestOnLog(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithWriter(&buf, false)
+
+	var got []string
+	log.OnLog(func(level Level, msg string) {
+		got = append(got, fmt.Sprintf("%d:%s", level, msg))
+	})
+
+	log.Trace("suppressed trace")
+	log.Debug("suppressed debug")
+	log.Info("started")
+	log.Warn("slow disk")
+	log.Error("failed %d", 2)
+
+	want := []string{
+		fmt.Sprintf("%d:started", LevelInfo),
+		fmt.Sprintf("%d:slow disk", LevelWarn),
+		fmt.Sprintf("%d:failed 2", LevelError),
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("hook calls = %v, want %v", got, want)
+	}
+
+	log.OnLog(nil)
+	log.Warn("unobserved")
+	if len(got) != len(want) {
+		t.Errorf("hook still called after removal: %v", got)
+	}
+}
+
+func TestOnLog_Dedup(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithWriter(&buf, false)
+	log.Dedup(time.Minute)
+
+	calls := 0
+	log.OnLog(func(Level, string) { calls++ })
+	for i := 0; i < 10; i++ {
+		log.Warn("same")
+	}
+	log.Flush()
+
+	lines := strings.Count(buf.String(), "\n")
+	if calls != lines || calls != 2 {
+		t.Errorf("hook calls = %d, written lines = %d, want 2 of each", calls, lines)
+	}
+}
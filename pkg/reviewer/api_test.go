@@ -0,0 +1,87 @@
+package reviewer_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/pkg/reviewer"
+)
+
+// TestPublicAPI drives a review the way an outside caller must, through
+// the names exported by this package alone.
+func TestPublicAPI(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := reviewer.ParseProvider("claude")
+	if err != nil {
+		t.Fatalf("ParseProvider() error = %v", err)
+	}
+	formats, err := reviewer.ParseFormats("md,json")
+	if err != nil {
+		t.Fatalf("ParseFormats() error = %v", err)
+	}
+	report, err := reviewer.ParseReport("json")
+	if err != nil {
+		t.Fatalf("ParseReport() error = %v", err)
+	}
+
+	opts := reviewer.Options{Target: root, OutputBase: t.TempDir()}
+	opts.Provider, opts.Formats, opts.Report = provider, formats, report
+	opts.Scan = reviewer.ScanOptions{OnlyLanguages: []string{"Go"}}
+	res, err := reviewer.Run(opts)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(res.Analyses) != 1 {
+		t.Fatalf("got %d analyses, want 1", len(res.Analyses))
+	}
+	if got := res.Analyses[0].Languages["Go"]; got != 1 {
+		t.Errorf("Languages[Go] = %d, want 1", got)
+	}
+	data, err := os.ReadFile(res.PromptPath)
+	if err != nil {
+		t.Fatalf("prompt not written: %v", err)
+	}
+	if !strings.Contains(string(data), "<context>") {
+		t.Error("prompt is not formatted for the claude provider")
+	}
+	for _, name := range []string{"phase1-llm-prompt.json", "scan-report.json"} {
+		if _, err := os.Stat(filepath.Join(res.OutputDir, name)); err != nil {
+			t.Errorf("%s not written: %v", name, err)
+		}
+	}
+}
+
+func TestPublicAPI_Errors(t *testing.T) {
+	if _, err := reviewer.ParseProvider("unknown"); err == nil {
+		t.Error("ParseProvider() accepted an unknown provider")
+	}
+	if _, err := reviewer.ParseFormats("pdf"); err == nil {
+		t.Error("ParseFormats() accepted an unknown format")
+	}
+	if _, err := reviewer.ParseReport("xml"); err == nil {
+		t.Error("ParseReport() accepted an unknown report")
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing")
+	if _, err := reviewer.Run(reviewer.Options{Target: missing}); !errors.Is(err, reviewer.ErrPathNotFound) {
+		t.Errorf("Run() on a missing target error = %v, want ErrPathNotFound", err)
+	}
+
+	opts := reviewer.Options{
+		Target:     t.TempDir(),
+		OutputBase: t.TempDir(),
+		Filter: func([]reviewer.Repository) ([]reviewer.Repository, error) {
+			return nil, nil
+		},
+	}
+	if _, err := reviewer.Run(opts); !errors.Is(err, reviewer.ErrNoRepos) {
+		t.Errorf("Run() with everything filtered out error = %v, want ErrNoRepos", err)
+	}
+}
@@ -0,0 +1,170 @@
+package reviewer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// DefaultOutputBase is where outputs are written when Options.OutputBase
+// is empty: a codebase-reviewer directory in the system temp directory, such
+// as /tmp on Linux or %TEMP% on Windows.
+var DefaultOutputBase = filepath.Join(os.TempDir(), "codebase-reviewer")
+
+// outputHashLen is the number of hex digits of the target path's hash in
+// an output directory name.
+const outputHashLen = 8
+
+// PrepareOutputDir creates and returns the output directory path.
+// Outputs go to <baseDir>/<dirName>, where dirName comes from
+// OutputDirName; an empty baseDir selects DefaultOutputBase. In dry-run
+// mode the path is computed and the planned removal and creation are
+// logged, but the filesystem is not modified.
+func PrepareOutputDir(dirName, baseDir string, scorch, dryRun bool, log *logger.Logger) (string, error) {
+	if baseDir == "" {
+		baseDir = DefaultOutputBase
+	} else if dryRun {
+		if !filepath.IsAbs(baseDir) {
+			return "", fmt.Errorf("output base must be an absolute path: %s", baseDir)
+		}
+	} else if err := validateOutputBase(baseDir); err != nil {
+		return "", err
+	}
+
+	outputDir := filepath.Join(baseDir, dirName)
+
+	if scorch {
+		if err := checkScorchTarget(baseDir, outputDir); err != nil {
+			log.Error("Refusing to remove output directory: %v", err)
+			return "", fmt.Errorf("scorch aborted: %w", err)
+		}
+		if _, err := os.Stat(outputDir); err == nil {
+			if dryRun {
+				log.Info("Dry run: would remove existing output directory %s", outputDir)
+			} else {
+				log.Info("Scorch mode: removing existing output directory")
+				if err := os.RemoveAll(outputDir); err != nil {
+					log.Warn("Failed to remove existing output: %v", err)
+				}
+			}
+		}
+	}
+
+	if dryRun {
+		log.Info("Dry run: would create output directory %s", outputDir)
+		return outputDir, nil
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	return outputDir, nil
+}
+
+// OutputDirName names the output directory for targetPath: the codebase
+// name followed by a short hash of the cleaned path, such as
+// org-repo-1a2b3c4d, so targets that share a name do not overwrite each
+// other's outputs. The name is taken from the remote of the repository at
+// targetPath, when there is one among repos, and is otherwise the base
+// name of targetPath, which is expected to be absolute. A target with no
+// base name and no remote, such as the filesystem root, yields "" so that
+// checkScorchTarget refuses it.
+func OutputDirName(targetPath string, repos []scanner.Repository) string {
+	clean := filepath.Clean(targetPath)
+	name := remoteDirName(clean, repos)
+	if name == "" {
+		name = filepath.Base(clean)
+		if name == "." || name == ".." || name == string(filepath.Separator) {
+			return ""
+		}
+	}
+	sum := sha256.Sum256([]byte(clean))
+	return name + "-" + hex.EncodeToString(sum[:])[:outputHashLen]
+}
+
+// maxDirNameLen caps the length of a remote-derived directory name.
+const maxDirNameLen = 64
+
+// remoteDirName returns the name derived from the remote of the repository
+// at root (see scanner.RemoteName), made safe as a single path segment, or
+// "" when that repository is absent or has no usable remote.
+func remoteDirName(root string, repos []scanner.Repository) string {
+	for _, repo := range repos {
+		if filepath.Clean(repo.Path) == root && repo.RemoteURL != "" {
+			return sanitizeDirName(scanner.RemoteName(repo.RemoteURL))
+		}
+	}
+	return ""
+}
+
+// sanitizeDirName makes name safe as a directory name on any platform:
+// runs of characters other than ASCII letters, digits, '.', '_', and '-'
+// become a single '-', leading and trailing dots and dashes are trimmed,
+// and the result is capped at maxDirNameLen bytes.
+func sanitizeDirName(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range name {
+		ok := r == '.' || r == '_' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if !ok {
+			if !dash {
+				b.WriteByte('-')
+				dash = true
+			}
+			continue
+		}
+		b.WriteRune(r)
+		dash = false
+	}
+	s := b.String()
+	if len(s) > maxDirNameLen {
+		s = s[:maxDirNameLen]
+	}
+	return strings.Trim(s, ".-")
+}
+
+// checkScorchTarget guards the scorch removal of outputDir: it must be a
+// named directory directly under baseDir, never baseDir itself or a path
+// outside it, whatever codebase name produced it.
+func checkScorchTarget(baseDir, outputDir string) error {
+	rel, err := filepath.Rel(filepath.Clean(baseDir), filepath.Clean(outputDir))
+	if err != nil {
+		return fmt.Errorf("%s is not under output base %s: %w", outputDir, baseDir, err)
+	}
+	if rel == "." || rel == ".." || strings.ContainsRune(rel, filepath.Separator) || filepath.IsAbs(rel) {
+		return fmt.Errorf("%s is not a codebase directory directly under output base %s", outputDir, baseDir)
+	}
+	return nil
+}
+
+// validateOutputBase checks that a caller-supplied output base
+// directory is an absolute path that can be created and written to.
+func validateOutputBase(baseDir string) error {
+	if !filepath.IsAbs(baseDir) {
+		return fmt.Errorf("output base must be an absolute path: %s", baseDir)
+	}
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return fmt.Errorf("output base %s cannot be created: %w", baseDir, err)
+	}
+	probe, err := os.CreateTemp(baseDir, ".write-check-*")
+	if err != nil {
+		return fmt.Errorf("output base %s is not writable: %w", baseDir, err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// ToolsExist reports whether outputDir, as returned by PrepareOutputDir,
+// already holds Phase 2 tools.
+func ToolsExist(outputDir string) bool {
+	toolsDir := filepath.Join(outputDir, "phase2-tools")
+	_, err := os.Stat(toolsDir)
+	return err == nil
+}
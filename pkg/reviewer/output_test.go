@@ -0,0 +1,251 @@
+package reviewer
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+func TestDetermineOutputDir_CustomBase(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "workspace")
+
+	got, err := PrepareOutputDir(OutputDirName("/src/my-app", nil), base, false, false, logger.New(false))
+	if err != nil {
+		t.Fatalf("PrepareOutputDir() error = %v", err)
+	}
+
+	want := filepath.Join(base, OutputDirName("/src/my-app", nil))
+	if got != want {
+		t.Errorf("PrepareOutputDir() = %q, want %q", got, want)
+	}
+	if info, err := os.Stat(got); err != nil || !info.IsDir() {
+		t.Errorf("output directory was not created: %v", err)
+	}
+}
+
+func TestDetermineOutputDir_ScorchCustomBase(t *testing.T) {
+	base := t.TempDir()
+	stale := filepath.Join(base, OutputDirName("/src/my-app", nil), "stale.txt")
+	if err := os.MkdirAll(filepath.Dir(stale), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := PrepareOutputDir(OutputDirName("/src/my-app", nil), base, true, false, logger.New(false)); err != nil {
+		t.Fatalf("PrepareOutputDir() error = %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("scorch should remove existing outputs under the custom base")
+	}
+}
+
+func TestValidateOutputBase(t *testing.T) {
+	readOnly := filepath.Join(t.TempDir(), "readonly")
+	if err := os.Mkdir(readOnly, 0555); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		base    string
+		wantErr string
+		skip    bool
+	}{
+		{name: "relative path", base: "out", wantErr: "absolute"},
+		{name: "not writable", base: readOnly, wantErr: "not writable", skip: os.Geteuid() == 0},
+		{name: "writable", base: t.TempDir()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.skip {
+				t.Skip("permission checks are not enforced for this user")
+			}
+			err := validateOutputBase(tt.base)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateOutputBase() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateOutputBase() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDetermineOutputDir_DryRun(t *testing.T) {
+	base := t.TempDir()
+	existing := filepath.Join(base, OutputDirName("/src/my-app", nil), "keep.txt")
+	if err := os.MkdirAll(filepath.Dir(existing), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(existing, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("scorch does not remove", func(t *testing.T) {
+		if _, err := PrepareOutputDir(OutputDirName("/src/my-app", nil), base, true, true, logger.New(false)); err != nil {
+			t.Fatalf("PrepareOutputDir() error = %v", err)
+		}
+		if _, err := os.Stat(existing); err != nil {
+			t.Errorf("dry run should not remove existing outputs: %v", err)
+		}
+	})
+
+	t.Run("does not create", func(t *testing.T) {
+		newBase := filepath.Join(t.TempDir(), "fresh")
+		got, err := PrepareOutputDir(OutputDirName("/src/other-app", nil), newBase, false, true, logger.New(false))
+		if err != nil {
+			t.Fatalf("PrepareOutputDir() error = %v", err)
+		}
+		if got != filepath.Join(newBase, OutputDirName("/src/other-app", nil)) {
+			t.Errorf("PrepareOutputDir() = %q", got)
+		}
+		if _, err := os.Stat(newBase); !os.IsNotExist(err) {
+			t.Error("dry run should not create the output base")
+		}
+	})
+}
+
+func TestOutputDirName(t *testing.T) {
+	withRemote := func(path, remote string) []scanner.Repository {
+		return []scanner.Repository{{Path: "/a/app/lib", RemoteURL: "https://github.com/org/lib.git"}, {Path: path, RemoteURL: remote}}
+	}
+	tests := []struct {
+		name   string
+		target string
+		repos  []scanner.Repository
+		want   string
+	}{
+		{"base name and hash", "/a/app", nil, `^app-[0-9a-f]{8}$`},
+		{"trailing slash ignored", "/a/app/", nil, `^app-[0-9a-f]{8}$`},
+		{"root has no name", "/", nil, `^$`},
+		{"empty has no name", "", nil, `^$`},
+		{"remote-derived name", "/tmp/clone123", withRemote("/tmp/clone123", "git@github.com:acme/billing.git"), `^acme-billing-[0-9a-f]{8}$`},
+		{"remote name sanitized", "/tmp/clone123", withRemote("/tmp/clone123", "https://host/Team Space/proj:v2"), `^Team-Space-proj-v2-[0-9a-f]{8}$`},
+		{"root repository without remote", "/a/app", withRemote("/a/app", ""), `^app-[0-9a-f]{8}$`},
+		{"remote of a nested repository ignored", "/a/app", withRemote("/a/other", "https://github.com/org/other"), `^app-[0-9a-f]{8}$`},
+		{"unusable remote falls back", "/a/app", withRemote("/a/app", "https://github.com/"), `^app-[0-9a-f]{8}$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OutputDirName(tt.target, tt.repos); !regexp.MustCompile(tt.want).MatchString(got) {
+				t.Errorf("OutputDirName(%q) = %q, want match for %s", tt.target, got, tt.want)
+			}
+		})
+	}
+
+	if OutputDirName("/a/app", nil) != OutputDirName("/a/app/", nil) {
+		t.Error("OutputDirName() differs for equivalent paths")
+	}
+}
+
+func TestSanitizeDirName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"org/repo", "org-repo"},
+		{"org//weird  name", "org-weird-name"},
+		{"../..", ""},
+		{".hidden/repo.", "hidden-repo"},
+		{"café/ünï", "caf-n"},
+		{strings.Repeat("a", 80), strings.Repeat("a", maxDirNameLen)},
+	}
+	for _, tt := range tests {
+		if got := sanitizeDirName(tt.name); got != tt.want {
+			t.Errorf("sanitizeDirName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDetermineOutputDir_SameBaseNameDifferentPaths(t *testing.T) {
+	base := t.TempDir()
+	log := logger.New(false)
+
+	first, err := PrepareOutputDir(OutputDirName("/a/app", nil), base, false, false, log)
+	if err != nil {
+		t.Fatalf("PrepareOutputDir(/a/app) error = %v", err)
+	}
+	second, err := PrepareOutputDir(OutputDirName("/b/app", nil), base, false, false, log)
+	if err != nil {
+		t.Fatalf("PrepareOutputDir(/b/app) error = %v", err)
+	}
+	if first == second {
+		t.Errorf("/a/app and /b/app share output directory %s", first)
+	}
+
+	again, err := PrepareOutputDir(OutputDirName("/a/app", nil), base, false, false, log)
+	if err != nil {
+		t.Fatalf("PrepareOutputDir(/a/app) error = %v", err)
+	}
+	if again != first {
+		t.Errorf("PrepareOutputDir(/a/app) = %s, then %s; want stable", first, again)
+	}
+}
+
+func TestDetermineOutputDir_ScorchRefusesUnsafeTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		dirName string
+	}{
+		{"empty codebase name", ""},
+		{"filesystem root", "/"},
+		{"traversal out of the base", "/src/.."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := t.TempDir()
+			base := filepath.Join(parent, "outputs")
+			keep := filepath.Join(base, "other-app", "keep.txt")
+			if err := os.MkdirAll(filepath.Dir(keep), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(keep, []byte("keep"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := PrepareOutputDir(tt.dirName, base, true, false, logger.New(false)); err == nil {
+				t.Fatal("PrepareOutputDir() error = nil, want refusal")
+			}
+			if _, err := os.Stat(keep); err != nil {
+				t.Errorf("scorch removed data outside the codebase directory: %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckScorchTarget(t *testing.T) {
+	base := filepath.Join("/tmp", "codebase-reviewer")
+	tests := []struct {
+		name    string
+		output  string
+		wantErr bool
+	}{
+		{"codebase directory", filepath.Join(base, "my-app"), false},
+		{"base itself", base, true},
+		{"base with trailing slash", base + "/", true},
+		{"parent of base", filepath.Dir(base), true},
+		{"sibling of base", filepath.Join("/tmp", "elsewhere"), true},
+		{"nested below codebase", filepath.Join(base, "my-app", "sub"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkScorchTarget(base, tt.output); (err != nil) != tt.wantErr {
+				t.Errorf("checkScorchTarget(%q) error = %v, wantErr %v", tt.output, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+// Package reviewer runs the Phase 1 pipeline of generate-docs as a
+// library: it discovers the git repositories under a target directory,
+// analyzes them, and writes the LLM prompt to an output directory.
+//
+//	res, err := reviewer.Run(reviewer.Options{Target: "/src/app"})
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Println(res.PromptPath, len(res.Analyses))
+package reviewer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/bordenet/codebase-reviewer/internal/prompt"
+	"github.com/bordenet/codebase-reviewer/internal/scanner"
+	"github.com/bordenet/codebase-reviewer/pkg/logger"
+)
+
+// Types shared with the scanner and prompt generator.
+type (
+	// Repository is a discovered git repository.
+	Repository = scanner.Repository
+	// RepositoryAnalysis is the analysis of one repository.
+	RepositoryAnalysis = scanner.RepositoryAnalysis
+	// ScanOptions tunes repository discovery and analysis.
+	ScanOptions = scanner.ScanOptions
+	// PromptOptions controls analysis caching and prompt generation.
+	PromptOptions = prompt.Options
+	// Provider formats the prompt for an LLM (see ParseProvider).
+	Provider = prompt.Provider
+)
+
+// Errors returned by Run and its steps, wrapped with details; test for
+// them with errors.Is.
+var (
+	// ErrPathNotFound reports that the target or a repository does not
+	// exist.
+	ErrPathNotFound = scanner.ErrPathNotFound
+	// ErrNotReadable reports that the target exists but cannot be read.
+	ErrNotReadable = scanner.ErrNotReadable
+	// ErrNotRepo reports that a path given as a repository is not one.
+	ErrNotRepo = scanner.ErrNotRepo
+	// ErrNoRepos reports that no repository is left to review.
+	ErrNoRepos = scanner.ErrNoRepos
+)
+
+// ParseProvider returns the Provider for PromptOptions.Provider registered
+// under name: "generic", "claude", or "openai". An empty name selects the
+// generic provider.
+func ParseProvider(name string) (Provider, error) {
+	return prompt.ParseProvider(name)
+}
+
+// ParseFormats parses a comma-separated list of output formats for
+// PromptOptions.Formats, such as "md,yaml,json". An empty list yields the
+// default formats.
+func ParseFormats(list string) ([]string, error) {
+	return prompt.ParseFormats(list)
+}
+
+// ParseReport validates a machine-readable report name for
+// PromptOptions.Report: "json", or "" for none.
+func ParseReport(name string) (string, error) {
+	return prompt.ParseReport(name)
+}
+
+// Options configures Run. The zero value of every field but Target
+// selects the default behavior.
+type Options struct {
+	// PromptOptions tunes the analysis and the generated prompt; its Scan,
+	// Scorch, and DryRun fields also apply to discovery and the output
+	// directory.
+	PromptOptions
+	// Target is the directory to review.
+	Target string
+	// Repositories, when non-empty, are reviewed instead of the
+	// repositories discovered under Target.
+	Repositories []Repository
+	// Filter, if set, chooses which of the repositories to review. Run
+	// fails with ErrNoRepos when it keeps none.
+	Filter func([]Repository) ([]Repository, error)
+	// OutputBase is the absolute directory under which the output
+	// directory is created (see OutputDirName). Empty uses
+	// DefaultOutputBase.
+	OutputBase string
+	// Log receives progress messages. Nil discards them.
+	Log *logger.Logger
+}
+
+// logger returns the configured logger, or one that discards output.
+func (o Options) logger() *logger.Logger {
+	if o.Log != nil {
+		return o.Log
+	}
+	return logger.NewWithWriter(io.Discard, false)
+}
+
+// Result describes a review.
+type Result struct {
+	// Target is the absolute path of the reviewed directory.
+	Target string
+	// OutputDir is the directory holding the outputs.
+	OutputDir string
+	// Repositories are the repositories reviewed. Once the prompt is
+	// generated, those dropped as empty or too small are left out.
+	Repositories []Repository
+	// Analyses holds the analysis of each repository in the prompt.
+	Analyses []*RepositoryAnalysis
+	// PromptPath is the primary prompt file, or "" when none was
+	// generated.
+	PromptPath string
+	// Tokens is the estimated token count of the prompt, or 0 when none
+	// was generated.
+	Tokens int
+	// Fingerprint identifies the reviewed codebase state, or is "" when
+	// none was computed.
+	Fingerprint string
+	// ToolsExist is set when OutputDir already held Phase 2 tools, so Run
+	// left it alone; set Scorch to regenerate.
+	ToolsExist bool
+}
+
+// Run reviews opts.Target: it discovers the repositories under it,
+// prepares the output directory, then analyzes the repositories and
+// writes the prompt. An output directory that already holds Phase 2 tools
+// is left alone unless opts.Scorch is set.
+func Run(opts Options) (*Result, error) {
+	return RunContext(context.Background(), opts)
+}
+
+// RunContext is Run, stopping early with ctx.Err() when ctx is cancelled.
+func RunContext(ctx context.Context, opts Options) (*Result, error) {
+	res, err := Prepare(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.Scorch && ToolsExist(res.OutputDir) {
+		opts.logger().Info("Phase 2 tools already exist in %s; set Scorch to rebuild", res.OutputDir)
+		res.ToolsExist = true
+		return res, nil
+	}
+	if err := Generate(ctx, res, opts); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Prepare runs the first half of Run: it resolves the target, discovers
+// and filters its repositories, and prepares the output directory,
+// returning a Result without analyses for Generate to complete.
+func Prepare(ctx context.Context, opts Options) (*Result, error) {
+	log := opts.logger()
+	target, err := filepath.Abs(opts.Target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(target); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrPathNotFound, target)
+	}
+
+	repos := opts.Repositories
+	if len(repos) == 0 {
+		repos, err = Discover(ctx, target, opts.Scan, log)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Name the output directory before filtering, so leaving out the root
+	// repository does not move it.
+	dirName := OutputDirName(target, repos)
+	if opts.Filter != nil {
+		repos, err = opts.Filter(repos)
+		if err != nil {
+			return nil, err
+		}
+		if len(repos) == 0 {
+			return nil, fmt.Errorf("%w: every repository was filtered out", ErrNoRepos)
+		}
+	}
+
+	outputDir, err := PrepareOutputDir(dirName, opts.OutputBase, opts.Scorch, opts.DryRun, log)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Target: target, OutputDir: outputDir, Repositories: repos}, nil
+}
+
+// Generate runs the second half of Run on a Result from Prepare: it
+// analyzes res.Repositories and writes the prompt to res.OutputDir,
+// filling in res.Analyses, res.PromptPath, res.Tokens, and
+// res.Fingerprint.
+func Generate(ctx context.Context, res *Result, opts Options) error {
+	out, err := prompt.GenerateResult(ctx, res.Target, res.Repositories, res.OutputDir, opts.PromptOptions, opts.logger())
+	if err != nil {
+		return err
+	}
+	res.Repositories, res.Analyses, res.PromptPath, res.Tokens = out.Repositories, out.Analyses, out.PromptPath, out.Tokens
+	res.Fingerprint = out.Fingerprint
+	return nil
+}
+
+// Discover finds the git repositories under target. A target without any
+// is treated as a single codebase.
+func Discover(ctx context.Context, target string, opts ScanOptions, log *logger.Logger) ([]Repository, error) {
+	log.Info("Scanning for git repositories...")
+	repos, err := scanner.FindGitReposWithOptions(ctx, target, opts, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for repositories: %w", err)
+	}
+
+	if len(repos) == 0 {
+		log.Warn("No git repositories found in %s", target)
+		log.Info("Treating entire directory as single codebase")
+		return []Repository{{
+			Path:    target,
+			Name:    filepath.Base(target),
+			License: scanner.DetectLicense(target),
+		}}, nil
+	}
+
+	log.Info("Found %d git repositories", len(repos))
+	for _, repo := range repos {
+		log.Info("  - %s", repo.Name)
+	}
+	return repos, nil
+}
@@ -0,0 +1,188 @@
+package reviewer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeRepo creates a git repository named name under root holding files.
+func writeRepo(t *testing.T, root, name string, files map[string]string) string {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for rel, content := range files {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	root := t.TempDir()
+	writeRepo(t, root, "api", map[string]string{
+		"go.mod":      "module example.com/api\n\ngo 1.21\n",
+		"main.go":     "package main\n\nfunc main() {}\n",
+		"api/http.go": "package api\n",
+	})
+	writeRepo(t, root, "web", map[string]string{"index.ts": "export {}\n"})
+	base := t.TempDir()
+
+	res, err := Run(Options{Target: root, OutputBase: base})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if res.Target != root {
+		t.Errorf("Target = %q, want %q", res.Target, root)
+	}
+	if filepath.Dir(res.OutputDir) != base {
+		t.Errorf("OutputDir = %q, want a directory under %q", res.OutputDir, base)
+	}
+	if len(res.Repositories) != 2 || len(res.Analyses) != 2 {
+		t.Fatalf("got %d repositories and %d analyses, want 2 of each", len(res.Repositories), len(res.Analyses))
+	}
+	byName := make(map[string]*RepositoryAnalysis)
+	for _, a := range res.Analyses {
+		byName[a.Repository.Name] = a
+	}
+	if got := byName["api"].Languages["Go"]; got != 2 {
+		t.Errorf("api Languages[Go] = %d, want 2", got)
+	}
+	if got := byName["web"].Languages["TypeScript"]; got != 1 {
+		t.Errorf("web Languages[TypeScript] = %d, want 1", got)
+	}
+
+	if filepath.Dir(res.PromptPath) != res.OutputDir {
+		t.Errorf("PromptPath = %q, want a file in %q", res.PromptPath, res.OutputDir)
+	}
+	data, err := os.ReadFile(res.PromptPath)
+	if err != nil {
+		t.Fatalf("prompt not written: %v", err)
+	}
+	if !strings.Contains(string(data), root) {
+		t.Error("prompt does not mention the target")
+	}
+	if res.ToolsExist {
+		t.Error("ToolsExist = true for a fresh output directory")
+	}
+}
+
+func TestRun_RepositoriesAndFilter(t *testing.T) {
+	root := t.TempDir()
+	api := writeRepo(t, root, "api", map[string]string{"main.go": "package main\n"})
+	writeRepo(t, root, "web", map[string]string{"index.ts": "export {}\n"})
+	other := writeRepo(t, t.TempDir(), "lib", map[string]string{"lib.go": "package lib\n"})
+
+	var filtered []string
+	opts := Options{
+		Target:       root,
+		Repositories: []Repository{{Path: api, Name: "api"}, {Path: other, Name: "lib"}},
+		OutputBase:   t.TempDir(),
+		Filter: func(repos []Repository) ([]Repository, error) {
+			for _, r := range repos {
+				filtered = append(filtered, r.Name)
+			}
+			return repos[1:], nil
+		},
+	}
+	res, err := Run(opts)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if strings.Join(filtered, ",") != "api,lib" {
+		t.Errorf("Filter saw %v, want the given repositories [api lib]", filtered)
+	}
+	if len(res.Analyses) != 1 || res.Analyses[0].Repository.Name != "lib" {
+		t.Errorf("Analyses = %v, want only lib", res.Analyses)
+	}
+
+	opts.Filter = func([]Repository) ([]Repository, error) { return nil, nil }
+	if _, err := Run(opts); !errors.Is(err, ErrNoRepos) {
+		t.Errorf("Run() with everything filtered out error = %v, want ErrNoRepos", err)
+	}
+}
+
+func TestRun_ExistingToolsLeftAlone(t *testing.T) {
+	root := t.TempDir()
+	writeRepo(t, root, "api", map[string]string{"main.go": "package main\n"})
+	base := t.TempDir()
+	tools := filepath.Join(base, OutputDirName(root, nil), "phase2-tools")
+	if err := os.MkdirAll(tools, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Run(Options{Target: root, OutputBase: base})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !res.ToolsExist || res.PromptPath != "" || res.Analyses != nil {
+		t.Errorf("Run() = %+v, want ToolsExist and no prompt", res)
+	}
+
+	opts := Options{Target: root, OutputBase: base}
+	opts.Scorch = true
+	res, err = Run(opts)
+	if err != nil {
+		t.Fatalf("Run() with Scorch error = %v", err)
+	}
+	if res.ToolsExist || res.PromptPath == "" {
+		t.Errorf("Run() with Scorch = %+v, want a regenerated prompt", res)
+	}
+}
+
+func TestRun_DryRunWritesNothing(t *testing.T) {
+	root := t.TempDir()
+	writeRepo(t, root, "api", map[string]string{"main.go": "package main\n"})
+	base := filepath.Join(t.TempDir(), "out")
+
+	opts := Options{Target: root, OutputBase: base}
+	opts.DryRun = true
+	res, err := Run(opts)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(res.Analyses) != 1 || res.PromptPath == "" {
+		t.Errorf("Run() = %+v, want an analysis and a planned prompt path", res)
+	}
+	if _, err := os.Stat(base); !os.IsNotExist(err) {
+		t.Errorf("dry run created %s: %v", base, err)
+	}
+}
+
+func TestRunContext_Errors(t *testing.T) {
+	if _, err := Run(Options{Target: filepath.Join(t.TempDir(), "missing")}); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("Run() on a missing target error = %v, want ErrPathNotFound", err)
+	}
+
+	root := t.TempDir()
+	writeRepo(t, root, "api", map[string]string{"main.go": "package main\n"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := RunContext(ctx, Options{Target: root, OutputBase: t.TempDir()}); !errors.Is(err, context.Canceled) {
+		t.Errorf("RunContext() with a cancelled context error = %v, want context.Canceled", err)
+	}
+}
+
+func TestDiscover_TreatsPlainDirectoryAsOneCodebase(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repos, err := Discover(context.Background(), dir, ScanOptions{}, Options{}.logger())
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if len(repos) != 1 || repos[0].Path != dir || repos[0].Name != filepath.Base(dir) {
+		t.Errorf("Discover() = %+v, want the directory itself", repos)
+	}
+}
@@ -0,0 +1,11 @@
+// Package prompts embeds the default prompt templates so the generate-docs
+// binary works regardless of the working directory it is run from.
+package prompts
+
+import _ "embed"
+
+// Phase1Template is the default Phase 1 prompt template
+// (templates/phase1-prompt-template.yaml).
+//
+//go:embed templates/phase1-prompt-template.yaml
+var Phase1Template []byte